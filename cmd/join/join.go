@@ -0,0 +1,70 @@
+package join
+
+import (
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	"github.com/spf13/cobra"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	JoinCmd.Flags().StringVar(&mechFile, "mech", "", "mech file (random if not provided)")
+	JoinCmd.Flags().StringVar(&name, "name", "", "player name shown to other players")
+	JoinCmd.Flags().IntVar(&team, "team", -1, "team number, players on the same team are friendly (negative teams are friendly with mission allies)")
+}
+
+var (
+	mechFile string
+	name     string
+	team     int
+	JoinCmd  = &cobra.Command{
+		Use:   "join [ADDRESS]",
+		Short: "Join a multiplayer mission hosted with the server command",
+		Long: "Join a multiplayer mission hosted with the server command at the address as HOST or HOST:PORT.\n" +
+			"The port is " + strconv.Itoa(game.MULTIPLAYER_DEFAULT_PORT) + " if not provided.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			address := args[0]
+			if _, _, err := net.SplitHostPort(address); err != nil {
+				address = net.JoinHostPort(address, strconv.Itoa(game.MULTIPLAYER_DEFAULT_PORT))
+			}
+
+			g := game.NewGame()
+
+			// use the unit file specified, or random unit if not provided
+			if len(mechFile) == 0 {
+				mechs := g.Resources().GetMechResourceList()
+				mechFile = model.TrimExtension(mechs[model.RandIntn(len(mechs))].File)
+			} else if g.LoadUnit(model.MechResourceType, mechFile) == nil {
+				log.Error("Error loading mech file: ", mechFile)
+				unitList := make([]string, 0, len(g.Resources().Mechs))
+				for k := range g.Resources().Mechs {
+					unitList = append(unitList, k)
+				}
+				sort.Strings(unitList)
+				if len(unitList) > 0 {
+					log.Error("Mech files available:\n", strings.Join(unitList[:], "\n"))
+				}
+				os.Exit(1)
+			}
+
+			if err := g.JoinServer(address, name, mechFile, team); err != nil {
+				log.Error("Error joining server: ", address)
+				log.Error(err)
+				os.Exit(1)
+			}
+
+			// jump straight to the game scene
+			g.SetInitialSceneFunc(game.NewGameScene)
+
+			g.Run()
+		},
+	}
+)
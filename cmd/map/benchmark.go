@@ -0,0 +1,79 @@
+package mapcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	benchmarkCmd.Flags().Int64Var(&benchmarkSeed, "seed", 0, "random seed for the generated map and units")
+	benchmarkCmd.Flags().StringVar(&benchmarkSize, "size", "400x400", "generated map size in cells as WIDTHxHEIGHT")
+	benchmarkCmd.Flags().StringVar(&benchmarkBiome, "biome", "grassland", "generated map biome: "+strings.Join(model.MapBiomes(), ", "))
+	benchmarkCmd.Flags().IntVar(&benchmarkUnits, "units", 300, "number of units in the battle")
+	benchmarkCmd.Flags().UintVar(&benchmarkTicks, "ticks", 300, "number of ticks to simulate")
+}
+
+var (
+	benchmarkSeed  int64
+	benchmarkSize  string
+	benchmarkBiome string
+	benchmarkUnits int
+	benchmarkTicks uint
+	benchmarkCmd   = &cobra.Command{
+		Use:   "benchmark",
+		Short: "Benchmark a headless battle on a large generated map",
+		Long: "Simulate a battle between many units on a large generated map without a window or audio,\n" +
+			"then report the average update time per tick with and without the collision spatial grid.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var width, height int
+			if _, err := fmt.Sscanf(strings.ToLower(benchmarkSize), "%dx%d", &width, &height); err != nil {
+				log.Fatalf("invalid map size '%s', must be formatted as WIDTHxHEIGHT", benchmarkSize)
+			}
+			opts := model.MapGenerateOptions{
+				Seed:   benchmarkSeed,
+				Width:  width,
+				Height: height,
+				Biome:  benchmarkBiome,
+			}
+
+			fmt.Printf("Map: %dx%d %s (seed %d)\n", width, height, strings.ToLower(benchmarkBiome), benchmarkSeed)
+
+			results := make([]*game.BattleBenchmarkResult, 0, 2)
+			for _, spatialGrid := range []bool{false, true} {
+				// new game with the same seed for each run so both simulate the same battle
+				g := game.NewHeadlessGame(benchmarkSeed)
+
+				m, err := model.LoadGeneratedMap(opts)
+				if err != nil {
+					log.Fatal(err)
+				}
+				if _, err := g.LoadBattleBenchmark(m, benchmarkUnits); err != nil {
+					log.Fatal(err)
+				}
+				g.SetPlayerUnit(g.RandomUnit(model.MechResourceType))
+
+				result := g.BenchmarkBattle(benchmarkTicks, spatialGrid)
+				results = append(results, result)
+
+				label := "without spatial grid"
+				if spatialGrid {
+					label = "with spatial grid"
+				}
+				fmt.Printf(
+					"%-22s %d units, %d ticks: %v/tick (%0.1f TPS)\n",
+					label+":", result.Units, result.Ticks, result.TickDuration(), result.TPS(),
+				)
+			}
+
+			if results[1].Duration > 0 {
+				fmt.Printf("Speedup: %0.2fx\n", float64(results[0].Duration)/float64(results[1].Duration))
+			}
+		},
+	}
+)
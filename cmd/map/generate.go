@@ -0,0 +1,81 @@
+package mapcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	generateCmd.Flags().StringVarP(&outMapPath, "output", "o", "", "[required] output map yaml file path")
+	generateCmd.Flags().Int64Var(&generateSeed, "seed", 0, "random seed, the same seed and options always generate the same map (default random)")
+	generateCmd.Flags().StringVar(&generateSize, "size", "200x200", "map size in cells as WIDTHxHEIGHT (50 cells per km)")
+	generateCmd.Flags().StringVar(&generateBiome, "biome", "grassland", "map biome: "+strings.Join(model.MapBiomes(), ", "))
+	generateCmd.MarkFlagRequired("output")
+}
+
+var (
+	outMapPath    string
+	generateSeed  int64
+	generateSize  string
+	generateBiome string
+	generateCmd   = &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a random map file",
+		Long: "Procedurally generate a complete map file with wall clusters, flooring paths, clutter, forests,\n" +
+			"spawn points and a drop zone, where every spawn point can be reached from the drop zone.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if !cmd.Flags().Changed("seed") {
+				generateSeed = time.Now().UnixNano()
+			}
+
+			var width, height int
+			if _, err := fmt.Sscanf(strings.ToLower(generateSize), "%dx%d", &width, &height); err != nil {
+				log.Fatalf("invalid map size '%s', must be formatted as WIDTHxHEIGHT", generateSize)
+			}
+
+			opts := model.MapGenerateOptions{
+				Seed:   generateSeed,
+				Width:  width,
+				Height: height,
+				Biome:  generateBiome,
+			}
+			m, err := model.GenerateMap(opts)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			mapYaml, err := model.MarshalMapYAML(m)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			// expand tilde as home directory
+			if strings.HasPrefix(outMapPath, "~/") {
+				dirname, _ := os.UserHomeDir()
+				outMapPath = filepath.Join(dirname, outMapPath[2:])
+			}
+
+			if err := os.MkdirAll(filepath.Dir(outMapPath), 0755); err != nil {
+				log.Fatal(err)
+			}
+
+			header := fmt.Sprintf(
+				"# Generated map: pixelmek-3d map generate --seed %d --size %dx%d --biome %s\n",
+				opts.Seed, opts.Width, opts.Height, strings.ToLower(opts.Biome),
+			)
+			if err := os.WriteFile(outMapPath, append([]byte(header), mapYaml...), 0644); err != nil {
+				log.Fatal(err)
+			}
+
+			log.Infof("Map generated: %s", outMapPath)
+		},
+	}
+)
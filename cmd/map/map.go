@@ -13,6 +13,8 @@ import (
 func init() {
 	MapCmd.AddCommand(launchCmd)
 	MapCmd.AddCommand(imageCmd)
+	MapCmd.AddCommand(generateCmd)
+	MapCmd.AddCommand(benchmarkCmd)
 
 	MapCmd.Flags().BoolVar(&listMaps, "list", false, "lists all map files")
 }
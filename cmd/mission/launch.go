@@ -2,8 +2,10 @@ package mission
 
 import (
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/pixelmek-3d/pixelmek-3d/game"
 	"github.com/pixelmek-3d/pixelmek-3d/game/model"
@@ -14,11 +16,15 @@ import (
 
 func init() {
 	launchCmd.Flags().StringVar(&mechFile, "mech", "", "mech file")
+	launchCmd.Flags().StringVar(&recordFile, "record", "", "record the mission to a replay file")
+	launchCmd.Flags().Int64Var(&recordSeed, "seed", 0, "random seed for the recorded mission (random if not provided)")
 }
 
 var (
-	mechFile  string
-	launchCmd = &cobra.Command{
+	mechFile   string
+	recordFile string
+	recordSeed int64
+	launchCmd  = &cobra.Command{
 		Use:   "launch [MISSION_FILE]",
 		Short: "Launch game directly into a mission",
 		Args:  cobra.ExactArgs(1),
@@ -60,6 +66,23 @@ var (
 				}
 			}
 
+			if len(recordFile) > 0 {
+				// expand tilde as home directory
+				if strings.HasPrefix(recordFile, "~/") {
+					dirname, _ := os.UserHomeDir()
+					recordFile = filepath.Join(dirname, recordFile[2:])
+				}
+
+				if !cmd.Flags().Changed("seed") {
+					recordSeed = time.Now().UnixNano()
+				}
+
+				if err := g.RecordReplay(recordFile, missionFile, recordSeed); err != nil {
+					log.Error("Error recording replay: ", err)
+					os.Exit(1)
+				}
+			}
+
 			// jump straight to the game scene
 			g.SetInitialSceneFunc(game.NewGameScene)
 
@@ -0,0 +1,37 @@
+package mission
+
+import (
+	"os"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game"
+	"github.com/spf13/cobra"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var loadCmd = &cobra.Command{
+	Use:   "load [SAVE_FILE]",
+	Short: "Continue a mission in progress from a saved game",
+	Long: "Continue a mission in progress from a saved game.\n" +
+		"If no saved game file is given, the last game saved from the in-game menu is loaded.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		g := game.NewGame()
+
+		saveFile := game.QuickSavePath()
+		if len(args) > 0 {
+			saveFile = args[0]
+		}
+
+		if err := g.LoadSaveGame(saveFile); err != nil {
+			log.Error("Error loading saved game: ", saveFile)
+			log.Error(err)
+			os.Exit(1)
+		}
+
+		// jump straight to the game scene
+		g.SetInitialSceneFunc(game.NewGameScene)
+
+		g.Run()
+	},
+}
@@ -13,6 +13,10 @@ import (
 func init() {
 	MissionCmd.AddCommand(launchCmd)
 	MissionCmd.AddCommand(imageCmd)
+	MissionCmd.AddCommand(loadCmd)
+	MissionCmd.AddCommand(replayCmd)
+	MissionCmd.AddCommand(simulateCmd)
+	MissionCmd.AddCommand(validateCmd)
 
 	MissionCmd.Flags().BoolVar(&listMissions, "list", false, "lists all mission files")
 }
@@ -0,0 +1,36 @@
+package mission
+
+import (
+	"os"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game"
+	"github.com/spf13/cobra"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay [REPLAY_FILE]",
+	Short: "Play back a mission replay recorded with launch --record",
+	Long: "Play back a mission replay recorded with launch --record.\n" +
+		"Playback controls use the configured key bindings:\n" +
+		"  power_toggle  pause or resume playback\n" +
+		"  up/down       increase or decrease playback speed\n" +
+		"  camera_cycle  cycle the camera through units in play, then back to the player unit",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		replayFile := args[0]
+
+		g := game.NewGame()
+		if err := g.LoadReplay(replayFile); err != nil {
+			log.Error("Error loading replay file: ", replayFile)
+			log.Error(err)
+			os.Exit(1)
+		}
+
+		// jump straight to the game scene
+		g.SetInitialSceneFunc(game.NewGameScene)
+
+		g.Run()
+	},
+}
@@ -0,0 +1,82 @@
+package mission
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	"github.com/spf13/cobra"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	simulateCmd.Flags().Int64Var(&simulateSeed, "seed", 0, "random seed, the same seed gives the same result")
+	simulateCmd.Flags().StringVar(&simulateMechFile, "mech", "", "player mech file (random if not provided)")
+	simulateCmd.Flags().Float64Var(&simulateMaxSeconds, "max-seconds", 600, "maximum mission time to simulate in seconds (0 for no limit)")
+}
+
+var (
+	simulateSeed       int64
+	simulateMechFile   string
+	simulateMaxSeconds float64
+	simulateCmd        = &cobra.Command{
+		Use:   "simulate [MISSION_FILE]",
+		Short: "Simulate a mission headless with the player unit piloted by AI",
+		Long: "Simulate a mission without a window or audio, with the player unit piloted by AI, " +
+			"then report the final objectives status.\n" +
+			"Exits with code 0 if objectives completed, 1 if failed, or 2 if still in progress after max seconds.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			missionFile = args[0]
+
+			g := game.NewHeadlessGame(simulateSeed)
+			m, err := g.LoadMission(missionFile)
+			if err != nil {
+				log.Error("Error loading mission file: ", missionFile)
+				log.Error(err)
+
+				missionList, _ := model.ListMissionFilenames()
+				if len(missionList) > 0 {
+					log.Error("Mission files available:\n", strings.Join(missionList[:], "\n"))
+				}
+				os.Exit(1)
+			}
+
+			var unit model.Unit
+			if len(simulateMechFile) == 0 {
+				unit = g.RandomUnit(model.MechResourceType)
+			} else {
+				unit = g.LoadUnit(model.MechResourceType, simulateMechFile)
+				if unit == nil {
+					log.Error("Error loading mech file: ", simulateMechFile)
+					os.Exit(1)
+				}
+			}
+			g.SetPlayerUnit(unit)
+
+			maxTicks := uint(simulateMaxSeconds * model.TICKS_PER_SECOND)
+
+			log.Debug("simulating mission ", missionFile, "...")
+			result := g.Simulate(maxTicks)
+
+			fmt.Printf("Mission: %s (%s)\n", m.Title, missionFile)
+			fmt.Printf("Seed: %d\n", simulateSeed)
+			fmt.Printf("Player: %s %s\n", unit.Name(), unit.Variant())
+			fmt.Printf("Time: %0.1fs (%d ticks)\n", result.Seconds, result.Ticks)
+			fmt.Printf("Status: %s\n", result.Status)
+			fmt.Printf("\n%s", result.Objectives)
+
+			switch result.Status {
+			case game.OBJECTIVES_COMPLETED:
+				os.Exit(0)
+			case game.OBJECTIVES_FAILED:
+				os.Exit(1)
+			default:
+				os.Exit(2)
+			}
+		},
+	}
+)
@@ -0,0 +1,74 @@
+package mission
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	"github.com/pixelmek-3d/pixelmek-3d/game/resources"
+	"github.com/spf13/cobra"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	validateCmd.Flags().BoolVar(&validateAll, "all", false, "validate all mission files")
+}
+
+var (
+	validateAll bool
+	validateCmd = &cobra.Command{
+		Use:   "validate [MISSION_FILE|--all]",
+		Short: "Validate mission file references, unit resources, and positions",
+		Long: "Validate mission files for dangling unit ID and nav point references, duplicate unit IDs, " +
+			"unit files missing from model resources, positions outside of map bounds, and patrol points inside walls.\n" +
+			"Exits with a non-zero code if any problems are found.",
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if !validateAll && len(args) == 0 {
+				cmd.Help()
+				os.Exit(1)
+			}
+
+			resources.InitResources()
+
+			var missionFiles []string
+			if validateAll {
+				var err error
+				missionFiles, err = model.ListMissionFilenames()
+				if err != nil {
+					log.Fatal(err)
+				}
+			} else {
+				missionFiles = []string{args[0]}
+			}
+
+			res, err := model.LoadModelResources()
+			if err != nil {
+				log.Error("error loading model resources: ", err)
+				os.Exit(1)
+			}
+
+			numProblems := 0
+			for _, missionFile := range missionFiles {
+				m, err := model.LoadMission(missionFile)
+				if err != nil {
+					fmt.Printf("[missions/%s] failed to load: %v\n", missionFile, err)
+					numProblems++
+					continue
+				}
+
+				for _, e := range m.Validate(missionFile, res) {
+					fmt.Println(e.Error())
+					numProblems++
+				}
+			}
+
+			if numProblems > 0 {
+				fmt.Printf("%d problem(s) found in %d mission file(s)\n", numProblems, len(missionFiles))
+				os.Exit(1)
+			}
+			fmt.Printf("no problems found in %d mission file(s)\n", len(missionFiles))
+		},
+	}
+)
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"github.com/pixelmek-3d/pixelmek-3d/cmd/join"
 	mapcmd "github.com/pixelmek-3d/pixelmek-3d/cmd/map"
 	"github.com/pixelmek-3d/pixelmek-3d/cmd/mission"
+	"github.com/pixelmek-3d/pixelmek-3d/cmd/server"
 	"github.com/pixelmek-3d/pixelmek-3d/cmd/unit"
 	"github.com/pixelmek-3d/pixelmek-3d/cmd/weapon"
 	"github.com/pixelmek-3d/pixelmek-3d/game"
@@ -14,6 +16,8 @@ import (
 func init() {
 	rootCmd.AddCommand(mapcmd.MapCmd)
 	rootCmd.AddCommand(mission.MissionCmd)
+	rootCmd.AddCommand(server.ServerCmd)
+	rootCmd.AddCommand(join.JoinCmd)
 	rootCmd.AddCommand(unit.UnitCmd)
 	rootCmd.AddCommand(weapon.WeaponCmd)
 }
@@ -54,4 +58,8 @@ func init() {
 	rootCmd.PersistentFlags().Bool(game.PARAM_KEY_DEBUG, false, "developer debug mode")
 	globalViper.BindPFlag(game.PARAM_KEY_DEBUG, rootCmd.PersistentFlags().Lookup(game.PARAM_KEY_DEBUG))
 	globalViper.SetDefault(game.PARAM_KEY_DEBUG, false)
+
+	rootCmd.PersistentFlags().Bool(game.PARAM_KEY_AI_TRACE, false, "developer AI behavior tree trace file")
+	globalViper.BindPFlag(game.PARAM_KEY_AI_TRACE, rootCmd.PersistentFlags().Lookup(game.PARAM_KEY_AI_TRACE))
+	globalViper.SetDefault(game.PARAM_KEY_AI_TRACE, false)
 }
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	"github.com/spf13/cobra"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	ServerCmd.Flags().IntVar(&port, "port", game.MULTIPLAYER_DEFAULT_PORT, "TCP port to listen on")
+	ServerCmd.Flags().IntVar(&players, "players", 1, "number of players to wait for before the mission starts")
+	ServerCmd.Flags().Int64Var(&seed, "seed", 0, "random seed for the mission (random if not provided)")
+}
+
+var (
+	port      int
+	players   int
+	seed      int64
+	ServerCmd = &cobra.Command{
+		Use:   "server [MISSION_FILE]",
+		Short: "Host a multiplayer mission for players to join",
+		Long: "Host a multiplayer mission without a window, running the mission for all players who join it " +
+			"with the join command.\n" +
+			"The mission starts once the number of players have joined, and ends when the objectives are " +
+			"completed or failed or all players have left.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			missionFile := args[0]
+
+			if !cmd.Flags().Changed("seed") {
+				seed = time.Now().UnixNano()
+			}
+
+			g := game.NewHeadlessGame(seed)
+			m, err := g.LoadMission(missionFile)
+			if err != nil {
+				log.Error("Error loading mission file: ", missionFile)
+				log.Error(err)
+
+				missionList, _ := model.ListMissionFilenames()
+				if len(missionList) > 0 {
+					log.Error("Mission files available:\n", strings.Join(missionList[:], "\n"))
+				}
+				os.Exit(1)
+			}
+
+			// the server player unit is only an observer of the mission
+			g.SetPlayerUnit(g.RandomUnit(model.MechResourceType))
+
+			address := net.JoinHostPort("", strconv.Itoa(port))
+			result, err := g.Serve(address, players, seed)
+			if err != nil {
+				log.Error("Error hosting mission: ", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Mission: %s (%s)\n", m.Title, missionFile)
+			fmt.Printf("Time: %0.1fs (%d ticks)\n", result.Seconds, result.Ticks)
+			fmt.Printf("Status: %s\n", result.Status)
+			fmt.Printf("\n%s", result.Objectives)
+		},
+	}
+)
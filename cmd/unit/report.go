@@ -0,0 +1,287 @@
+package unit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	reportCmd.Flags().StringVarP(&reportOutPath, "output", "o", "", "[required] report output file path")
+	reportCmd.Flags().StringVarP(&reportFormat, "format", "f", "", "report format: csv, json or md (default from output file extension, or csv)")
+	reportCmd.MarkFlagRequired("output")
+}
+
+const (
+	NAME          = "Name"
+	VARIANT       = "Variant"
+	FILE          = "File"
+	TYPE          = "Type"
+	TONS          = "Tons"
+	SPEED         = "Speed"
+	ARMOR         = "Armor"
+	STRUCTURE     = "Structure"
+	JUMP_JETS     = "Jump Jets"
+	ALPHA_DAMAGE  = "Alpha Damage"
+	DPS           = "DPS"
+	HEAT_PS       = "Heat/s"
+	DISSIPATION   = "Dissipation/s"
+	MAX_RANGE     = "Max Range"
+	AMMO_DURATION = "Ammo Duration"
+)
+
+var headers = []string{
+	NAME, VARIANT, FILE, TYPE, TONS, SPEED, ARMOR, STRUCTURE, JUMP_JETS,
+	ALPHA_DAMAGE, DPS, HEAT_PS, DISSIPATION, MAX_RANGE, AMMO_DURATION,
+}
+
+// unitReportRow is the statistics of a single unit, with JSON keys used for the json report format
+type unitReportRow struct {
+	Name      string  `json:"name"`
+	Variant   string  `json:"variant"`
+	File      string  `json:"file"`
+	Type      string  `json:"type"`
+	Tonnage   float64 `json:"tonnage"`
+	Speed     float64 `json:"speed_kph"`
+	Armor     float64 `json:"armor"`
+	Structure float64 `json:"structure"`
+	JumpJets  int     `json:"jump_jets"`
+	// derived from the unit armament
+	AlphaDamage float64 `json:"alpha_damage"`
+	DPS         float64 `json:"dps"`
+	HeatPS      float64 `json:"heat_per_second"`
+	Dissipation float64 `json:"dissipation_per_second"`
+	MaxRange    float64 `json:"max_range"`
+	// seconds of sustained fire until the first ammo bin runs out, omitted for units that do not use ammo
+	AmmoDuration *float64 `json:"ammo_duration,omitempty"`
+}
+
+func (r *unitReportRow) data() map[string]string {
+	ammoDuration := ""
+	if r.AmmoDuration != nil {
+		ammoDuration = floatString(*r.AmmoDuration)
+	}
+	return map[string]string{
+		NAME:          r.Name,
+		VARIANT:       r.Variant,
+		FILE:          r.File,
+		TYPE:          r.Type,
+		TONS:          floatString(r.Tonnage),
+		SPEED:         floatString(r.Speed),
+		ARMOR:         floatString(r.Armor),
+		STRUCTURE:     floatString(r.Structure),
+		JUMP_JETS:     fmt.Sprintf("%d", r.JumpJets),
+		ALPHA_DAMAGE:  floatString(r.AlphaDamage),
+		DPS:           floatString(r.DPS),
+		HEAT_PS:       floatString(r.HeatPS),
+		DISSIPATION:   floatString(r.Dissipation),
+		MAX_RANGE:     floatString(r.MaxRange),
+		AMMO_DURATION: ammoDuration,
+	}
+}
+
+func unitRowData(unitType, file string, u model.Unit) *unitReportRow {
+	row := &unitReportRow{
+		Name:        u.Name(),
+		Variant:     u.Variant(),
+		File:        file,
+		Type:        unitType,
+		Tonnage:     u.Tonnage(),
+		Speed:       u.MaxVelocity() * model.VELOCITY_TO_KPH,
+		Armor:       u.MaxArmorPoints(),
+		Structure:   u.MaxStructurePoints(),
+		JumpJets:    u.JumpJets(),
+		Dissipation: u.HeatDissipation() * model.TICKS_PER_SECOND,
+	}
+
+	// ammo rounds consumed per second of sustained fire from each ammo bin
+	ammoUsage := make(map[*model.AmmoBin]float64)
+	for _, w := range u.Armament() {
+		row.AlphaDamage += w.Damage()
+		row.DPS += w.Damage() / w.MaxCooldown()
+		row.HeatPS += w.Heat() / w.MaxCooldown()
+		row.MaxRange = math.Max(row.MaxRange, w.Distance())
+
+		if ammoBin := w.AmmoBin(); ammoBin != nil && ammoBin.AmmoMax() > 0 {
+			// same ammo consumption per shot as when fired in game
+			ammoPerShot := float64(w.ProjectileCount())
+			if ammoBin.AmmoType() == model.AMMO_BALLISTIC {
+				ammoPerShot = 1
+			}
+			ammoUsage[ammoBin] += ammoPerShot / w.MaxCooldown()
+		}
+	}
+
+	for ammoBin, perSecond := range ammoUsage {
+		duration := float64(ammoBin.AmmoMax()) / perSecond
+		if row.AmmoDuration == nil || duration < *row.AmmoDuration {
+			row.AmmoDuration = &duration
+		}
+	}
+
+	return row
+}
+
+var (
+	reportOutPath string
+	reportFormat  string
+	reportCmd     = &cobra.Command{
+		Use:   "report",
+		Short: "Create unit statistics report",
+		Long: "Create a report of the statistics of every unit resource, including metrics derived from the unit armament.\n" +
+			"Speed is in kph, range in meters and ammo duration is the seconds of sustained fire until the first ammo bin is empty.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			// expand tilde as home directory
+			if strings.HasPrefix(reportOutPath, "~/") {
+				dirname, _ := os.UserHomeDir()
+				reportOutPath = filepath.Join(dirname, reportOutPath[2:])
+			}
+
+			format := strings.ToLower(reportFormat)
+			if format == "" {
+				format = strings.TrimPrefix(strings.ToLower(filepath.Ext(reportOutPath)), ".")
+			}
+			switch format {
+			case "csv", "json", "md":
+			case "markdown":
+				format = "md"
+			default:
+				if reportFormat != "" {
+					log.Fatalf("unknown report format '%s', must be one of: [csv, json, md]", reportFormat)
+				}
+				format = "csv"
+			}
+
+			// initialize game resources without running the actual game loop
+			g := game.NewHeadlessGame(0)
+			r := g.Resources()
+
+			// gather unit data for each row, by unit type
+			unitFiles := make(map[string][]string)
+			for _, m := range r.GetMechResourceList() {
+				unitFiles[model.MechResourceType] = append(unitFiles[model.MechResourceType], m.File)
+			}
+			for _, v := range r.GetVehicleResourceList() {
+				unitFiles[model.VehicleResourceType] = append(unitFiles[model.VehicleResourceType], v.File)
+			}
+			for _, v := range r.GetVTOLResourceList() {
+				unitFiles[model.VTOLResourceType] = append(unitFiles[model.VTOLResourceType], v.File)
+			}
+			for _, i := range r.GetInfantryResourceList() {
+				unitFiles[model.InfantryResourceType] = append(unitFiles[model.InfantryResourceType], i.File)
+			}
+			for _, e := range r.GetEmplacementResourceList() {
+				unitFiles[model.EmplacementResourceType] = append(unitFiles[model.EmplacementResourceType], e.File)
+			}
+
+			rows := make([]*unitReportRow, 0, 64)
+			unitTypes := []string{
+				model.MechResourceType, model.VehicleResourceType, model.VTOLResourceType,
+				model.InfantryResourceType, model.EmplacementResourceType,
+			}
+			for _, unitType := range unitTypes {
+				for _, file := range unitFiles[unitType] {
+					u := g.LoadUnit(unitType, model.TrimExtension(file))
+					if u == nil {
+						log.Errorf("unable to load %s unit %s", unitType, file)
+						continue
+					}
+					rows = append(rows, unitRowData(unitType, file, u))
+				}
+			}
+
+			if err := os.MkdirAll(filepath.Dir(reportOutPath), 0755); err != nil {
+				log.Fatal(err)
+			}
+
+			// create report file
+			file, err := os.Create(reportOutPath)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer file.Close()
+
+			switch format {
+			case "json":
+				err = writeJSONReport(file, rows)
+			case "md":
+				err = writeMarkdownReport(file, rows)
+			default:
+				err = writeCSVReport(file, rows)
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			log.Infof("Unit report created: %s", file.Name())
+		},
+	}
+)
+
+func writeCSVReport(w io.Writer, rows []*unitReportRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		record := r.data()
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = record[header]
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeJSONReport(w io.Writer, rows []*unitReportRow) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
+
+func writeMarkdownReport(w io.Writer, rows []*unitReportRow) error {
+	separators := make([]string, len(headers))
+	for i := range headers {
+		separators[i] = "---"
+	}
+
+	lines := make([]string, 0, 2+len(rows))
+	lines = append(lines, "| "+strings.Join(headers, " | ")+" |")
+	lines = append(lines, "| "+strings.Join(separators, " | ")+" |")
+	for _, r := range rows {
+		record := r.data()
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			// escape pipes so names cannot break the table columns
+			row[i] = strings.ReplaceAll(record[header], "|", `\|`)
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+	}
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func floatString(f float64) string {
+	s := fmt.Sprintf("%0.2f", f)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(s, "0")
+		s = strings.TrimRight(s, ".")
+	}
+	return s
+}
@@ -12,6 +12,7 @@ import (
 
 func init() {
 	UnitCmd.AddCommand(animationCmd)
+	UnitCmd.AddCommand(reportCmd)
 
 	UnitCmd.Flags().BoolVar(&listUnits, "list", false, "lists all unit files")
 }
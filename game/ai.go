@@ -6,8 +6,8 @@ import (
 	"math"
 	"path"
 	"path/filepath"
-	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -27,6 +27,7 @@ type AIHandler struct {
 	g          *Game
 	ai         []*AIBehavior
 	formations []*AIFormation
+	lancemates *AIFormation
 	initiative *AIInitiative
 	resources  AIResources
 }
@@ -38,24 +39,33 @@ type AIBehavior struct {
 	gunnery       *AIGunnery
 	piloting      *AIPiloting
 	rng           *model.Rand
+	order         *AIOrder
 	newInitiative bool
+
+	// number of times the behavior tree has been ticked, for decorators to know if their child was ticked last time
+	ticks uint
+	// status of each node from the last tick, only recorded while the AI is being traced for debugging
+	trace *AITrace
 }
 
 type AIGunnery struct {
+	pilot           *model.Pilot
 	rangeBrackets   *AIRangeBrackets
 	targetLeadPos   *geom.Vector2
 	ticksSinceFired uint
 }
 
 type AIPiloting struct {
+	pilot          *model.Pilot
 	pathing        *AIPathing
 	formation      *AIFormation
 	ticksSinceEval uint
 }
 
 type AIRangeBrackets struct {
-	lower map[model.Weapon]float64
-	upper map[model.Weapon]float64
+	weapons []model.Weapon
+	lower   map[model.Weapon]float64
+	upper   map[model.Weapon]float64
 }
 
 type AIPathing struct {
@@ -66,6 +76,48 @@ type AIPathing struct {
 type AIFormation struct {
 	leader model.Unit
 	units  []model.Unit
+
+	// lance name, shape, spacing and slots are only set for formations of mission lances
+	lance   string
+	shape   string
+	spacing float64
+	slots   map[model.Unit]int
+}
+
+// AIOrder is the current order given by the player to a lancemate
+type AIOrder struct {
+	command  AICommand
+	target   model.Unit
+	position *geom.Vector2
+	holdFire bool
+}
+
+type AICommand int
+
+const (
+	AI_COMMAND_ATTACK_TARGET AICommand = iota
+	AI_COMMAND_FORM_ON_ME
+	AI_COMMAND_HOLD_POSITION
+	AI_COMMAND_NAV_POINT
+	AI_COMMAND_CEASE_FIRE
+	AI_COMMAND_COUNT
+)
+
+func (c AICommand) String() string {
+	switch c {
+	case AI_COMMAND_ATTACK_TARGET:
+		return "Attack My Target"
+	case AI_COMMAND_FORM_ON_ME:
+		return "Form On Me"
+	case AI_COMMAND_HOLD_POSITION:
+		return "Hold Position"
+	case AI_COMMAND_NAV_POINT:
+		return "Go To Nav Point"
+	case AI_COMMAND_CEASE_FIRE:
+		return "Cease Fire"
+	default:
+		return fmt.Sprintf("undefined AICommand: %d", c)
+	}
 }
 
 type AINodeID string
@@ -88,24 +140,6 @@ func (t AINodeType) String() string {
 	}
 }
 
-type AIDecoratorType int
-
-const (
-	AI_DECORATOR_NONE AIDecoratorType = iota
-	AI_DECORATOR_NEGATE
-)
-
-func (t AIDecoratorType) String() string {
-	switch t {
-	case AI_DECORATOR_NONE:
-		return ""
-	case AI_DECORATOR_NEGATE:
-		return "negate"
-	default:
-		return fmt.Sprintf("undefined AIDecoratorType: %d", t)
-	}
-}
-
 type AIResources struct {
 	Trees map[string]AIResourceTree
 }
@@ -125,8 +159,50 @@ type AIResourceNode struct {
 	Properties AIResourceProperties `json:"properties"`
 }
 
+// AIResourceProperties are the node properties from the tree json, where the type property is
+// used by the loader and any others are parameters for the action or decorator of the node
 type AIResourceProperties struct {
-	Type AINodeType
+	Type   AINodeType
+	Params map[string]any
+}
+
+// Unmarshals node properties, separating the type from node parameters
+func (p *AIResourceProperties) UnmarshalJSON(b []byte) error {
+	params := make(map[string]any)
+	if err := json.Unmarshal(b, &params); err != nil {
+		return err
+	}
+
+	p.Type = AI_NODE_DEFAULT
+	if nodeType, ok := params["type"]; ok {
+		if err := p.Type.UnmarshalText([]byte(fmt.Sprint(nodeType))); err != nil {
+			return err
+		}
+		delete(params, "type")
+	}
+	p.Params = params
+	return nil
+}
+
+// Float returns the node parameter as a number, or the default value if it is not set
+func (p AIResourceProperties) Float(key string, defaultValue float64) (float64, error) {
+	v, ok := p.Params[key]
+	if !ok {
+		return defaultValue, nil
+	}
+	switch value := v.(type) {
+	case float64:
+		return value, nil
+	case string:
+		// the behavior tree editor may export property values as strings
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return defaultValue, fmt.Errorf("node parameter '%s' must be a number: %v", key, v)
+		}
+		return f, nil
+	default:
+		return defaultValue, fmt.Errorf("node parameter '%s' must be a number: %v", key, v)
+	}
 }
 
 // Unmarshals into AINodeType
@@ -205,6 +281,7 @@ func NewAIHandler(g *Game) *AIHandler {
 	}
 	if len(units) > 0 {
 		aiHandler.LoadFormations()
+		aiHandler.LoadLancemates()
 		aiHandler.initiative.roll()
 	}
 
@@ -212,20 +289,30 @@ func NewAIHandler(g *Game) *AIHandler {
 }
 
 func (h *AIHandler) NewUnitAI(u model.Unit) *AIBehavior {
+	pilot := h.unitPilot(u)
 	a := &AIBehavior{
 		g:        h.g,
 		u:        u,
-		gunnery:  NewAIGunnery(u),
-		piloting: NewAIPiloting(u),
+		gunnery:  NewAIGunnery(u, pilot),
+		piloting: NewAIPiloting(u, pilot),
 		rng:      model.NewRNG(),
 	}
 	a.gunnery.Reset()
 	a.piloting.Reset()
-	a.Node = a.LoadBehaviorTree("unit", h.resources)
+
+	tree := "unit"
+	if h.g.mission != nil && u.Team() < 0 && h.g.mission.IsLancemate(u.ID()) {
+		// player lancemates start following the player until given other orders
+		tree = "lancemate"
+		a.order = &AIOrder{command: AI_COMMAND_FORM_ON_ME}
+	}
+	a.Node = a.LoadBehaviorTree(tree, h.resources)
 
 	h.Add(a)
 	if h.g.debug {
-		fmt.Printf("--- %s\n%s\n", u.ID(), a.Node)
+		var tree strings.Builder
+		aiTreePrinter.Fprint(&tree, a.Node)
+		fmt.Printf("--- %s [%s]\n%s\n", u.ID(), pilot, tree.String())
 	}
 	return a
 }
@@ -236,7 +323,7 @@ func (h *AIHandler) Add(a *AIBehavior) {
 }
 
 func (h *AIHandler) UnitAI(u model.Unit) *AIBehavior {
-	if u == nil {
+	if h == nil || u == nil {
 		return nil
 	}
 	for _, ai := range h.ai {
@@ -284,21 +371,75 @@ func (h *AIHandler) LoadFormations() {
 			continue
 		}
 
-		if ai.piloting.formation != nil {
-			log.Errorf("[%s] is a leader of another formation and cannot also follow: %s", ai.u.ID(), leaderID)
+		h.joinFormation(ai, leaderAI)
+	}
+
+	// mission lances keep their members in formation slots
+	if h.g.mission == nil {
+		return
+	}
+	for _, lance := range h.g.mission.Lances {
+		leaderAI, ok := unitAiByID[lance.Leader]
+		if !ok || leaderAI.piloting.formation == nil {
+			continue
+		}
+		leaderAI.piloting.formation.initLance(lance, unitAiByID)
+	}
+}
+
+// joinFormation adds the unit AI to the formation of the leader, creating the formation if needed
+func (h *AIHandler) joinFormation(ai, leaderAI *AIBehavior) {
+	if ai.piloting.formation != nil {
+		log.Errorf("[%s] is a leader of another formation and cannot also follow: %s", ai.u.ID(), leaderAI.u.ID())
+		return
+	}
+
+	leaderFormation := leaderAI.piloting.formation
+	if leaderFormation == nil {
+		leaderFormation = &AIFormation{leader: leaderAI.u, units: make([]model.Unit, 0, 1)}
+		leaderAI.piloting.formation = leaderFormation
+		h.formations = append(h.formations, leaderFormation)
+	}
+
+	ai.piloting.formation = leaderFormation
+	leaderFormation.AddUnit(ai.u)
+}
+
+// AddReinforcementAI attaches AI to units added after the mission started, such as from a mission trigger
+func (h *AIHandler) AddReinforcementAI(units []model.Unit) {
+	for _, u := range units {
+		h.NewUnitAI(u)
+	}
+
+	// reinforcements can follow any unit still in play as formation leader
+	for _, u := range units {
+		leaderID := u.GuardUnit()
+		if leaderID == "" {
 			continue
 		}
 
-		leaderFormation := leaderAI.piloting.formation
-		if leaderFormation == nil {
-			leaderFormation = &AIFormation{leader: leaderAI.u, units: make([]model.Unit, 0, 1)}
-			leaderAI.piloting.formation = leaderFormation
-			h.formations = append(h.formations, leaderFormation)
+		leaderAI := h.UnitAI(h.g.getSpriteUnitByID(leaderID))
+		if leaderAI == nil {
+			log.Errorf("[%s] formation leader not found by ID: %s", u.ID(), leaderID)
+			continue
 		}
+		h.joinFormation(h.UnitAI(u), leaderAI)
+	}
+}
 
-		ai.piloting.formation = leaderFormation
-		leaderFormation.AddUnit(ai.u)
+// SetUnitBehaviorTree changes the behavior tree of the unit AI, such as from a mission trigger
+func (h *AIHandler) SetUnitBehaviorTree(u model.Unit, tree string) error {
+	a := h.UnitAI(u)
+	if a == nil {
+		return fmt.Errorf("[%s] unit AI not found", u.ID())
+	}
+	if _, ok := h.resources.Trees[tree]; !ok {
+		return fmt.Errorf("[%s] behavior tree does not exist: %s", u.ID(), tree)
 	}
+
+	a.Node = a.LoadBehaviorTree(tree, h.resources)
+	a.piloting.Reset()
+	return nil
 }
 
 func (a *AIBehavior) LoadBehaviorTree(ai string, aiRes AIResources) bt.Node {
@@ -309,9 +450,9 @@ func (a *AIBehavior) LoadBehaviorTree(ai string, aiRes AIResources) bt.Node {
 
 	log.Debugf("[%s] loading behavior tree '%s'", aiTree.Title, ai)
 
-	actions := make(map[AINodeID]func([]bt.Node) (bt.Status, error))
+	actions := make(map[AINodeID]bt.Node)
 	compositeTicks := make(map[AINodeID]bt.Tick)
-	decorators := make(map[AINodeID]AIDecoratorType)
+	decorators := make(map[AINodeID]AIDecoratorFunc)
 	trees := make(map[AINodeID]bt.Node)
 
 	for id, n := range aiTree.Nodes {
@@ -322,8 +463,8 @@ func (a *AIBehavior) LoadBehaviorTree(ai string, aiRes AIResources) bt.Node {
 			continue
 		}
 
-		decor := getDecorator(n.Name)
-		if decor != AI_DECORATOR_NONE {
+		if decor, ok := aiDecorators[n.Name]; ok {
+			// store decorator to wrap its child once all nodes are captured
 			decorators[id] = decor
 			continue
 		}
@@ -335,17 +476,18 @@ func (a *AIBehavior) LoadBehaviorTree(ai string, aiRes AIResources) bt.Node {
 			continue
 		}
 
-		iFunc := reflect.ValueOf(a).MethodByName(n.Name)
-		if iFunc.Kind() == reflect.Invalid || resources.IsNil(iFunc) {
+		action, ok := aiActions[n.Name]
+		if !ok {
 			log.Fatalf("[%s] behavior tree function does not exist with name: '%s' <%s>", aiTree.Title, n.Name, n.ID)
 		}
-		aValues := iFunc.Call(nil)
-
-		var actionFunc func([]bt.Node) (bt.Status, error) = aValues[0].Interface().(func([]bt.Node) (bt.Status, error))
+		actionFunc, err := action(a, n.Properties)
+		if err != nil {
+			log.Fatalf("[%s] behavior tree action '%s' <%s>: %s", aiTree.Title, n.Name, n.ID, err.Error())
+		}
 		if actionFunc == nil {
 			log.Fatalf("[%s] behavior tree action function incorrectly defined: '%s' <%s>", aiTree.Title, n.Name, n.ID)
 		}
-		actions[id] = actionFunc
+		actions[id] = bt.New(actionFunc)
 	}
 
 	// recursive function to create nodes starting from children to work back up to root
@@ -353,22 +495,25 @@ func (a *AIBehavior) LoadBehaviorTree(ai string, aiRes AIResources) bt.Node {
 	loadBehaviorNode = func(res AIResourceNode) bt.Node {
 		log.Debugf("loading node %s <%s>", res.Name, res.ID)
 
-		if decor, ok := decorators[res.ID]; ok {
-			// currently only supporting decorators with single child that is an action function
-			if len(res.Child) == 0 {
-				log.Fatalf("[%s] decorator must have one child action: %s", aiTree.Title, res.ID)
-			}
+		if childTree, ok := trees[res.ID]; ok {
+			return a.traceNode(res.Title, childTree)
+		}
+		if action, ok := actions[res.ID]; ok {
+			return a.traceNode(res.Title, action)
+		}
 
-			childAction, ok := actions[res.Child]
-			if !ok {
-				log.Fatalf("[%s][%s] decorator child not found or incorrectly defined: %s", aiTree.Title, res.ID, res.Child)
+		if decor, ok := decorators[res.ID]; ok {
+			// decorators have a single child of any kind of node
+			childRes, ok := aiTree.Nodes[res.Child]
+			if len(res.Child) == 0 || !ok {
+				log.Fatalf("[%s][%s] decorator child not found or incorrectly defined: '%s'", aiTree.Title, res.ID, res.Child)
 			}
 
-			decoratedAction := decorate(decor, childAction)
-			if decoratedAction == nil {
-				log.Fatalf("[%s][%s] decorator not currently implemented: %s", aiTree.Title, res.ID, decor.String())
+			decorated, err := decor(a, res.Properties, loadBehaviorNode(childRes))
+			if err != nil {
+				log.Fatalf("[%s] behavior tree decorator '%s' <%s>: %s", aiTree.Title, res.Name, res.ID, err.Error())
 			}
-			return bt.New(decoratedAction)
+			return a.traceNode(res.Title, decorated)
 		}
 
 		tick, ok := compositeTicks[res.ID]
@@ -378,22 +523,15 @@ func (a *AIBehavior) LoadBehaviorTree(ai string, aiRes AIResources) bt.Node {
 
 		childNodes := make([]bt.Node, 0, len(res.Children))
 		for _, childId := range res.Children {
-			childRes := aiTree.Nodes[childId]
-			log.Debugf("[%s] processing child %s <%s>", res.Name, childRes.Name, childId)
-			if childTree, ok := trees[childId]; ok {
-				childNodes = append(childNodes, childTree)
-			} else if childAction, ok := actions[childId]; ok {
-				childNodes = append(childNodes, bt.New(childAction))
-			} else if _, ok := decorators[childId]; ok {
-				childNodes = append(childNodes, loadBehaviorNode(childRes))
-			} else if _, ok := compositeTicks[childId]; ok {
-				childNodes = append(childNodes, loadBehaviorNode(childRes))
-			} else {
+			childRes, ok := aiTree.Nodes[childId]
+			if !ok {
 				log.Fatalf("[%s][%s] behavior node child not found or incorrectly defined: %s", aiTree.Title, res.ID, childId)
 			}
+			log.Debugf("[%s] processing child %s <%s>", res.Name, childRes.Name, childId)
+			childNodes = append(childNodes, loadBehaviorNode(childRes))
 		}
 
-		return bt.New(tick, childNodes...)
+		return a.traceNode(res.Title, bt.New(tick, childNodes...))
 	}
 
 	// load nodes starting from the root
@@ -407,40 +545,16 @@ func (a *AIBehavior) LoadBehaviorTree(ai string, aiRes AIResources) bt.Node {
 }
 
 func (a *AIBehavior) Tick() (bt.Status, error) {
+	a.ticks++
 	status, err := a.Node.Tick()
 	return status, err
 }
 
-func getComposite(nodeName string) bt.Tick {
-	switch nodeName {
-	case "select":
-		return bt.Selector
-	case "sequence":
-		return bt.Sequence
-	}
-	return nil
-}
-
-func getDecorator(nodeName string) AIDecoratorType {
-	switch nodeName {
-	case "negate":
-		return AI_DECORATOR_NEGATE
-	default:
-		return AI_DECORATOR_NONE
-	}
-}
-
-func decorate(decor AIDecoratorType, actionFunc func([]bt.Node) (bt.Status, error)) func([]bt.Node) (bt.Status, error) {
-	switch decor {
-	case AI_DECORATOR_NEGATE:
-		return bt.Not(actionFunc)
-	default:
-		log.Fatalf("decorator not currently implemented: %s", decor.String())
-		return nil
+func (h *AIHandler) Update() {
+	if h.g.aiTrace != nil {
+		h.g.aiTrace.tick++
 	}
-}
 
-func (h *AIHandler) Update() {
 	// only update AI whose initiative slot is next
 	turnAI := h.initiative.Next()
 	for _, a := range turnAI {
@@ -465,10 +579,23 @@ func (h *AIHandler) Update() {
 			continue
 		}
 
+		if h.isTraced(a) {
+			if a.trace == nil {
+				a.trace = &AITrace{}
+			}
+			a.trace.reset()
+		} else {
+			a.trace = nil
+		}
+
 		_, err := a.Tick()
 		if err != nil {
 			log.Error(err)
 		}
+
+		if h.g.aiTrace != nil {
+			h.g.aiTrace.write(h.g, a)
+		}
 	}
 }
 
@@ -510,23 +637,27 @@ func (h *AIHandler) isUnitPowerConditionMet(u model.Unit) bool {
 		}
 	}
 	if pConditions.PlayerDistance > 0 {
-		// calculate distance (in meters) from player, return true if <= distance
+		// calculate distance (in meters) from each player unit, return true if <= distance
 		uPos := u.Pos()
-		pPos := h.g.player.Pos()
-		pDist := geom.Distance(uPos.X, uPos.Y, pPos.X, pPos.Y) * model.METERS_PER_UNIT
-		if pDist <= float64(pConditions.PlayerDistance) {
-			return true
+		for _, p := range h.g.playerUnits() {
+			pPos := p.Pos()
+			pDist := geom.Distance(uPos.X, uPos.Y, pPos.X, pPos.Y) * model.METERS_PER_UNIT
+			if pDist <= float64(pConditions.PlayerDistance) {
+				return true
+			}
 		}
 	}
 	return false
 }
 
-func NewAIGunnery(u model.Unit) *AIGunnery {
+func NewAIGunnery(u model.Unit, pilot *model.Pilot) *AIGunnery {
 	n := &AIGunnery{
+		pilot:           pilot,
 		ticksSinceFired: math.MaxUint,
 		rangeBrackets: &AIRangeBrackets{
-			lower: make(map[model.Weapon]float64),
-			upper: make(map[model.Weapon]float64),
+			weapons: make([]model.Weapon, 0),
+			lower:   make(map[model.Weapon]float64),
+			upper:   make(map[model.Weapon]float64),
 		},
 	}
 	if u == nil {
@@ -536,6 +667,7 @@ func NewAIGunnery(u model.Unit) *AIGunnery {
 	// initialize weapons distance brackets for each weapon on the unit: where lower=1/3 of max, upper=2/3 of max
 	for _, w := range u.Armament() {
 		maxDist := w.Distance() / model.METERS_PER_UNIT
+		n.rangeBrackets.weapons = append(n.rangeBrackets.weapons, w)
 		n.rangeBrackets.lower[w] = geom.Clamp(maxDist/3, u.CollisionRadius(), maxDist)
 		n.rangeBrackets.upper[w] = geom.Clamp(2*maxDist/3, u.CollisionRadius(), maxDist)
 	}
@@ -550,11 +682,12 @@ func (n *AIGunnery) Reset() {
 func (n *AIGunnery) IdealWeaponsRange() (min, max float64) {
 	// determine range of distance to keep a target at given current weapons capabilities
 	var dpsTotal float64
-	for w, lower := range n.rangeBrackets.lower {
-		if w.AmmoBin() != nil && w.AmmoBin().AmmoCount() == 0 {
+	// iterate weapons in armament order since map order is not consistent
+	for _, w := range n.rangeBrackets.weapons {
+		if w.Destroyed() || (w.AmmoBin() != nil && w.AmmoBin().AmmoCount() == 0) {
 			continue
 		}
-		upper := n.rangeBrackets.upper[w]
+		lower, upper := n.rangeBrackets.lower[w], n.rangeBrackets.upper[w]
 
 		// TODO: could be better, but for simplicity at the moment using linear weapon DPS to range ratio
 		dps := w.Damage() / w.MaxCooldown()
@@ -562,13 +695,18 @@ func (n *AIGunnery) IdealWeaponsRange() (min, max float64) {
 		min += (dps * lower)
 		max += (dps * upper)
 	}
+	if dpsTotal == 0 {
+		// no usable weapons remaining
+		return
+	}
 	min /= dpsTotal
 	max /= dpsTotal
 	return
 }
 
-func NewAIPiloting(_ model.Unit) *AIPiloting {
+func NewAIPiloting(_ model.Unit, pilot *model.Pilot) *AIPiloting {
 	p := &AIPiloting{
+		pilot:          pilot,
 		ticksSinceEval: math.MaxUint,
 	}
 	return p
@@ -19,8 +19,7 @@ func (a *AIBehavior) HasTarget() func([]bt.Node) (bt.Status, error) {
 
 		// TODO: create separate node for selecting a new target based on some criteria?
 
-		// TODO: different detection range for different units
-		pUnits := a.g.getProximitySpriteUnits(a.u.Pos(), 1000/model.METERS_PER_UNIT)
+		pUnits := a.g.getProximitySpriteUnits(a.u.Pos(), a.u.SensorRange())
 		for _, p := range pUnits {
 			t := p.unit
 			if t == a.u || t.IsDestroyed() || a.g.IsFriendly(a.u, t) {
@@ -40,6 +39,16 @@ func (a *AIBehavior) HasTarget() func([]bt.Node) (bt.Status, error) {
 
 			return bt.Success, nil
 		}
+
+		// lance members share contacts, breaking formation to engage together
+		if contact := a.piloting.formation.contact(a.g, a.u); contact != nil {
+			if a.u.Target() != contact {
+				a.gunnery.Reset()
+				a.u.SetTarget(contact)
+			}
+			return bt.Success, nil
+		}
+
 		a.u.SetTarget(nil)
 		return bt.Failure, nil
 	}
@@ -57,6 +66,37 @@ func (a *AIBehavior) TargetIsAlive() func([]bt.Node) (bt.Status, error) {
 	}
 }
 
+func (a *AIBehavior) AttackOrderTarget() func([]bt.Node) (bt.Status, error) {
+	return func(_ []bt.Node) (bt.Status, error) {
+		if a.order == nil || a.order.command != AI_COMMAND_ATTACK_TARGET {
+			return bt.Failure, nil
+		}
+
+		target := a.order.target
+		if target == nil || target.IsDestroyed() {
+			// ordered target is gone, fall back in formation on the player
+			a.order.command = AI_COMMAND_FORM_ON_ME
+			a.order.target = nil
+			return bt.Failure, nil
+		}
+
+		if a.u.Target() != target {
+			a.gunnery.Reset()
+			a.u.SetTarget(target)
+		}
+		return bt.Success, nil
+	}
+}
+
+func (a *AIBehavior) WeaponsFree() func([]bt.Node) (bt.Status, error) {
+	return func(_ []bt.Node) (bt.Status, error) {
+		if a.order != nil && a.order.holdFire {
+			return bt.Failure, nil
+		}
+		return bt.Success, nil
+	}
+}
+
 func (a *AIBehavior) FireWeapons() func([]bt.Node) (bt.Status, error) {
 	return func(_ []bt.Node) (bt.Status, error) {
 		if a.gunnery.ticksSinceFired < math.MaxUint {
@@ -69,8 +109,8 @@ func (a *AIBehavior) FireWeapons() func([]bt.Node) (bt.Status, error) {
 			return bt.Failure, nil
 		}
 
-		// chance to fire this tick gradually increases as number of ticks without firing goes up
-		chanceToFire := float64(a.gunnery.ticksSinceFired) / (model.TICKS_PER_SECOND / AI_INITIATIVE_SLOTS)
+		// chance to fire this tick gradually increases as number of ticks without firing goes up, sooner for better gunnery
+		chanceToFire := float64(a.gunnery.ticksSinceFired) / (a.gunnery.fireDelay() * model.TICKS_PER_SECOND / AI_INITIATIVE_SLOTS)
 		if chanceToFire < 1 {
 			r := a.rng.RandFloat64In(0, 1.0)
 			if r > chanceToFire {
@@ -84,8 +124,8 @@ func (a *AIBehavior) FireWeapons() func([]bt.Node) (bt.Status, error) {
 		numWeapons := len(a.u.Armament())
 		readyWeapons := make([]model.Weapon, 0, numWeapons)
 		for _, w := range a.u.Armament() {
-			if w.Cooldown() > 0 {
-				// only weapons not on cooldown
+			if w.Destroyed() || w.Cooldown() > 0 {
+				// only weapons not destroyed or on cooldown
 				continue
 			}
 			if model.WeaponAmmoCount(w) <= 0 {
@@ -161,28 +201,33 @@ func (a *AIBehavior) FireWeapons() func([]bt.Node) (bt.Status, error) {
 		}
 
 		// check for friendly units in line of fire to target position
-		units := a.g.getSpriteUnits()
 		// use angle/pitch for weapons line of fire checks, not current target position
 		targetLine := geom.LineFromAngle(a.u.Pos().X, a.u.Pos().Y, a.u.TurretAngle(), targetDist)
-		for _, s := range units {
-			if s == a.u || s.IsDestroyed() || !a.g.IsFriendly(a.u, s) {
-				continue
+		lineMinX, lineMinY := math.Min(targetLine.X1, targetLine.X2), math.Min(targetLine.Y1, targetLine.Y2)
+		lineMaxX, lineMaxY := math.Max(targetLine.X1, targetLine.X2), math.Max(targetLine.Y1, targetLine.Y2)
+		friendlyBlocked := false
+		a.g.spatial.RangeEntities(lineMinX, lineMinY, lineMaxX, lineMaxY, func(e model.Entity) bool {
+			s := model.EntityUnit(e)
+			if s == nil || s == a.u || s.IsDestroyed() || !a.g.IsFriendly(a.u, s) {
+				return true
 			}
 
 			zDiff := target.PosZ() - a.u.PosZ()
 			if (zDiff > 0 && s.PosZ() < a.u.PosZ()) || (zDiff < 0 && s.PosZ() > a.u.PosZ()) {
 				// TODO: use a 3-Dimensional line of fire check
-				continue
+				return true
 			}
 
 			// TODO: make sure player unit is checked when same team as AI unit
 
 			sCollisionCircle := geom.Circle{X: s.Pos().X, Y: s.Pos().Y, Radius: s.CollisionRadius()}
-			if len(geom.LineCircleIntersection(targetLine, sCollisionCircle, true)) > 0 {
-				// wait to fire until line of fire is not blocked by friendly
-				// log.Debugf("[%s] friendly in LOS to %s", a.u.ID(), target.ID())
-				return bt.Failure, nil
-			}
+			friendlyBlocked = len(geom.LineCircleIntersection(targetLine, sCollisionCircle, true)) > 0
+			return !friendlyBlocked
+		})
+		if friendlyBlocked {
+			// wait to fire until line of fire is not blocked by friendly
+			// log.Debugf("[%s] friendly in LOS to %s", a.u.ID(), target.ID())
+			return bt.Failure, nil
 		}
 
 		weaponsFired := make([]model.Weapon, 0, len(readyWeapons))
@@ -248,6 +293,9 @@ func (a *AIBehavior) idealWeaponForDistance(dist float64) model.Weapon {
 	var idealWeapon model.Weapon
 	var idealDist float64
 	for _, w := range a.u.Armament() {
+		if w.Destroyed() {
+			continue
+		}
 		if model.WeaponAmmoCount(w) <= 0 {
 			// only weapons with ammo remaining
 			continue
@@ -0,0 +1,291 @@
+package game
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/harbdog/raycaster-go/geom3d"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	"github.com/pixelmek-3d/pixelmek-3d/game/render/sprites"
+
+	bt "github.com/joeycumines/go-behaviortree"
+	log "github.com/sirupsen/logrus"
+)
+
+// aiNodeNameKey is the key of the behavior tree node value holding the node title from the tree json
+type aiNodeNameKey struct{}
+
+// aiTreePrinter prints behavior trees using the node titles from the tree json
+var aiTreePrinter = bt.TreePrinter{
+	Inspector: func(node bt.Node, tick bt.Tick) ([]any, any) {
+		if name, ok := node.Value(aiNodeNameKey{}).(string); ok {
+			return nil, name
+		}
+		return bt.DefaultPrinterInspector(node, tick)
+	},
+	Formatter: bt.DefaultPrinterFormatter,
+}
+
+// AITrace is the status of each behavior tree node ticked by a unit AI in its last tick, in the order they were ticked
+type AITrace struct {
+	nodes []*AITraceNode
+	depth int
+}
+
+type AITraceNode struct {
+	Name   string
+	Depth  int
+	Status bt.Status
+}
+
+func (t *AITrace) reset() {
+	t.nodes = t.nodes[:0]
+	t.depth = 0
+}
+
+// Paths returns the node path from the root to each traced node, joined by the separator
+func (t *AITrace) Paths(sep string) []string {
+	paths := make([]string, 0, len(t.nodes))
+	path := make([]string, 0, 8)
+	for _, n := range t.nodes {
+		path = append(path[:n.Depth], n.Name)
+		paths = append(paths, strings.Join(path, sep))
+	}
+	return paths
+}
+
+// ActivePath returns the node names from the root to the last action that did not fail
+func (t *AITrace) ActivePath() []string {
+	var active []string
+	path := make([]string, 0, 8)
+	for i, n := range t.nodes {
+		path = append(path[:n.Depth], n.Name)
+		isLeaf := i == len(t.nodes)-1 || t.nodes[i+1].Depth <= n.Depth
+		if isLeaf && n.Status != bt.Failure {
+			active = slices.Clone(path)
+		}
+	}
+	return active
+}
+
+// traceNode wraps a node created from the tree json to record its status whenever the unit AI is being traced
+func (a *AIBehavior) traceNode(name string, node bt.Node) bt.Node {
+	tick, children := node()
+	traced := bt.New(
+		func(children []bt.Node) (bt.Status, error) {
+			t := a.trace
+			if t == nil {
+				return tick(children)
+			}
+
+			n := &AITraceNode{Name: name, Depth: t.depth}
+			t.nodes = append(t.nodes, n)
+			t.depth++
+			status, err := tick(children)
+			t.depth--
+			n.Status = status
+			return status, err
+		},
+		children...,
+	)
+	return traced.WithValue(aiNodeNameKey{}, name)
+}
+
+// isTraced returns true if the unit AI node statuses need to be recorded for the debug overlay or trace file
+func (h *AIHandler) isTraced(a *AIBehavior) bool {
+	return h.g.aiTrace != nil || (h.g.aiDebug != nil && h.g.aiDebug.unit == a.u)
+}
+
+// AITraceHandler writes the status of behavior tree nodes of each unit AI every tick to a csv file
+type AITraceHandler struct {
+	file   *os.File
+	writer *csv.Writer
+	tick   uint64
+}
+
+func NewAITraceHandler() *AITraceHandler {
+	fileName := "ai_trace_" + strconv.Itoa(os.Getpid()) + ".csv"
+	file, err := os.Create(fileName)
+	if err != nil {
+		log.Error("error creating AI trace file: ", err)
+		return nil
+	}
+
+	t := &AITraceHandler{
+		file:   file,
+		writer: csv.NewWriter(file),
+	}
+
+	// write header
+	t.writer.Write([]string{"Tick", "Time", "Unit", "Node", "Status"})
+
+	return t
+}
+
+func (t *AITraceHandler) Close() {
+	if t.writer != nil {
+		t.writer.Flush()
+		t.writer = nil
+	}
+	if t.file != nil {
+		t.file.Close()
+	}
+}
+
+// write records the node statuses from the last tick of the unit AI
+func (t *AITraceHandler) write(g *Game, a *AIBehavior) {
+	if t.writer == nil || a.trace == nil {
+		return
+	}
+
+	tick := strconv.FormatUint(t.tick, 10)
+	seconds := fmt.Sprintf("%0.2f", g.mission.TimerSeconds())
+	for i, path := range a.trace.Paths("/") {
+		t.writer.Write([]string{tick, seconds, a.u.ID(), path, a.trace.nodes[i].Status.String()})
+	}
+}
+
+// AIDebugOverlay is the developer debug overlay of the behavior of a selected unit AI
+type AIDebugOverlay struct {
+	unit   model.Unit
+	guides []*sprites.PathGuide
+}
+
+func NewAIDebugOverlay() *AIDebugOverlay {
+	return &AIDebugOverlay{}
+}
+
+// Unit returns the unit whose AI is shown in the overlay, or nil if the overlay is not shown
+func (d *AIDebugOverlay) Unit() model.Unit {
+	if d == nil {
+		return nil
+	}
+	return d.unit
+}
+
+// toggleAIDebugOverlay shows the AI debug overlay for the player target, or hides it if already shown
+func (g *Game) toggleAIDebugOverlay() {
+	d := g.aiDebug
+	if d == nil {
+		return
+	}
+
+	if d.unit != nil {
+		d.unit = nil
+		return
+	}
+
+	target := model.EntityUnit(g.player.Target())
+	if target == nil || g.ai.UnitAI(target) == nil {
+		return
+	}
+	d.unit = target
+}
+
+// pathGuides returns raycasted guides at each position in the planned path of the AI shown in the overlay
+func (g *Game) pathGuides() []*sprites.PathGuide {
+	d := g.aiDebug
+	u := d.Unit()
+	if u == nil {
+		return nil
+	}
+	if u.IsDestroyed() {
+		d.unit = nil
+		return nil
+	}
+
+	a := g.ai.UnitAI(u)
+	if a == nil || a.piloting.pathing == nil {
+		return nil
+	}
+
+	m := g.mission.Map()
+	path := a.piloting.pathing.destPath
+	for i, pathPos := range path {
+		var z float64
+		if m.HasElevation() {
+			z = m.HeightAt(pathPos.X, pathPos.Y)
+		}
+		guidePos := geom3d.Vector3{X: pathPos.X, Y: pathPos.Y, Z: z}
+		if i < len(d.guides) {
+			d.guides[i].SetPosition(guidePos)
+		} else {
+			d.guides = append(d.guides, sprites.NewPathGuide(guidePos))
+		}
+	}
+	return d.guides[:len(path)]
+}
+
+// aiDebugLines returns the lines of text describing the behavior of the unit AI
+func (g *Game) aiDebugLines(a *AIBehavior) []string {
+	u := a.u
+	lines := make([]string, 0, 8+len(a.gunnery.rangeBrackets.weapons))
+	lines = append(lines, fmt.Sprintf("AI: %s [%s]", u.ID(), a.gunnery.pilot))
+
+	if a.trace != nil {
+		lines = append(lines, "Node: "+strings.Join(a.trace.ActivePath(), " > "))
+	}
+
+	target := model.EntityUnit(u.Target())
+	if target != nil {
+		targetDist := model.EntityDistance(u, target) * model.METERS_PER_UNIT
+		lines = append(lines, fmt.Sprintf("Target: %s @ %0.0fm", target.ID(), targetDist))
+	} else {
+		lines = append(lines, "Target: none")
+	}
+
+	if pathing := a.piloting.pathing; pathing != nil && pathing.Len() > 0 {
+		lines = append(lines, fmt.Sprintf("Path: %d to %0.1f,%0.1f", pathing.Len(), pathing.destPos.X, pathing.destPos.Y))
+	} else {
+		lines = append(lines, "Path: none")
+	}
+
+	min, max := a.gunnery.IdealWeaponsRange()
+	lines = append(lines, fmt.Sprintf("Ideal Range: %0.0f-%0.0fm", min*model.METERS_PER_UNIT, max*model.METERS_PER_UNIT))
+	brackets := a.gunnery.rangeBrackets
+	for _, w := range brackets.weapons {
+		if w.Destroyed() {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf(
+			"  %s: %0.0f-%0.0fm",
+			w.ShortName(), brackets.lower[w]*model.METERS_PER_UNIT, brackets.upper[w]*model.METERS_PER_UNIT,
+		))
+	}
+
+	slot, order := g.ai.initiative.Order(a)
+	if slot >= 0 {
+		lines = append(lines, fmt.Sprintf(
+			"Initiative: %d.%d of %d, next in %d ticks", slot, order, len(g.ai.ai), g.ai.initiative.TicksUntil(slot),
+		))
+	}
+	return lines
+}
+
+// aiDebugPathPoints returns the screen points of the planned path of the unit AI, starting from the unit
+func (g *Game) aiDebugPathPoints(a *AIBehavior) []image.Point {
+	points := make([]image.Point, 0, len(g.aiDebug.guides)+1)
+	if s := g.getSpriteFromEntity(a.u); s != nil {
+		if bounds := s.ScreenRect(g.renderScale); bounds != nil {
+			points = append(points, image.Point{X: bounds.Min.X + bounds.Dx()/2, Y: bounds.Max.Y})
+		}
+	}
+
+	if a.piloting.pathing == nil {
+		return points
+	}
+	for i := 0; i < a.piloting.pathing.Len() && i < len(g.aiDebug.guides); i++ {
+		bounds := g.aiDebug.guides[i].ScreenRect(g.renderScale)
+		if bounds == nil {
+			// path is not in view beyond this point
+			break
+		}
+		points = append(points, image.Point{X: bounds.Min.X + bounds.Dx()/2, Y: bounds.Max.Y})
+	}
+	return points
+}
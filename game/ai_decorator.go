@@ -0,0 +1,145 @@
+package game
+
+import (
+	"fmt"
+
+	bt "github.com/joeycumines/go-behaviortree"
+)
+
+// aiDecoratorNegate inverts the result of the child, so success becomes failure and failure becomes success
+func aiDecoratorNegate(_ *AIBehavior, _ AIResourceProperties, child bt.Node) (bt.Node, error) {
+	return bt.New(bt.Not(bt.Sequence), child), nil
+}
+
+// aiDecoratorSucceed ticks the child and always succeeds, such as for optional steps in a sequence
+func aiDecoratorSucceed(_ *AIBehavior, _ AIResourceProperties, child bt.Node) (bt.Node, error) {
+	return bt.New(
+		func(children []bt.Node) (bt.Status, error) {
+			if _, err := children[0].Tick(); err != nil {
+				return bt.Failure, err
+			}
+			return bt.Success, nil
+		},
+		child,
+	), nil
+}
+
+// aiDecoratorRepeat ticks the child up to "count" times each tick, stopping early if it does not succeed
+func aiDecoratorRepeat(_ *AIBehavior, props AIResourceProperties, child bt.Node) (bt.Node, error) {
+	count, err := props.Float("count", 1)
+	if err != nil {
+		return nil, err
+	}
+	if count < 1 {
+		return nil, fmt.Errorf("node parameter 'count' must be at least 1: %v", count)
+	}
+
+	return bt.New(
+		func(children []bt.Node) (bt.Status, error) {
+			status := bt.Failure
+			for i := 0; i < int(count); i++ {
+				var err error
+				status, err = children[0].Tick()
+				if err != nil || status != bt.Success {
+					return status, err
+				}
+			}
+			return status, nil
+		},
+		child,
+	), nil
+}
+
+// aiDecoratorCooldown fails without ticking the child for "seconds" after the child last succeeded
+func aiDecoratorCooldown(a *AIBehavior, props AIResourceProperties, child bt.Node) (bt.Node, error) {
+	seconds, err := props.Float("seconds", 5)
+	if err != nil {
+		return nil, err
+	}
+	if seconds < 0 {
+		return nil, fmt.Errorf("node parameter 'seconds' must not be negative: %v", seconds)
+	}
+
+	readySeconds := 0.0
+	return bt.New(
+		func(children []bt.Node) (bt.Status, error) {
+			now := a.g.mission.TimerSeconds()
+			if now < readySeconds {
+				return bt.Failure, nil
+			}
+
+			status, err := children[0].Tick()
+			if err == nil && status == bt.Success {
+				readySeconds = now + seconds
+			}
+			return status, err
+		},
+		child,
+	), nil
+}
+
+// aiDecoratorTimeout fails once the child has been ticked without failing for longer than "seconds", then
+// keeps failing for "rest" seconds before the child is given another chance
+func aiDecoratorTimeout(a *AIBehavior, props AIResourceProperties, child bt.Node) (bt.Node, error) {
+	seconds, err := props.Float("seconds", 10)
+	if err != nil {
+		return nil, err
+	}
+	rest, err := props.Float("rest", seconds)
+	if err != nil {
+		return nil, err
+	}
+	if seconds <= 0 || rest < 0 {
+		return nil, fmt.Errorf("node parameters 'seconds' must be positive and 'rest' must not be negative: %v, %v", seconds, rest)
+	}
+
+	var startSeconds, restSeconds float64
+	var lastTick uint
+	active := false
+	return bt.New(
+		func(children []bt.Node) (bt.Status, error) {
+			now := a.g.mission.TimerSeconds()
+			if now < restSeconds {
+				return bt.Failure, nil
+			}
+
+			if !active || lastTick+1 != a.ticks {
+				// child was not ticked last time, so starts again from now
+				startSeconds = now
+			}
+			lastTick = a.ticks
+
+			if now-startSeconds > seconds {
+				active = false
+				restSeconds = now + rest
+				return bt.Failure, nil
+			}
+
+			status, err := children[0].Tick()
+			active = err == nil && status != bt.Failure
+			return status, err
+		},
+		child,
+	), nil
+}
+
+// aiDecoratorChance only ticks the child with the given "chance" from 0 to 1, otherwise fails
+func aiDecoratorChance(a *AIBehavior, props AIResourceProperties, child bt.Node) (bt.Node, error) {
+	chance, err := props.Float("chance", 0.5)
+	if err != nil {
+		return nil, err
+	}
+	if chance < 0 || chance > 1 {
+		return nil, fmt.Errorf("node parameter 'chance' must be between 0 and 1: %v", chance)
+	}
+
+	return bt.New(
+		func(children []bt.Node) (bt.Status, error) {
+			if a.rng.RandFloat64In(0, 1.0) >= chance {
+				return bt.Failure, nil
+			}
+			return children[0].Tick()
+		},
+		child,
+	), nil
+}
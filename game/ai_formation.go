@@ -0,0 +1,149 @@
+package game
+
+import (
+	"github.com/harbdog/raycaster-go/geom"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+
+	bt "github.com/joeycumines/go-behaviortree"
+)
+
+// initLance sets the mission lance formation shape and the slot of each member unit in formation
+func (f *AIFormation) initLance(lance *model.MissionLance, unitAiByID map[string]*AIBehavior) {
+	f.lance = lance.Name
+	f.shape = lance.Formation
+	f.spacing = lance.SpacingUnits()
+	f.slots = make(map[model.Unit]int)
+	for id, slot := range lance.FormationSlots() {
+		if ai, ok := unitAiByID[id]; ok {
+			f.slots[ai.u] = slot
+		}
+	}
+}
+
+// slotPosition returns the position the unit keeps in formation with the leader, or the leader
+// position if it does not have a formation slot
+func (f *AIFormation) slotPosition(g *Game, u model.Unit) *geom.Vector2 {
+	leaderPos := f.leader.Pos()
+	slot, ok := f.slots[u]
+	if !ok {
+		return leaderPos
+	}
+
+	slotPos := model.FormationSlotPosition(f.shape, slot, f.spacing, *leaderPos, f.leader.Heading())
+	if !g.isOpenPosition(slotPos) {
+		// slot is not reachable, stay close to the leader instead
+		return leaderPos
+	}
+	return &slotPos
+}
+
+// isOpenPosition returns true if the position is within the map and not inside a wall
+func (g *Game) isOpenPosition(pos geom.Vector2) bool {
+	if pos.X < 0 || pos.Y < 0 || int(pos.X) >= g.mapWidth || int(pos.Y) >= g.mapHeight {
+		return false
+	}
+	return !g.mission.Map().IsWallAt(0, int(pos.X), int(pos.Y))
+}
+
+// contact returns a target detected by another unit in the lance, so all members break formation to engage together
+func (f *AIFormation) contact(g *Game, u model.Unit) model.Unit {
+	if f == nil || len(f.lance) == 0 {
+		return nil
+	}
+
+	members := append([]model.Unit{f.leader}, f.units...)
+	for _, m := range members {
+		if m == u || m.IsDestroyed() || m.Powered() != model.POWER_ON {
+			continue
+		}
+		t := model.EntityUnit(m.Target())
+		if t == nil || t.IsDestroyed() {
+			continue
+		}
+		// only share a contact the member is detecting itself, not one it only has from the lance
+		if g.IsTargetableAtDistance(m, t, model.EntityDistance(m, t)) {
+			return t
+		}
+	}
+	return nil
+}
+
+// LoadLancemates creates the formation of player lancemates following the player unit,
+// with slots in the same order they were placed around the drop zone
+func (h *AIHandler) LoadLancemates() {
+	h.lancemates = nil
+	if h.g.mission == nil || h.g.player == nil || h.g.server != nil || len(h.g.mission.Lancemates) == 0 {
+		// the player unit is only an observer on a multiplayer server
+		return
+	}
+
+	formation := &AIFormation{
+		leader:  h.g.player.Unit,
+		units:   make([]model.Unit, 0, len(h.g.mission.Lancemates)),
+		shape:   model.FORMATION_WEDGE,
+		spacing: model.FORMATION_SPACING_METERS / model.METERS_PER_UNIT,
+		slots:   make(map[model.Unit]int, len(h.g.mission.Lancemates)),
+	}
+	for i, mUnit := range h.g.mission.Lancemates {
+		for _, ai := range h.ai {
+			if ai.order == nil || ai.u.ID() != mUnit.ID {
+				continue
+			}
+			ai.piloting.formation = formation
+			formation.AddUnit(ai.u)
+			formation.slots[ai.u] = i + 1
+			break
+		}
+	}
+	if len(formation.units) > 0 {
+		h.lancemates = formation
+	}
+}
+
+// Lancemates returns the AI of player lancemates still in play
+func (h *AIHandler) Lancemates() []*AIBehavior {
+	if h == nil || h.lancemates == nil {
+		return nil
+	}
+	lancemates := make([]*AIBehavior, 0, len(h.lancemates.units))
+	for _, u := range h.lancemates.units {
+		if u.IsDestroyed() {
+			continue
+		}
+		if ai := h.UnitAI(u); ai != nil {
+			lancemates = append(lancemates, ai)
+		}
+	}
+	return lancemates
+}
+
+// followFormation moves the unit to its slot in formation, or towards the leader if it does not have a slot
+func (a *AIBehavior) followFormation(formation *AIFormation) bt.Status {
+	leader := formation.leader
+	if leader == nil || leader.IsDestroyed() || a.u == leader {
+		return bt.Failure
+	}
+
+	guardPos := formation.slotPosition(a.g, a.u)
+	a.updatePathingToPosition(guardPos, 4)
+	targetHeading := a.pathingHeading(guardPos, leader.PosZ())
+
+	targetVelocity := a.pathingVelocity(targetHeading, a.u.MaxVelocity())
+	if formation.spacing > 0 {
+		pos := a.u.Pos()
+		slotDist := geom.Distance(pos.X, pos.Y, guardPos.X, guardPos.Y)
+		if slotDist < formation.spacing {
+			// close to its slot, match the leader velocity with a little extra to catch up to keep formation
+			targetVelocity = geom.Clamp(leader.Velocity()*(1+slotDist/formation.spacing), 0, targetVelocity)
+			if slotDist < a.u.CollisionRadius() {
+				// in its slot, face the same way as the leader
+				targetHeading = leader.Heading()
+			}
+		}
+	}
+
+	a.u.SetTargetHeading(targetHeading)
+	a.u.SetTargetTurretAngle(targetHeading)
+	a.u.SetTargetVelocity(targetVelocity)
+	return bt.Success
+}
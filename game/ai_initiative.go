@@ -2,10 +2,10 @@ package game
 
 import (
 	"math"
-	"math/rand"
 	"slices"
 	"sort"
 
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -62,7 +62,7 @@ func (n *AIInitiative) roll() {
 		roll float64
 	}
 
-	// determine initiative for each AI (higher is better)
+	// determine initiative for each AI (higher is better), where better pilots tend to react first
 	rolls := make([]*initiativeRoll, 0, len(n.aiHandler.ai))
 	for _, ai := range n.aiHandler.ai {
 		if ai.u.IsDestroyed() {
@@ -70,7 +70,7 @@ func (n *AIInitiative) roll() {
 		}
 		rolls = append(rolls, &initiativeRoll{
 			ai:   ai,
-			roll: rand.Float64(),
+			roll: model.RandFloat64() + ai.piloting.initiativeBonus(),
 		})
 
 		// set flag to indicate a new initiative order has started
@@ -123,6 +123,21 @@ func (n *AIInitiative) Next() []*AIBehavior {
 	return n.stack[slot]
 }
 
+// Order returns the initiative slot of the AI and its order within the slot, or -1 if it has no initiative
+func (n *AIInitiative) Order(a *AIBehavior) (slot, order int) {
+	for i, arr := range n.stack {
+		if j := slices.Index(arr, a); j >= 0 {
+			return i, j
+		}
+	}
+	return -1, -1
+}
+
+// TicksUntil returns the number of ticks until the AI in the initiative slot are next updated
+func (n *AIInitiative) TicksUntil(slot int) int {
+	return (slot - int(n.timer%AI_INITIATIVE_SLOTS) + AI_INITIATIVE_SLOTS) % AI_INITIATIVE_SLOTS
+}
+
 // UpdateForNewInitiativeSet performs certain updates that only occur
 // at the beginning of a new initiative set
 func (a *AIBehavior) UpdateForNewInitiativeSet() {
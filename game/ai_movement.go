@@ -61,7 +61,7 @@ func (a *AIBehavior) updatePathingToPosition(toPos *geom.Vector2, recalcDistFact
 
 	if findNewPath {
 		// find new path to reach target position
-		toPath, err := a.g.mission.Pathing.FindPath(a.u.Pos(), toPos)
+		toPath, err := a.g.mission.Pathing.FindPath(a.u.Pos(), toPos, model.UnitPathingCosts(a.u))
 		if err != nil {
 			log.Debug(err)
 			return err
@@ -109,8 +109,8 @@ func (a *AIBehavior) pathingVelocity(targetHeading, targetVelocity float64) floa
 	// return ideal target velocity to turn towards target heading
 	headingDiff := model.AngleDistance(a.u.Heading(), targetHeading)
 	if headingDiff > geom.Pi/8 {
-		// reduce velocity more for sharper turns
-		vTurnRatio := (geom.Pi - math.Abs(headingDiff)) / geom.Pi
+		// reduce velocity more for sharper turns, and less for better piloting
+		vTurnRatio := geom.Clamp(1-a.piloting.turnSlowdown()*math.Abs(headingDiff)/geom.Pi, 0, 1)
 		maxVelocity := geom.Clamp(a.u.MaxVelocity()*vTurnRatio, 0, targetVelocity)
 		targetVelocity = geom.Clamp(targetVelocity, 0, maxVelocity)
 	}
@@ -134,8 +134,8 @@ func (a *AIBehavior) TurnToTarget() func([]bt.Node) (bt.Status, error) {
 			a.piloting.ticksSinceEval += 1
 		}
 
-		// chance to reevaluate this tick gradually increases as number of ticks without goes up
-		chanceToEval := float64(a.piloting.ticksSinceEval) / (100 * model.TICKS_PER_SECOND / AI_INITIATIVE_SLOTS)
+		// chance to reevaluate this tick gradually increases as number of ticks without goes up, sooner for better piloting
+		chanceToEval := float64(a.piloting.ticksSinceEval) / (a.piloting.reactionDelay() * 100 * model.TICKS_PER_SECOND / AI_INITIATIVE_SLOTS)
 		if chanceToEval < 1 {
 			r := a.rng.RandFloat64In(0, 1.0)
 			if r > chanceToEval {
@@ -219,11 +219,11 @@ func (a *AIBehavior) TurretToTarget() func([]bt.Node) (bt.Status, error) {
 		iWeapon := a.idealWeaponForDistance(tDist)
 		iPos := model.TargetLeadPosition(a.u, target, iWeapon)
 
-		// generate random target offset based on distance for imperfect accuracy at range
+		// generate random target offset based on distance and gunnery for imperfect accuracy at range
 		// TODO: more accuracy for slow or immobile targets
-		cR, cH := target.CollisionRadius(), target.CollisionHeight()
-		xyExtent, xyClamp := (tDist/5*cR)+cR, 0.75
-		zExtent, zClamp := (tDist/10*cH)+cH/2, 0.35
+		cR, cH, spread := target.CollisionRadius(), target.CollisionHeight(), a.gunnery.aimSpread()
+		xyExtent, xyClamp := spread*((tDist/5*cR)+cR), spread*0.75
+		zExtent, zClamp := spread*((tDist/10*cH)+cH/2), spread*0.35
 		offX := geom.Clamp(a.rng.RandFloat64In(-xyExtent, xyExtent), -xyClamp, xyClamp)
 		offY := geom.Clamp(a.rng.RandFloat64In(-xyExtent, xyExtent), -xyClamp, xyClamp)
 		offZ := geom.Clamp(a.rng.RandFloat64In(-zExtent, zExtent), -zClamp, zClamp)
@@ -242,7 +242,10 @@ func (a *AIBehavior) TurretToTarget() func([]bt.Node) (bt.Status, error) {
 		// set intended target lead position for weapons fire decision
 		a.gunnery.targetLeadPos = &geom.Vector2{X: pLine.X2, Y: pLine.Y2}
 
-		if a.u.HasLockOnWeapon() {
+		if a.u.HasLockOnWeapon() && a.g.isLockOnBlocked(a.u, target) {
+			// target is protected by ECM
+			a.u.SetTargetLock(0)
+		} else if a.u.HasLockOnWeapon() {
 			// TODO: if more distant, decrease angle/pitch check for target lock proximity
 			acquireLock := model.AngleDistance(currHeading, pHeading) <= 0.5 && model.AngleDistance(currPitch, pPitch) <= 0.5
 
@@ -297,7 +300,7 @@ func (a *AIBehavior) DetermineForcedWithdrawal() func([]bt.Node) (bt.Status, err
 		}
 
 		// TODO: check if no weapons usable
-		if a.u.StructurePoints() > 0.2*a.u.MaxStructurePoints() {
+		if a.u.StructurePoints() > a.piloting.withdrawThreshold()*a.u.MaxStructurePoints() {
 			return bt.Failure, nil
 		}
 		// log.Debugf("[%s] -> determineForcedWithdrawal", a.u.ID())
@@ -420,28 +423,80 @@ func (a *AIBehavior) GuardUnit() func([]bt.Node) (bt.Status, error) {
 			return bt.Failure, nil
 		}
 
-		leader := a.piloting.formation.leader
-		if leader == nil || leader.IsDestroyed() || a.u == leader {
+		// move to the unit slot in formation, or towards the leader if not in a lance
+		status := a.followFormation(a.piloting.formation)
+
+		//log.Debugf("[%s] -> guard unit [%s] -> status: %s", a.u.ID(), a.piloting.formation.leader.ID(), status)
+		return status, nil
+	}
+}
+
+func (a *AIBehavior) FormOnPlayer() func([]bt.Node) (bt.Status, error) {
+	return func(_ []bt.Node) (bt.Status, error) {
+		if a.order == nil || a.order.command != AI_COMMAND_FORM_ON_ME || a.piloting.formation == nil {
 			return bt.Failure, nil
 		}
 
-		// TODO: determine guard position using some formation instead of some random position near leader
+		// keep in formation on the player as its lancemate
+		status := a.followFormation(a.piloting.formation)
 
-		a.updatePathingToPosition(leader.Pos(), 4)
-		targetHeading := a.pathingHeading(leader.Pos(), leader.PosZ())
+		//log.Debugf("[%s] -> form on player -> status: %s", a.u.ID(), status)
+		return status, nil
+	}
+}
 
-		a.u.SetTargetHeading(targetHeading)
-		a.u.SetTargetTurretAngle(targetHeading)
+func (a *AIBehavior) HoldPosition() func([]bt.Node) (bt.Status, error) {
+	return func(_ []bt.Node) (bt.Status, error) {
+		if a.order == nil || a.order.command != AI_COMMAND_HOLD_POSITION || a.order.position == nil {
+			return bt.Failure, nil
+		}
 
-		// TODO: match leader's velocity if close by
-		targetVelocity := a.pathingVelocity(targetHeading, a.u.MaxVelocity())
-		a.u.SetTargetVelocity(targetVelocity)
+		a.moveToOrderPosition()
+
+		//log.Debugf("[%s] -> hold position -> %v", a.u.ID(), a.order.position)
+		return bt.Success, nil
+	}
+}
+
+func (a *AIBehavior) GoToNavPoint() func([]bt.Node) (bt.Status, error) {
+	return func(_ []bt.Node) (bt.Status, error) {
+		if a.order == nil || a.order.command != AI_COMMAND_NAV_POINT || a.order.position == nil {
+			return bt.Failure, nil
+		}
 
-		//log.Debugf("[%s] -> guard unit [%s] -> heading: %0.2f", a.u.ID(), leader.ID(), geom.Degrees(targetHeading))
+		a.moveToOrderPosition()
+
+		//log.Debugf("[%s] -> go to nav point -> %v", a.u.ID(), a.order.position)
 		return bt.Success, nil
 	}
 }
 
+// moveToOrderPosition moves the unit to the position it was ordered to, and stands there once it arrives
+func (a *AIBehavior) moveToOrderPosition() {
+	if !a.moveToPosition(a.order.position) {
+		a.u.SetTargetTurretAngle(a.u.TargetHeading())
+	}
+}
+
+// moveToPosition moves the unit towards the position, returning true once it has arrived and stands there
+func (a *AIBehavior) moveToPosition(toPos *geom.Vector2) bool {
+	pos := a.u.Pos()
+	if geom.Distance2(pos.X, pos.Y, toPos.X, toPos.Y) < 2 {
+		a.piloting.pathing.SetDestination(nil, make([]*geom.Vector2, 0))
+		a.u.SetTargetVelocity(0)
+		return true
+	}
+
+	a.updatePathingToPosition(toPos, 4)
+	targetHeading := a.pathingHeading(toPos, a.u.PosZ())
+
+	a.u.SetTargetHeading(targetHeading)
+
+	targetVelocity := a.pathingVelocity(targetHeading, a.u.MaxVelocity())
+	a.u.SetTargetVelocity(targetVelocity)
+	return false
+}
+
 func (a *AIBehavior) PatrolPath() func([]bt.Node) (bt.Status, error) {
 	return func(_ []bt.Node) (bt.Status, error) {
 		patrolPath := a.u.PathStack()
@@ -0,0 +1,56 @@
+package game
+
+import (
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+)
+
+const (
+	// AI_PILOT_SKILL_FACTOR is how much each skill level above or below a regular pilot changes AI skill modifiers
+	AI_PILOT_SKILL_FACTOR = 0.125
+
+	// AI_WITHDRAW_STRUCTURE_RATIO is the fraction of structure remaining at which a regular pilot withdraws
+	AI_WITHDRAW_STRUCTURE_RATIO = 0.2
+)
+
+// pilotSkillModifier returns a multiplier that is 1.0 for a regular pilot skill, lower for better skill and higher for worse
+func pilotSkillModifier(skill, regularSkill int) float64 {
+	return 1 + AI_PILOT_SKILL_FACTOR*float64(skill-regularSkill)
+}
+
+// unitPilot returns the pilot of the unit, or the difficulty level default pilot if it does not have one
+func (h *AIHandler) unitPilot(u model.Unit) *model.Pilot {
+	if u != nil && u.Pilot() != nil {
+		return u.Pilot()
+	}
+	return h.g.difficulty.DefaultPilot()
+}
+
+// aimSpread returns the multiplier for random aim offset from the target lead position
+func (n *AIGunnery) aimSpread() float64 {
+	return pilotSkillModifier(n.pilot.Gunnery, model.PILOT_GUNNERY_DEFAULT)
+}
+
+// fireDelay returns the multiplier for how long the AI waits between deciding to fire weapons
+func (n *AIGunnery) fireDelay() float64 {
+	return pilotSkillModifier(n.pilot.Gunnery, model.PILOT_GUNNERY_DEFAULT)
+}
+
+// reactionDelay returns the multiplier for how long the AI waits before reevaluating where to move
+func (p *AIPiloting) reactionDelay() float64 {
+	return pilotSkillModifier(p.pilot.Piloting, model.PILOT_PILOTING_DEFAULT)
+}
+
+// initiativeBonus returns the amount added to initiative rolls, positive for better skill and negative for worse
+func (p *AIPiloting) initiativeBonus() float64 {
+	return 1 - pilotSkillModifier(p.pilot.Piloting, model.PILOT_PILOTING_DEFAULT)
+}
+
+// turnSlowdown returns the multiplier for how much velocity is reduced when making sharp turns
+func (p *AIPiloting) turnSlowdown() float64 {
+	return pilotSkillModifier(p.pilot.Piloting, model.PILOT_PILOTING_DEFAULT)
+}
+
+// withdrawThreshold returns the fraction of structure remaining at which the unit is forced to withdraw
+func (p *AIPiloting) withdrawThreshold() float64 {
+	return AI_WITHDRAW_STRUCTURE_RATIO * pilotSkillModifier(p.pilot.Piloting, model.PILOT_PILOTING_DEFAULT)
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	bt "github.com/joeycumines/go-behaviortree"
+	log "github.com/sirupsen/logrus"
+)
+
+// AIActionFunc creates the tick function of a behavior tree action node for the unit AI,
+// using any parameters from the node properties
+type AIActionFunc func(a *AIBehavior, props AIResourceProperties) (func([]bt.Node) (bt.Status, error), error)
+
+// AIDecoratorFunc creates a behavior tree node for the unit AI that decorates the result of its child node,
+// using any parameters from the node properties
+type AIDecoratorFunc func(a *AIBehavior, props AIResourceProperties, child bt.Node) (bt.Node, error)
+
+var (
+	aiActions    = make(map[string]AIActionFunc)
+	aiDecorators = make(map[string]AIDecoratorFunc)
+)
+
+func init() {
+	// actions that do not use any node parameters
+	for name, action := range map[string]func(*AIBehavior) func([]bt.Node) (bt.Status, error){
+		"HasTarget":                 (*AIBehavior).HasTarget,
+		"TargetIsAlive":             (*AIBehavior).TargetIsAlive,
+		"AttackOrderTarget":         (*AIBehavior).AttackOrderTarget,
+		"WeaponsFree":               (*AIBehavior).WeaponsFree,
+		"FireWeapons":               (*AIBehavior).FireWeapons,
+		"TurnToTarget":              (*AIBehavior).TurnToTarget,
+		"TurretToTarget":            (*AIBehavior).TurretToTarget,
+		"VelocityToMax":             (*AIBehavior).VelocityToMax,
+		"DetermineForcedWithdrawal": (*AIBehavior).DetermineForcedWithdrawal,
+		"TurnToWithdraw":            (*AIBehavior).TurnToWithdraw,
+		"InWithdrawArea":            (*AIBehavior).InWithdrawArea,
+		"Withdraw":                  (*AIBehavior).Withdraw,
+		"GuardArea":                 (*AIBehavior).GuardArea,
+		"GuardUnit":                 (*AIBehavior).GuardUnit,
+		"FormOnPlayer":              (*AIBehavior).FormOnPlayer,
+		"HoldPosition":              (*AIBehavior).HoldPosition,
+		"GoToNavPoint":              (*AIBehavior).GoToNavPoint,
+		"PatrolPath":                (*AIBehavior).PatrolPath,
+		"HuntLastTargetArea":        (*AIBehavior).HuntLastTargetArea,
+		"Wander":                    (*AIBehavior).Wander,
+		"FocusFire":                 (*AIBehavior).FocusFire,
+	} {
+		RegisterAIAction(name, aiActionWithoutParams(action))
+	}
+
+	// actions with node parameters
+	RegisterAIAction("SeekCover", (*AIBehavior).SeekCover)
+	RegisterAIAction("FlankTarget", (*AIBehavior).FlankTarget)
+	RegisterAIAction("KeepIdealRange", (*AIBehavior).KeepIdealRange)
+	RegisterAIAction("RetreatToCool", (*AIBehavior).RetreatToCool)
+
+	RegisterAIDecorator("negate", aiDecoratorNegate)
+	RegisterAIDecorator("succeed", aiDecoratorSucceed)
+	RegisterAIDecorator("repeat", aiDecoratorRepeat)
+	RegisterAIDecorator("cooldown", aiDecoratorCooldown)
+	RegisterAIDecorator("timeout", aiDecoratorTimeout)
+	RegisterAIDecorator("chance", aiDecoratorChance)
+}
+
+// RegisterAIAction adds an action that behavior tree json can use as a node by name,
+// replacing any action previously registered with the same name
+func RegisterAIAction(name string, action AIActionFunc) {
+	if _, ok := aiActions[name]; ok {
+		log.Debugf("replacing behavior tree action: %s", name)
+	}
+	aiActions[name] = action
+}
+
+// RegisterAIDecorator adds a decorator that behavior tree json can use as a node by name,
+// replacing any decorator previously registered with the same name
+func RegisterAIDecorator(name string, decorator AIDecoratorFunc) {
+	if _, ok := aiDecorators[name]; ok {
+		log.Debugf("replacing behavior tree decorator: %s", name)
+	}
+	aiDecorators[name] = decorator
+}
+
+func aiActionWithoutParams(action func(*AIBehavior) func([]bt.Node) (bt.Status, error)) AIActionFunc {
+	return func(a *AIBehavior, _ AIResourceProperties) (func([]bt.Node) (bt.Status, error), error) {
+		return action(a), nil
+	}
+}
+
+func getComposite(nodeName string) bt.Tick {
+	switch nodeName {
+	case "select":
+		return bt.Selector
+	case "sequence":
+		return bt.Sequence
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/harbdog/raycaster-go/geom"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+
+	bt "github.com/joeycumines/go-behaviortree"
+)
+
+const (
+	// number of directions around the unit checked for a position to take cover
+	AI_COVER_DIRECTIONS = 16
+	// number of distances out to the search radius checked in each direction for a position to take cover
+	AI_COVER_RINGS = 3
+)
+
+// SeekCover moves to a nearby position within "radius" meters where a wall blocks line of sight from the target
+func (a *AIBehavior) SeekCover(props AIResourceProperties) (func([]bt.Node) (bt.Status, error), error) {
+	radius, err := props.Float("radius", 200)
+	if err != nil {
+		return nil, err
+	}
+	if radius <= 0 {
+		return nil, fmt.Errorf("node parameter 'radius' must be positive: %v", radius)
+	}
+	radius /= model.METERS_PER_UNIT
+
+	var coverPos *geom.Vector2
+	return func(_ []bt.Node) (bt.Status, error) {
+		if a.u.UnitType() == model.EmplacementUnitType {
+			// emplacements cannot move to cover
+			return bt.Failure, nil
+		}
+
+		target := model.EntityUnit(a.u.Target())
+		if target == nil {
+			coverPos = nil
+			return bt.Failure, nil
+		}
+
+		tPos := target.Pos()
+		if coverPos == nil || !a.isCoveredFrom(tPos, coverPos) {
+			// target moved to see the last cover position, find another
+			coverPos = a.findCover(tPos, radius)
+			if coverPos == nil {
+				return bt.Failure, nil
+			}
+		}
+
+		a.moveToPosition(coverPos)
+
+		//log.Debugf("[%s] -> seek cover from [%s] -> %v", a.u.ID(), target.ID(), coverPos)
+		return bt.Success, nil
+	}, nil
+}
+
+// isCoveredFrom returns true if a wall is between the position and where it is seen from
+func (a *AIBehavior) isCoveredFrom(fromPos, pos *geom.Vector2) bool {
+	return a.g.wallBlocksLine(geom.Line{X1: fromPos.X, Y1: fromPos.Y, X2: pos.X, Y2: pos.Y})
+}
+
+// findCover returns an open position near the unit within the radius that is covered from the position
+// it is seen from, or nil if there is none
+func (a *AIBehavior) findCover(fromPos *geom.Vector2, radius float64) *geom.Vector2 {
+	uPos := a.u.Pos()
+	if a.isCoveredFrom(fromPos, uPos) {
+		return &geom.Vector2{X: uPos.X, Y: uPos.Y}
+	}
+
+	// closer rings are checked first so the unit does not have to move far to reach cover
+	for ring := 1; ring <= AI_COVER_RINGS; ring++ {
+		dist := radius * float64(ring) / AI_COVER_RINGS
+
+		var coverPos *geom.Vector2
+		var coverDist float64
+		for i := 0; i < AI_COVER_DIRECTIONS; i++ {
+			angle := geom.Pi2 * float64(i) / AI_COVER_DIRECTIONS
+			line := geom.LineFromAngle(uPos.X, uPos.Y, angle, dist)
+			pos := &geom.Vector2{X: line.X2, Y: line.Y2}
+			if !a.g.isOpenPosition(*pos) || !a.isCoveredFrom(fromPos, pos) {
+				continue
+			}
+
+			// prefer cover that is not closer to where it is seen from
+			fromDist := geom.Distance2(fromPos.X, fromPos.Y, pos.X, pos.Y)
+			if coverPos == nil || fromDist > coverDist {
+				coverPos, coverDist = pos, fromDist
+			}
+		}
+		if coverPos != nil {
+			return coverPos
+		}
+	}
+	return nil
+}
+
+// FlankTarget moves around to the side of the target at "angle" degrees from its heading, staying within ideal weapons range
+func (a *AIBehavior) FlankTarget(props AIResourceProperties) (func([]bt.Node) (bt.Status, error), error) {
+	angle, err := props.Float("angle", 90)
+	if err != nil {
+		return nil, err
+	}
+	if angle < 0 || angle > 180 {
+		return nil, fmt.Errorf("node parameter 'angle' must be between 0 and 180: %v", angle)
+	}
+	angle = geom.Radians(angle)
+
+	return func(_ []bt.Node) (bt.Status, error) {
+		if a.u.UnitType() == model.EmplacementUnitType {
+			// emplacements cannot move to flank
+			return bt.Failure, nil
+		}
+
+		target := model.EntityUnit(a.u.Target())
+		if target == nil {
+			return bt.Failure, nil
+		}
+
+		min, max := a.gunnery.IdealWeaponsRange()
+		if max == 0 {
+			// no usable weapons remaining
+			return bt.Failure, nil
+		}
+
+		// flank on whichever side of the target the unit is already on
+		uPos, tPos := a.u.Pos(), target.Pos()
+		tLine := geom.Line{X1: tPos.X, Y1: tPos.Y, X2: uPos.X, Y2: uPos.Y}
+		tHeading := target.Heading()
+		flankAngle := tHeading + angle
+		if model.AngleDistance(tHeading, tLine.Angle()) < 0 {
+			flankAngle = tHeading - angle
+		}
+
+		flankLine := geom.LineFromAngle(tPos.X, tPos.Y, flankAngle, (min+max)/2)
+		flankPos := &geom.Vector2{X: flankLine.X2, Y: flankLine.Y2}
+		if !a.g.isOpenPosition(*flankPos) {
+			return bt.Failure, nil
+		}
+
+		a.moveToPosition(flankPos)
+
+		//log.Debugf("[%s] -> flank target [%s] -> %v", a.u.ID(), target.ID(), flankPos)
+		return bt.Success, nil
+	}, nil
+}
+
+// KeepIdealRange moves closer or further from the target only as needed to keep it within ideal weapons range
+func (a *AIBehavior) KeepIdealRange(_ AIResourceProperties) (func([]bt.Node) (bt.Status, error), error) {
+	return func(_ []bt.Node) (bt.Status, error) {
+		if a.u.UnitType() == model.EmplacementUnitType {
+			// emplacements only have turrets
+			return bt.Success, nil
+		}
+
+		target := model.EntityUnit(a.u.Target())
+		if target == nil {
+			return bt.Failure, nil
+		}
+
+		min, max := a.gunnery.IdealWeaponsRange()
+		if max == 0 {
+			// no usable weapons remaining
+			return bt.Failure, nil
+		}
+
+		uPos, tPos := a.u.Pos(), target.Pos()
+		tLine := geom.Line{X1: tPos.X, Y1: tPos.Y, X2: uPos.X, Y2: uPos.Y}
+		tDist := tLine.Distance()
+
+		var keepDist float64
+		switch {
+		case tDist > max:
+			keepDist = max
+		case tDist < min:
+			keepDist = min
+		default:
+			// already within ideal range, hold here facing the target
+			a.piloting.pathing.SetDestination(nil, make([]*geom.Vector2, 0))
+			a.u.SetTargetHeading(model.ClampAngle2Pi(tLine.Angle() + geom.Pi))
+			a.u.SetTargetVelocity(0)
+			return bt.Success, nil
+		}
+
+		keepLine := geom.LineFromAngle(tPos.X, tPos.Y, tLine.Angle(), keepDist)
+		keepPos := &geom.Vector2{X: keepLine.X2, Y: keepLine.Y2}
+		if !a.g.isOpenPosition(*keepPos) {
+			return bt.Failure, nil
+		}
+
+		a.moveToPosition(keepPos)
+
+		//log.Debugf("[%s] -> keep ideal range [%0.1f, %0.1f] of [%s] -> %v", a.u.ID(), min, max, target.ID(), keepPos)
+		return bt.Success, nil
+	}, nil
+}
+
+// RetreatToCool moves "distance" meters away from the target to cool down while heat is above the
+// "heat" fraction of max heat, or stands still to cool down if it has no target
+func (a *AIBehavior) RetreatToCool(props AIResourceProperties) (func([]bt.Node) (bt.Status, error), error) {
+	heat, err := props.Float("heat", 0.7)
+	if err != nil {
+		return nil, err
+	}
+	distance, err := props.Float("distance", 200)
+	if err != nil {
+		return nil, err
+	}
+	if heat < 0 || distance < 0 {
+		return nil, fmt.Errorf("node parameters 'heat' and 'distance' must not be negative: %v, %v", heat, distance)
+	}
+	distance /= model.METERS_PER_UNIT
+
+	return func(_ []bt.Node) (bt.Status, error) {
+		maxHeat := a.u.MaxHeat()
+		if maxHeat <= 0 || a.u.Heat() < heat*maxHeat {
+			return bt.Failure, nil
+		}
+
+		if a.u.UnitType() == model.EmplacementUnitType {
+			// emplacements cannot move, only wait to cool down
+			return bt.Success, nil
+		}
+
+		target := model.EntityUnit(a.u.Target())
+		if target == nil {
+			a.piloting.pathing.SetDestination(nil, make([]*geom.Vector2, 0))
+			a.u.SetTargetVelocity(0)
+			return bt.Success, nil
+		}
+
+		// retreat directly away from the target, or as close to that as walls allow
+		uPos, tPos := a.u.Pos(), target.Pos()
+		tLine := geom.Line{X1: tPos.X, Y1: tPos.Y, X2: uPos.X, Y2: uPos.Y}
+		awayAngle := tLine.Angle()
+		for _, offset := range []float64{0, geom.Pi / 4, -geom.Pi / 4, geom.HalfPi, -geom.HalfPi} {
+			retreatLine := geom.LineFromAngle(uPos.X, uPos.Y, awayAngle+offset, distance)
+			retreatPos := &geom.Vector2{X: retreatLine.X2, Y: retreatLine.Y2}
+			if a.g.isOpenPosition(*retreatPos) {
+				a.moveToPosition(retreatPos)
+
+				//log.Debugf("[%s] -> retreat to cool [%0.1f/%0.1f] -> %v", a.u.ID(), a.u.Heat(), maxHeat, retreatPos)
+				return bt.Success, nil
+			}
+		}
+
+		// nowhere to retreat to, stand still to cool down
+		a.piloting.pathing.SetDestination(nil, make([]*geom.Vector2, 0))
+		a.u.SetTargetVelocity(0)
+		return bt.Success, nil
+	}, nil
+}
+
+// FocusFire targets the most damaged enemy the unit can detect, so the lance finishes off weakened units first
+func (a *AIBehavior) FocusFire() func([]bt.Node) (bt.Status, error) {
+	return func(_ []bt.Node) (bt.Status, error) {
+		var focus model.Unit
+		focusHealth := 0.0
+
+		pUnits := a.g.getProximitySpriteUnits(a.u.Pos(), a.u.SensorRange())
+		for _, p := range pUnits {
+			t := p.unit
+			if t == a.u || t.IsDestroyed() || a.g.IsFriendly(a.u, t) {
+				continue
+			}
+			if !a.g.IsTargetableAtDistance(a.u, t, p.distance) {
+				continue
+			}
+
+			health := unitHealth(t)
+			if focus == nil || health < focusHealth {
+				focus, focusHealth = t, health
+			}
+		}
+
+		if focus == nil {
+			return bt.Failure, nil
+		}
+
+		if a.u.Target() != focus {
+			// only switch targets if the new one is more damaged than the current target
+			current := model.EntityUnit(a.u.Target())
+			if current == nil || current.IsDestroyed() || focusHealth < unitHealth(current) {
+				a.gunnery.Reset()
+				a.u.SetTarget(focus)
+			}
+		}
+		return bt.Success, nil
+	}
+}
+
+// unitHealth returns the fraction of armor and structure the unit has remaining
+func unitHealth(u model.Unit) float64 {
+	maxHealth := u.MaxArmorPoints() + u.MaxStructurePoints()
+	if maxHealth <= 0 {
+		return 0
+	}
+	return (u.ArmorPoints() + u.StructurePoints()) / maxHealth
+}
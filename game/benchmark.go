@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/pixelmek-3d/pixelmek-3d/game/common"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
 )
 
 type BenchmarkHandler struct {
@@ -64,3 +66,95 @@ func (b *BenchmarkHandler) UpdateStop() {
 	line := fmt.Sprintf("%d,%d,%0.1f,%0.1f\n", b.tick, duration.Nanoseconds(), tps, fps)
 	b.write(line)
 }
+
+// BattleBenchmarkResult is the update time measured from a headless battle benchmark
+type BattleBenchmarkResult struct {
+	Units    int
+	Ticks    uint
+	Duration time.Duration
+}
+
+// TickDuration returns the average update time of each tick
+func (r *BattleBenchmarkResult) TickDuration() time.Duration {
+	if r.Ticks == 0 {
+		return 0
+	}
+	return r.Duration / time.Duration(r.Ticks)
+}
+
+// TPS returns the ticks per second that could be updated at the average update time
+func (r *BattleBenchmarkResult) TPS() float64 {
+	tickDuration := r.TickDuration()
+	if tickDuration == 0 {
+		return 0
+	}
+	return float64(time.Second) / float64(tickDuration)
+}
+
+// LoadBattleBenchmark loads a mission on the map with random mechs of two opposing teams spread across it
+func (g *Game) LoadBattleBenchmark(missionMap *model.Map, numUnits int) (*model.Mission, error) {
+	mission, err := model.NewMissionFromMap(missionMap)
+	if err != nil {
+		return nil, err
+	}
+	mission.Title = "Benchmark\n" + missionMap.Name
+	mission.Briefing = "Benchmark a battle between many units."
+	mission.Objectives = &model.MissionObjectives{}
+
+	mechResources := g.resources.GetMechResourceList()
+	if len(mechResources) == 0 {
+		return nil, fmt.Errorf("no mech resources available for benchmark")
+	}
+
+	// place units in random open cells, no more than one unit per cell
+	w, h := missionMap.Size()
+	occupied := make(map[[2]int]bool, numUnits)
+	mission.Mechs = make([]model.MissionUnit, 0, numUnits)
+	for i := 0; i < numUnits && len(occupied) < w*h/2; {
+		x, y := 1+model.RandIntn(w-2), 1+model.RandIntn(h-2)
+		if occupied[[2]int{x, y}] || missionMap.IsWallAt(0, x, y) {
+			continue
+		}
+		occupied[[2]int{x, y}] = true
+
+		r := mechResources[model.RandIntn(len(mechResources))]
+		mission.Mechs = append(mission.Mechs, model.MissionUnit{
+			ID:       fmt.Sprintf("benchmark_%d", i),
+			Team:     i % 2,
+			Unit:     model.TrimExtension(r.File),
+			Position: [2]float64{float64(x) + 0.5, float64(y) + 0.5},
+			Heading:  float64(model.RandIntn(360)),
+		})
+		i++
+	}
+
+	g.mission = mission
+	return mission, nil
+}
+
+// BenchmarkBattle simulates the loaded battle benchmark for a number of ticks and measures the update time,
+// with spatial grid disabled to compare against collision and line of sight checks against everything
+func (g *Game) BenchmarkBattle(ticks uint, spatialGrid bool) *BattleBenchmarkResult {
+	if !g.headless {
+		panic("benchmark requires a game from NewHeadlessGame!")
+	}
+
+	g.spatialDisabled = !spatialGrid
+	g.initMission()
+	g.mission.SetTickTimer(true)
+
+	g.player.autopilot = true
+	g.ai.NewUnitAI(g.player.Unit)
+
+	stopwatch := &common.Stopwatch{}
+	stopwatch.Start()
+	for tick := uint(0); tick < ticks; tick++ {
+		g.simulateTick()
+	}
+
+	return &BattleBenchmarkResult{
+		Units:    len(g.mission.Mechs),
+		Ticks:    ticks,
+		Duration: stopwatch.Stop(),
+	}
+}
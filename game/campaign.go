@@ -0,0 +1,385 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	"github.com/pixelmek-3d/pixelmek-3d/game/resources"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CAMPAIGN_SAVE_VERSION is the campaign save file format version, it needs to be incremented when the format changes
+const CAMPAIGN_SAVE_VERSION = 1
+
+// CAMPAIGN_MAX_LANCEMATES is the number of other roster mechs that can launch with the player to fill out a lance
+const CAMPAIGN_MAX_LANCEMATES = 3
+
+// repair costs in C-Bills
+const (
+	REPAIR_COST_ARMOR_POINT     = 100
+	REPAIR_COST_STRUCTURE_POINT = 400
+	REPAIR_COST_WEAPON_TON      = 10000
+	REPAIR_COST_AMMO_ROUND      = 20
+	REPAIR_COST_HEAT_SINK       = 2000
+	REPAIR_COST_JUMP_JET        = 5000
+)
+
+// CampaignSave is the saved progress of a campaign between missions
+type CampaignSave struct {
+	Version   int                   `json:"version"`
+	Campaign  string                `json:"campaign"`
+	Mission   string                `json:"mission"`
+	CBills    int                   `json:"c_bills"`
+	Completed []string              `json:"completed,omitempty"`
+	Selected  int                   `json:"selected"`
+	Roster    []*CampaignRosterMech `json:"roster"`
+}
+
+// CampaignRosterMech is a mech in the player's mech bay, with its damage state if not fully repaired
+type CampaignRosterMech struct {
+	Mech     string           `json:"mech"`
+	State    *model.UnitState `json:"state,omitempty"`
+	Salvaged bool             `json:"salvaged,omitempty"`
+}
+
+// CampaignResult is the outcome of the last campaign mission shown in the debrief
+type CampaignResult struct {
+	Success  bool
+	Reward   int
+	Salvaged []string
+	Next     string
+}
+
+// Campaign handles the progress of the active campaign
+type Campaign struct {
+	campaign *model.Campaign
+	save     *CampaignSave
+	savePath string
+
+	// enemy mechs destroyed during the current mission that can be salvaged
+	salvage []model.Unit
+
+	// result of the last completed mission
+	result *CampaignResult
+}
+
+func campaignSavePath(campaignFile string) string {
+	return filepath.Join(resources.UserSavePath, "campaign_"+model.TrimExtension(campaignFile)+".json")
+}
+
+// HasCampaignSave returns true if there is saved progress for the campaign
+func HasCampaignSave(campaignFile string) bool {
+	_, err := os.Stat(campaignSavePath(campaignFile))
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+// NewCampaign starts the campaign from the beginning, replacing any saved progress
+func (g *Game) NewCampaign(campaignFile string) error {
+	c, err := model.LoadCampaign(campaignFile)
+	if err != nil {
+		return err
+	}
+
+	save := &CampaignSave{
+		Version:  CAMPAIGN_SAVE_VERSION,
+		Campaign: campaignFile,
+		Mission:  c.Missions[0].ID,
+		CBills:   c.CBills,
+		Roster:   make([]*CampaignRosterMech, 0, len(c.Mechs)),
+	}
+	for _, mech := range c.Mechs {
+		if g.LoadUnit(model.MechResourceType, mech) == nil {
+			return fmt.Errorf("[%s] mech not found: %s", campaignFile, mech)
+		}
+		save.Roster = append(save.Roster, &CampaignRosterMech{Mech: mech})
+	}
+
+	campaign := &Campaign{
+		campaign: c,
+		save:     save,
+		savePath: campaignSavePath(campaignFile),
+	}
+	if err := campaign.saveProgress(); err != nil {
+		return err
+	}
+
+	g.campaign = campaign
+	return nil
+}
+
+// LoadCampaign continues the campaign from its saved progress
+func (g *Game) LoadCampaign(campaignFile string) error {
+	c, err := model.LoadCampaign(campaignFile)
+	if err != nil {
+		return err
+	}
+
+	savePath := campaignSavePath(campaignFile)
+	saveBytes, err := os.ReadFile(savePath)
+	if err != nil {
+		return err
+	}
+
+	save := &CampaignSave{}
+	if err := json.Unmarshal(saveBytes, save); err != nil {
+		return fmt.Errorf("[%s] %s", savePath, err.Error())
+	}
+	if save.Version != CAMPAIGN_SAVE_VERSION {
+		return fmt.Errorf("[%s] unsupported campaign save version %d, expected version %d", savePath, save.Version, CAMPAIGN_SAVE_VERSION)
+	}
+	if save.Mission != model.CAMPAIGN_END && c.GetMission(save.Mission) == nil {
+		return fmt.Errorf("[%s] unknown campaign mission: %s", savePath, save.Mission)
+	}
+	if len(save.Roster) == 0 {
+		return fmt.Errorf("[%s] mech roster is empty", savePath)
+	}
+	if save.Selected < 0 || save.Selected >= len(save.Roster) {
+		save.Selected = 0
+	}
+
+	campaign := &Campaign{
+		campaign: c,
+		save:     save,
+		savePath: savePath,
+	}
+	for i := range save.Roster {
+		if _, err := campaign.rosterUnit(g, i); err != nil {
+			return fmt.Errorf("[%s] %s", savePath, err.Error())
+		}
+	}
+
+	g.campaign = campaign
+	return nil
+}
+
+func (c *Campaign) saveProgress() error {
+	if err := os.MkdirAll(filepath.Dir(c.savePath), 0755); err != nil {
+		return err
+	}
+
+	saveBytes, err := json.MarshalIndent(c.save, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.savePath, saveBytes, 0644)
+}
+
+func (c *Campaign) Title() string {
+	return c.campaign.Title
+}
+
+func (c *Campaign) CBills() int {
+	return c.save.CBills
+}
+
+// Complete returns true if there are no more missions left in the campaign
+func (c *Campaign) Complete() bool {
+	return c.save.Mission == model.CAMPAIGN_END
+}
+
+// Mission returns the next campaign mission to be played
+func (c *Campaign) Mission() *model.CampaignMission {
+	return c.campaign.GetMission(c.save.Mission)
+}
+
+func (c *Campaign) Roster() []*CampaignRosterMech {
+	return c.save.Roster
+}
+
+func (c *Campaign) Selected() int {
+	return c.save.Selected
+}
+
+func (c *Campaign) SetSelected(i int) {
+	if i >= 0 && i < len(c.save.Roster) {
+		c.save.Selected = i
+	}
+}
+
+// rosterUnit creates the unit for the roster mech with its damage state applied
+func (c *Campaign) rosterUnit(g *Game, i int) (model.Unit, error) {
+	r := c.save.Roster[i]
+	unit := g.LoadUnit(model.MechResourceType, r.Mech)
+	if unit == nil {
+		return nil, fmt.Errorf("mech not found: %s", r.Mech)
+	}
+	if err := unit.SetUnitState(r.State); err != nil {
+		return nil, fmt.Errorf("%s: %s", r.Mech, err.Error())
+	}
+	return unit, nil
+}
+
+// repairCost returns the C-Bills needed to fully repair and rearm the roster mech
+func (c *Campaign) repairCost(g *Game, i int) int {
+	r := c.save.Roster[i]
+	if r.State == nil {
+		return 0
+	}
+
+	unit := g.LoadUnit(model.MechResourceType, r.Mech)
+	if unit == nil {
+		return 0
+	}
+	full := unit.UnitState()
+
+	var cost float64
+	for j, l := range full.Locations {
+		if j < len(r.State.Locations) {
+			cost += (l.Armor - r.State.Locations[j].Armor) * REPAIR_COST_ARMOR_POINT
+			cost += (l.Structure - r.State.Locations[j].Structure) * REPAIR_COST_STRUCTURE_POINT
+		}
+	}
+	armament := unit.Armament()
+	for _, w := range r.State.DestroyedWeapons {
+		if w < len(armament) {
+			cost += armament[w].Tonnage() * REPAIR_COST_WEAPON_TON
+		}
+	}
+	for j, ammoCount := range full.Ammo {
+		if j < len(r.State.Ammo) {
+			cost += float64(ammoCount-r.State.Ammo[j]) * REPAIR_COST_AMMO_ROUND
+		}
+	}
+	cost += float64(full.HeatSinks-r.State.HeatSinks) * REPAIR_COST_HEAT_SINK
+	cost += float64(full.JumpJets-r.State.JumpJets) * REPAIR_COST_JUMP_JET
+
+	return int(cost)
+}
+
+// repair fully repairs and rearms the roster mech if there are enough C-Bills to pay for it
+func (c *Campaign) repair(g *Game, i int) error {
+	cost := c.repairCost(g, i)
+	if cost > c.save.CBills {
+		return fmt.Errorf("repair costs %d C-Bills, only %d available", cost, c.save.CBills)
+	}
+
+	c.save.CBills -= cost
+	c.save.Roster[i].State = nil
+	return c.saveProgress()
+}
+
+// recordSalvage adds an enemy mech destroyed in the current mission to the potential salvage
+func (c *Campaign) recordSalvage(unit model.Unit) {
+	if _, ok := unit.(*model.Mech); !ok || slices.Contains(c.salvage, unit) {
+		return
+	}
+	c.salvage = append(c.salvage, unit)
+}
+
+// launchCampaignMission loads the next campaign mission with the selected roster mech as the player unit
+func (g *Game) launchCampaignMission() error {
+	c := g.campaign
+	cMission := c.Mission()
+	if cMission == nil {
+		return fmt.Errorf("campaign has no missions remaining")
+	}
+
+	unit, err := c.rosterUnit(g, c.save.Selected)
+	if err != nil {
+		return err
+	}
+	if unit.IsDestroyed() {
+		return fmt.Errorf("%s needs to be repaired before launch", unit.Name())
+	}
+
+	if _, err := g.LoadMission(cMission.Mission); err != nil {
+		return err
+	}
+	g.SetPlayerUnit(unit)
+
+	if len(g.mission.Lancemates) == 0 {
+		// the rest of the roster ready to launch fills out the lance when the mission has no lancemates of its own
+		g.mission.AddLancemates(c.lancemates(g)...)
+	}
+
+	c.salvage = c.salvage[:0]
+	c.result = nil
+
+	// the selected mech is saved so a saved game of the mission can be continued with it
+	return c.saveProgress()
+}
+
+// lancemates returns the roster mechs other than the selected one that do not need repairs, up to the number
+// that fill out a lance with the player
+func (c *Campaign) lancemates(g *Game) []string {
+	mechs := make([]string, 0, CAMPAIGN_MAX_LANCEMATES)
+	for i, r := range c.save.Roster {
+		if len(mechs) >= CAMPAIGN_MAX_LANCEMATES {
+			break
+		}
+		if i == c.save.Selected || c.repairCost(g, i) > 0 {
+			continue
+		}
+		mechs = append(mechs, r.Mech)
+	}
+	return mechs
+}
+
+// completeCampaignMission applies the mission results to the campaign progress and saves it
+func (g *Game) completeCampaignMission() {
+	c := g.campaign
+	cMission := c.Mission()
+	if cMission == nil || g.player == nil {
+		return
+	}
+
+	success := g.objectives != nil && g.objectives.Status() == OBJECTIVES_COMPLETED
+	result := &CampaignResult{Success: success}
+
+	// damage to the player mech carries over to the next mission
+	c.save.Roster[c.save.Selected].State = g.player.Unit.UnitState()
+
+	if success {
+		result.Reward = cMission.Reward
+		c.save.CBills += cMission.Reward
+		c.save.Completed = append(c.save.Completed, cMission.ID)
+
+		// enemy mechs are salvaged in the order they were destroyed, in the state they were destroyed
+		for i := 0; i < len(c.salvage) && i < cMission.Salvage; i++ {
+			mech := c.salvage[i].(*model.Mech)
+			c.save.Roster = append(c.save.Roster, &CampaignRosterMech{
+				Mech:     model.TrimExtension(mech.Resource.File),
+				State:    mech.UnitState(),
+				Salvaged: true,
+			})
+			result.Salvaged = append(result.Salvaged, mech.Name()+" "+mech.Variant())
+		}
+	}
+
+	c.save.Mission = c.campaign.NextMission(cMission.ID, success)
+	if next := c.Mission(); next != nil {
+		result.Next = next.ID
+	}
+	c.salvage = c.salvage[:0]
+	c.result = result
+
+	if err := c.saveProgress(); err != nil {
+		log.Error("error saving campaign progress: ", err)
+	}
+}
+
+func (r *CampaignResult) String() string {
+	if r == nil {
+		return ""
+	}
+
+	resultText := "Failed"
+	if r.Success {
+		resultText = fmt.Sprintf("Successful | Reward: %d C-Bills", r.Reward)
+	}
+	if len(r.Salvaged) > 0 {
+		resultText += " | Salvage: " + strings.Join(r.Salvaged, ", ")
+	}
+	if r.Next == "" {
+		resultText += " | Campaign Complete"
+	}
+	return resultText
+}
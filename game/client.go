@@ -0,0 +1,418 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/harbdog/raycaster-go"
+	"github.com/harbdog/raycaster-go/geom"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	"github.com/pixelmek-3d/pixelmek-3d/game/render/sprites"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Client is the connection to a multiplayer server, which runs the mission while the client pilots the player unit
+type Client struct {
+	conn    *netConn
+	welcome *NetWelcome
+
+	// latest snapshot received concurrently from the server connection
+	mu       sync.Mutex
+	snapshot *NetSnapshot
+	err      error
+
+	// units in play by the sequence of their sprite on the server
+	units     map[uint64]model.Unit
+	sequences map[model.Unit]uint64
+	motion    map[model.Unit]*netMotion
+
+	// units piloted by other clients, with their sprite so they can be removed when they leave
+	players map[model.Unit]*sprites.MechSprite
+
+	// projectiles from the last snapshot that have already been spawned
+	projectiles map[uint64]bool
+
+	// weapons fired by the player since the last input sent
+	fire []int
+}
+
+// netMotion interpolates a unit piloted by the server from its position when the last snapshot was received
+// to its position in the snapshot, as X, Y, Z, heading and turret angle
+type netMotion struct {
+	from, to [5]float64
+	ticks    int
+}
+
+// JoinServer connects to a multiplayer server to join its mission with the given mech and team,
+// so the next mission launched is played on the server
+func (g *Game) JoinServer(address, name, mechFile string, team int) error {
+	unit := g.LoadUnit(model.MechResourceType, mechFile)
+	if unit == nil {
+		return fmt.Errorf("mech not found: %s", mechFile)
+	}
+
+	conn, err := net.DialTimeout("tcp", address, NET_TIMEOUT)
+	if err != nil {
+		return err
+	}
+	c := &Client{
+		conn:        newNetConn(conn),
+		units:       make(map[uint64]model.Unit, 64),
+		sequences:   make(map[model.Unit]uint64, 64),
+		motion:      make(map[model.Unit]*netMotion, 64),
+		players:     make(map[model.Unit]*sprites.MechSprite, 8),
+		projectiles: make(map[uint64]bool, 256),
+	}
+
+	hello := &NetHello{
+		Version: MULTIPLAYER_VERSION,
+		Name:    name,
+		Mech:    mechFile,
+		Team:    team,
+	}
+	if err := c.conn.write(&NetMessage{Hello: hello}); err != nil {
+		c.conn.close()
+		return err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(NET_TIMEOUT))
+	msg, err := c.conn.read()
+	switch {
+	case err != nil:
+	case msg.Error != "":
+		err = errors.New(msg.Error)
+	case msg.Welcome == nil:
+		err = fmt.Errorf("expected welcome message")
+	}
+	if err != nil {
+		c.conn.close()
+		return fmt.Errorf("[%s] %s", address, err.Error())
+	}
+	conn.SetReadDeadline(time.Time{})
+	c.welcome = msg.Welcome
+
+	if _, err := g.LoadMission(c.welcome.Mission); err != nil {
+		c.conn.close()
+		return fmt.Errorf("[%s] %s", address, err.Error())
+	}
+	g.SetPlayerUnit(unit)
+	g.player.SetTeam(team)
+
+	g.client = c
+	go c.readSnapshots()
+	return nil
+}
+
+// readSnapshots receives snapshots from the server until the connection is closed
+func (c *Client) readSnapshots() {
+	for {
+		msg, err := c.conn.read()
+		if err == nil && msg.Error != "" {
+			err = errors.New(msg.Error)
+		}
+		if err != nil {
+			c.mu.Lock()
+			c.err = err
+			c.mu.Unlock()
+			return
+		}
+		if msg.Snapshot == nil {
+			continue
+		}
+
+		c.mu.Lock()
+		c.snapshot = msg.Snapshot
+		c.mu.Unlock()
+	}
+}
+
+// stopClient disconnects from the multiplayer server
+func (g *Game) stopClient() {
+	if g.client == nil {
+		return
+	}
+	g.client.conn.close()
+	g.client = nil
+}
+
+// start seeds the same as the server right before the mission is initialized so mission content is created the same
+func (c *Client) start() {
+	model.SetRandomSeed(c.welcome.Seed)
+}
+
+// initMission maps the mission units by their sprite sequence, which is the same as on the server
+func (c *Client) initMission(g *Game) {
+	// units are piloted by the server
+	g.ai = nil
+
+	for _, spriteType := range g.sprites.SpriteTypes() {
+		if !isInteractiveType(spriteType) {
+			continue
+		}
+		g.sprites.RangeByType(spriteType, func(k, v any) bool {
+			if u := getSpriteFromInterface(k.(raycaster.Sprite)).Unit(); u != nil {
+				c.addUnit(v.(uint64), u)
+			}
+			return true
+		})
+	}
+	c.addUnit(c.welcome.Sequence, g.player.Unit)
+}
+
+func (c *Client) addUnit(sequence uint64, u model.Unit) {
+	c.units[sequence] = u
+	c.sequences[u] = sequence
+}
+
+func (c *Client) removeUnit(sequence uint64, u model.Unit) {
+	delete(c.units, sequence)
+	delete(c.sequences, u)
+	delete(c.motion, u)
+	delete(c.players, u)
+}
+
+// update applies the latest snapshot from the server, ending the mission if the connection was lost
+func (c *Client) update(g *Game) {
+	c.mu.Lock()
+	snapshot, err := c.snapshot, c.err
+	c.snapshot = nil
+	c.mu.Unlock()
+
+	if snapshot != nil {
+		c.applySnapshot(g, snapshot)
+	}
+
+	if !g.InProgress() {
+		return
+	}
+	switch {
+	case err != nil:
+		log.Error("disconnected from server: ", err)
+		g.objectives.fail()
+	case g.player.IsDestroyed():
+		g.objectives.fail()
+	}
+}
+
+// endTick sends the player input for the tick to the server
+func (c *Client) endTick(g *Game) {
+	c.mu.Lock()
+	err := c.err
+	c.mu.Unlock()
+	if err != nil {
+		return
+	}
+
+	p := g.player
+	in := &NetInput{
+		TargetVelocity:      p.TargetVelocity(),
+		TargetVelocityZ:     p.TargetVelocityZ(),
+		TargetHeading:       p.TargetHeading(),
+		TargetTurretAngle:   p.cameraAngle,
+		TargetPitch:         p.cameraPitch,
+		JumpJetsActive:      p.JumpJetsActive(),
+		JumpJetsDirectional: p.JumpJetsDirectional(),
+		JumpJetHeading:      p.JumpJetHeading(),
+		Powered:             p.Powered(),
+		TargetLock:          p.TargetLock(),
+		Fire:                c.fire,
+	}
+	if t := model.EntityUnit(p.Target()); t != nil {
+		in.Target = c.sequences[t]
+	}
+	c.fire = nil
+
+	if err := c.conn.write(&NetMessage{Input: in}); err != nil {
+		c.mu.Lock()
+		c.err = err
+		c.mu.Unlock()
+	}
+}
+
+// fired records a player weapon fired to send with the next input
+func (c *Client) fired(weaponIndex int) {
+	c.fire = append(c.fire, weaponIndex)
+}
+
+func (c *Client) applySnapshot(g *Game, snapshot *NetSnapshot) {
+	// mission progress
+	g.mission.SetTimerSeconds(snapshot.MissionSeconds)
+	if len(snapshot.NavVisited) == len(g.mission.NavPoints) {
+		for i, nav := range g.mission.NavPoints {
+			nav.SetVisited(snapshot.NavVisited[i])
+		}
+	}
+	if err := g.objectives.setObjectiveStates(snapshot.Objectives); err != nil {
+		log.Error("error updating objectives from server: ", err)
+	}
+
+	// units in play
+	inPlay := make(map[uint64]bool, len(snapshot.Units))
+	for _, nu := range snapshot.Units {
+		inPlay[nu.Sequence] = true
+
+		u, ok := c.units[nu.Sequence]
+		if !ok {
+			if u = c.spawnUnit(g, nu); u == nil {
+				continue
+			}
+		}
+
+		if u == g.player.Unit {
+			c.applyPlayerState(g, nu.State)
+		} else {
+			c.applyUnitState(u, nu)
+		}
+	}
+
+	for sequence, u := range c.units {
+		if inPlay[sequence] {
+			continue
+		}
+		if sprite, ok := c.players[u]; ok && !u.IsDestroyed() {
+			// client left the mission
+			g.sprites.DeleteMechSprite(sprite)
+		} else {
+			destroyEntity(u)
+		}
+		c.removeUnit(sequence, u)
+	}
+
+	// projectiles fired by units other than the player, which are spawned locally when fired
+	spawned := make(map[uint64]bool, len(snapshot.Projectiles))
+	fireAudio := make(map[model.Weapon]bool)
+	for _, np := range snapshot.Projectiles {
+		spawned[np.Sequence] = true
+		if c.projectiles[np.Sequence] {
+			continue
+		}
+
+		parent, ok := c.units[np.Parent]
+		if !ok || parent == g.player.Unit || np.State == nil {
+			continue
+		}
+		armament := parent.Armament()
+		if np.Weapon < 0 || np.Weapon >= len(armament) {
+			continue
+		}
+
+		w := armament[np.Weapon]
+		projectile := w.SpawnProjectile(np.State.Heading, np.State.Pitch, parent)
+		if projectile == nil {
+			continue
+		}
+		projectile.SetProjectileState(np.State)
+		g.addProjectileSprite(w, projectile)
+
+		// only play fire audio once per weapon for weapons that fire multiple projectiles
+		if !fireAudio[w] {
+			fireAudio[w] = true
+			g.audio.PlayExternalWeaponFireAudio(g, w, parent)
+		}
+	}
+	c.projectiles = spawned
+}
+
+// spawnUnit creates a unit that was not part of the mission when it was loaded, such as other client units
+func (c *Client) spawnUnit(g *Game, nu *NetUnit) model.Unit {
+	if nu.Mech == "" || nu.State == nil {
+		return nil
+	}
+
+	u := g.LoadUnit(model.MechResourceType, nu.Mech)
+	if u == nil {
+		log.Error("unable to spawn unit from server, mech not found: ", nu.Mech)
+		return nil
+	}
+	u.SetID(nu.ID)
+	u.SetTeam(nu.Team)
+	if err := u.SetUnitMissionState(nu.State); err != nil {
+		log.Errorf("unable to spawn unit from server [%s]: %s", nu.ID, err.Error())
+		return nil
+	}
+
+	sprite := g.CreateUnitSprite(u).(*sprites.MechSprite)
+	g.sprites.AddMechSprite(sprite)
+	if nu.Player {
+		c.players[u] = sprite
+	}
+
+	c.addUnit(nu.Sequence, u)
+	return u
+}
+
+// applyUnitState updates a unit piloted by the server, interpolating from its current position to the snapshot position
+func (c *Client) applyUnitState(u model.Unit, nu *NetUnit) {
+	m, ok := c.motion[u]
+	if !ok {
+		m = &netMotion{}
+		c.motion[u] = m
+	}
+	pos := u.Pos()
+	m.from = [5]float64{pos.X, pos.Y, u.PosZ(), u.Heading(), u.TurretAngle()}
+
+	if err := u.SetUnitMissionState(nu.State); err != nil {
+		log.Errorf("error updating unit from server [%s]: %s", u.ID(), err.Error())
+		return
+	}
+	if mech, ok := u.(*model.Mech); ok {
+		mech.PowerOnTimer, mech.PowerOffTimer = nu.PowerOnTimer, nu.PowerOffTimer
+	}
+
+	pos = u.Pos()
+	m.to = [5]float64{pos.X, pos.Y, u.PosZ(), u.Heading(), u.TurretAngle()}
+	if !ok {
+		// nothing to interpolate from the first time
+		m.from = m.to
+	}
+	m.ticks = 0
+}
+
+// applyPlayerState updates the player unit damage and heat from the server, keeping the player piloting
+// and only correcting the player position when it is too far off from the server
+func (c *Client) applyPlayerState(g *Game, state *model.UnitMissionState) {
+	if state == nil {
+		return
+	}
+
+	p := g.player
+	local := p.UnitMissionState()
+	local.UnitState = state.UnitState
+	local.Heat = state.Heat
+
+	pos := p.Pos()
+	if geom.Distance(pos.X, pos.Y, state.Position[0], state.Position[1]) > NET_CORRECTION_DISTANCE ||
+		math.Abs(p.PosZ()-state.PositionZ) > NET_CORRECTION_DISTANCE {
+		local.Position, local.PositionZ = state.Position, state.PositionZ
+	}
+
+	if err := p.SetUnitMissionState(local); err != nil {
+		log.Error("error updating player from server: ", err)
+	}
+}
+
+// interpolate moves a unit piloted by the server towards its last snapshot position,
+// returns false if the unit is not piloted by the server
+func (c *Client) interpolate(u model.Unit) bool {
+	m, ok := c.motion[u]
+	if !ok {
+		return false
+	}
+
+	m.ticks++
+	t := math.Min(float64(m.ticks)/NET_SNAPSHOT_TICKS, 1)
+	lerp := func(i int) float64 {
+		return m.from[i] + (m.to[i]-m.from[i])*t
+	}
+
+	u.SetPos(&geom.Vector2{X: lerp(0), Y: lerp(1)})
+	u.SetPosZ(lerp(2))
+	u.SetHeading(lerpAngle(m.from[3], m.to[3], t))
+	u.SetTurretAngle(lerpAngle(m.from[4], m.to[4], t))
+	return true
+}
@@ -1,7 +1,6 @@
 package game
 
 import (
-	"image"
 	"math"
 	"sort"
 
@@ -12,6 +11,11 @@ import (
 	"github.com/pixelmek-3d/pixelmek-3d/game/render/sprites"
 )
 
+const (
+	// distance between points along the line of sight checked against elevated terrain
+	LINE_OF_SIGHT_STEP float64 = 0.25
+)
+
 type EntityCollision struct {
 	entity     model.Entity
 	collision  *geom.Vector2
@@ -36,7 +40,7 @@ func (g *Game) isCollisionType(spriteType sprites.SpriteType) bool {
 	return false
 }
 
-// checks walls for line of sight from source to target entity
+// checks walls and elevated terrain for line of sight from source to target entity
 func (g *Game) lineOfSight(source, target model.Entity) bool {
 	if source == nil || target == nil {
 		return false
@@ -46,17 +50,24 @@ func (g *Game) lineOfSight(source, target model.Entity) bool {
 	tgtX, tgtY := target.Pos().X, target.Pos().Y
 
 	line := geom.Line{X1: srcX, Y1: srcY, X2: tgtX, Y2: tgtY}
-	lRect := image.Rect(int(srcX), int(srcY), int(tgtX), int(tgtY))
-	for _, borderLine := range g.collisionMap {
-		// quick check for nearby wall cells instead of all of them
-		bRect := image.Rect(int(borderLine.X1), int(borderLine.Y1), int(borderLine.X2), int(borderLine.Y2))
-		if (bRect.Min.X > lRect.Max.X && bRect.Min.Y > lRect.Max.Y) ||
-			(bRect.Max.X < lRect.Min.X && bRect.Max.Y < lRect.Min.Y) {
-			continue
-		}
 
-		_, _, intersects := geom.LineIntersection(line, *borderLine)
-		if intersects {
+	if g.wallBlocksLine(line) {
+		return false
+	}
+
+	m := g.mission.Map()
+	if !m.HasElevation() {
+		return true
+	}
+
+	// check elevated terrain along the line from the top of the source to the center of the target
+	srcZ := source.PosZ() + source.CollisionHeight()
+	tgtZ := target.PosZ() + target.CollisionHeight()/2
+	dist := line.Distance()
+	for d := LINE_OF_SIGHT_STEP; d < dist; d += LINE_OF_SIGHT_STEP {
+		t := d / dist
+		x, y, z := srcX+t*(tgtX-srcX), srcY+t*(tgtY-srcY), srcZ+t*(tgtZ-srcZ)
+		if m.HeightAt(x, y) > z {
 			return false
 		}
 	}
@@ -64,6 +75,17 @@ func (g *Game) lineOfSight(source, target model.Entity) bool {
 	return true
 }
 
+// wallBlocksLine returns true if any wall is in the way along the line
+func (g *Game) wallBlocksLine(line geom.Line) bool {
+	// only check walls in the spatial index cells along the line instead of all of them
+	wallBlocked := false
+	g.spatial.RangeWallsOnLine(line, func(borderLine *geom.Line) bool {
+		_, _, wallBlocked = geom.LineIntersection(line, *borderLine)
+		return !wallBlocked
+	})
+	return wallBlocked
+}
+
 // checks for valid move from current position, returns valid (x, y) position, whether a collision
 // was encountered, and a list of entity collisions that may have been encountered
 func (g *Game) getValidMove(entity model.Entity, moveX, moveY, moveZ float64, checkAlternate bool) (*geom.Vector2, float64, bool, []*EntityCollision) {
@@ -89,15 +111,23 @@ func (g *Game) getValidMove(entity model.Entity, moveX, moveY, moveZ float64, ch
 	intersectPoints := []geom.Vector2{}
 	collisionEntities := []*EntityCollision{}
 
-	// check wall collisions
-	for _, borderLine := range g.collisionMap {
+	// check wall collisions near the move
+	moveMinX, moveMinY := math.Min(posX, newX), math.Min(posY, newY)
+	moveMaxX, moveMaxY := math.Max(posX, newX), math.Max(posY, newY)
+	g.spatial.RangeWalls(moveMinX, moveMinY, moveMaxX, moveMaxY, func(borderLine *geom.Line) bool {
 		if px, py, ok := geom.LineIntersection(moveLine, *borderLine); ok {
 			intersectPoints = append(intersectPoints, geom.Vector2{X: px, Y: py})
 		}
+		return true
+	})
+
+	// check elevation collisions, too high to step up onto without jumping
+	if !g.mission.Map().CanStepUp(math.Max(posZ, newZ), newX, newY) {
+		intersectPoints = append(intersectPoints, geom.Vector2{X: newX, Y: newY})
 	}
 
-	// check sprite against player collision
-	if entity != g.player.Unit && entity.Parent() != g.player.Unit && !entity.IsDestroyed() {
+	// check sprite against player collision, except on a multiplayer server where the player is only an observer
+	if g.server == nil && entity != g.player.Unit && entity.Parent() != g.player.Unit && !entity.IsDestroyed() {
 		// only check for collision if player is somewhat nearby
 		playerPosition := g.player.Pos()
 		playerCollisionRadius := g.player.CollisionRadius()
@@ -126,71 +156,66 @@ func (g *Game) getValidMove(entity model.Entity, moveX, moveY, moveZ float64, ch
 		}
 	}
 
-	// check sprite collisions
-	for _, spriteType := range g.sprites.SpriteTypes() {
-		if !g.isCollisionType(spriteType) {
-			// only check collision against certain sprite types (skip projectiles, effects, etc.)
-			continue
+	// check sprite collisions, only against entities in the spatial index near the move
+	// (which only has certain sprite types, skipping projectiles, effects, etc.)
+	eMinX, eMinY := moveMinX-entityCollisionRadius, moveMinY-entityCollisionRadius
+	eMaxX, eMaxY := moveMaxX+entityCollisionRadius, moveMaxY+entityCollisionRadius
+	g.spatial.RangeEntities(eMinX, eMinY, eMaxX, eMaxY, func(sEntity model.Entity) bool {
+		if entity == sEntity || entity.Parent() == sEntity || sEntity.CollisionRadius() <= 0 || sEntity.IsDestroyed() {
+			return true
 		}
-		g.sprites.RangeByType(spriteType, func(k, _ any) bool {
-			spriteInterface := k.(raycaster.Sprite)
-			sEntity := getEntityFromInterface(spriteInterface)
-			if entity == sEntity || entity.Parent() == sEntity || sEntity.CollisionRadius() <= 0 || sEntity.IsDestroyed() {
-				return true
-			}
-
-			sEntityPosition := sEntity.Pos()
-			sEntityCr := sEntity.CollisionRadius()
-
-			// only check intersection of nearby sprites instead of all of them
-			if !model.PointInProximity(checkDist, newX, newY, sEntityPosition.X, sEntityPosition.Y) {
-				return true
-			}
-
-			// quick check if intersects in Z-plane
-			zIntersect := zEntityIntersection(newZ, entity, sEntity)
-			if zIntersect < 0 {
-				return true
-			}
 
-			// check if movement line intersects with combined collision radii
-			combinedCircle := geom.Circle{X: sEntityPosition.X, Y: sEntityPosition.Y, Radius: sEntityCr + entityCollisionRadius}
-			combinedIntersects := geom.LineCircleIntersection(moveLine, combinedCircle, true)
+		sEntityPosition := sEntity.Pos()
+		sEntityCr := sEntity.CollisionRadius()
 
-			circleHit := false
-			if len(combinedIntersects) > 0 {
-				spriteCircle := geom.Circle{X: sEntityPosition.X, Y: sEntityPosition.Y, Radius: sEntityCr}
-				for _, chkPoint := range combinedIntersects {
-					// intersections from combined circle radius indicate center point to check intersection toward sprite collision circle
-					chkLine := geom.Line{X1: chkPoint.X, Y1: chkPoint.Y, X2: sEntityPosition.X, Y2: sEntityPosition.Y}
-					chkLineIntersects := geom.LineCircleIntersection(chkLine, spriteCircle, true)
-					intersectPoints = append(intersectPoints, chkLineIntersects...)
+		// only check intersection of nearby sprites instead of all of them
+		if !model.PointInProximity(checkDist, newX, newY, sEntityPosition.X, sEntityPosition.Y) {
+			return true
+		}
 
-					for _, intersect := range chkLineIntersects {
-						circleHit = true
-						collisionEntities = append(
-							collisionEntities, &EntityCollision{entity: sEntity, collision: &intersect, collisionZ: zIntersect},
-						)
-					}
-				}
-			}
+		// quick check if intersects in Z-plane
+		zIntersect := zEntityIntersection(newZ, entity, sEntity)
+		if zIntersect < 0 {
+			return true
+		}
 
-			if !circleHit {
-				// check if move point could be inside the circle without touching it
-				chkLine := geom.Line{X1: newX, Y1: newY, X2: sEntityPosition.X, Y2: sEntityPosition.Y}
-				chkLineDist := chkLine.Distance()
-				if chkLineDist <= combinedCircle.Radius {
-					chkPoint := geom.Vector2{X: newX, Y: newY}
-					intersectPoints = append(intersectPoints, chkPoint)
+		// check if movement line intersects with combined collision radii
+		combinedCircle := geom.Circle{X: sEntityPosition.X, Y: sEntityPosition.Y, Radius: sEntityCr + entityCollisionRadius}
+		combinedIntersects := geom.LineCircleIntersection(moveLine, combinedCircle, true)
+
+		circleHit := false
+		if len(combinedIntersects) > 0 {
+			spriteCircle := geom.Circle{X: sEntityPosition.X, Y: sEntityPosition.Y, Radius: sEntityCr}
+			for _, chkPoint := range combinedIntersects {
+				// intersections from combined circle radius indicate center point to check intersection toward sprite collision circle
+				chkLine := geom.Line{X1: chkPoint.X, Y1: chkPoint.Y, X2: sEntityPosition.X, Y2: sEntityPosition.Y}
+				chkLineIntersects := geom.LineCircleIntersection(chkLine, spriteCircle, true)
+				intersectPoints = append(intersectPoints, chkLineIntersects...)
+
+				for _, intersect := range chkLineIntersects {
+					circleHit = true
 					collisionEntities = append(
-						collisionEntities, &EntityCollision{entity: sEntity, collision: &chkPoint, collisionZ: zIntersect},
+						collisionEntities, &EntityCollision{entity: sEntity, collision: &intersect, collisionZ: zIntersect},
 					)
 				}
 			}
+		}
 
-			return true
-		})
-	}
+		if !circleHit {
+			// check if move point could be inside the circle without touching it
+			chkLine := geom.Line{X1: newX, Y1: newY, X2: sEntityPosition.X, Y2: sEntityPosition.Y}
+			chkLineDist := chkLine.Distance()
+			if chkLineDist <= combinedCircle.Radius {
+				chkPoint := geom.Vector2{X: newX, Y: newY}
+				intersectPoints = append(intersectPoints, chkPoint)
+				collisionEntities = append(
+					collisionEntities, &EntityCollision{entity: sEntity, collision: &chkPoint, collisionZ: zIntersect},
+				)
+			}
+		}
+
+		return true
+	})
 
 	// sort collisions by distance to current entity position
 	sort.Slice(collisionEntities, func(i, j int) bool {
@@ -334,18 +359,18 @@ func (g *Game) getValidMove(entity model.Entity, moveX, moveY, moveZ float64, ch
 	}
 
 	worldMap := g.mission.Map().Level(0)
-	if worldMap[ix][iy] <= 0 {
+	if worldMap[ix][iy] <= 0 || g.mission.Map().IsWalkableWall(ix, iy) {
 		posX = newX
 		posY = newY
 	} else {
 		isCollision = true
 	}
 
-	// prevent going under the floor
+	// prevent going under the floor, or the elevated terrain beneath
 	// TODO: prevent going above flight ceiling (set in map yaml?)
 	posZ = newZ
 	zMin, _ := zEntityMinMax(0, entity)
-	zMin = math.Abs(zMin)
+	zMin = math.Abs(zMin) + g.mission.Map().HeightAt(posX, posY)
 	if posZ < zMin {
 		posZ = zMin
 		isCollision = true
@@ -18,6 +18,12 @@ const (
 	baseLockOnPitchOffset float64 = geom.Pi / 12
 )
 
+// criticalRoll is a unit location waiting to be rolled for critical hits
+type criticalRoll struct {
+	unit     model.Unit
+	location model.Location
+}
+
 type ProjectileSpawn struct {
 	delay      float64
 	spread     float64
@@ -27,7 +33,13 @@ type ProjectileSpawn struct {
 }
 
 func (g *Game) initCombatVariables() {
-	g.delayedProjectiles = make(map[*ProjectileSpawn]struct{}, 256)
+	g.delayedProjectiles = make([]*ProjectileSpawn, 0, 256)
+	g.combatRNG = model.NewRNG()
+}
+
+// SetCombatSeed sets the seed used for combat rolls, such as critical hits, for reproducible results
+func (g *Game) SetCombatSeed(seed int64) {
+	g.combatRNG = model.NewSeededRNG(seed)
 }
 
 func NewProjectileSpawn(weapon model.Weapon, parent model.Entity) *ProjectileSpawn {
@@ -56,9 +68,15 @@ func destroyEntity(e model.Entity) {
 	}
 }
 
-// applyDamage applies the base damage amount to a target entity, taking into account any game modifiers/multipliers
-func (g *Game) applyDamage(source, target model.Entity, damage float64) {
-	isSourcePlayer, isTargetPlayer := source == g.player.Unit, target == g.player.Unit
+// applyDamage applies the base damage amount to a target entity at the hit location (if applicable),
+// taking into account any game modifiers/multipliers
+func (g *Game) applyDamage(source, target model.Entity, damage float64, hitLocation model.Location) {
+	if g.client != nil && model.EntityUnit(target) != nil {
+		// damage to units is applied by the multiplayer server
+		return
+	}
+
+	isSourcePlayer, isTargetPlayer := g.isPlayerUnit(source), g.isPlayerUnit(target)
 	isFriendly := (isSourcePlayer || isTargetPlayer) && g.IsFriendly(source, target)
 	if !g.difficulty.FriendlyFireEnabled && isFriendly {
 		// friendly fire disabled, no damage to apply
@@ -73,19 +91,64 @@ func (g *Game) applyDamage(source, target model.Entity, damage float64) {
 		multiplier = g.difficulty.EnemyDamageTakenModifier
 	}
 
-	target.ApplyDamage(damage * multiplier)
+	// projectile updates are concurrent, only apply damage one at a time
+	g.damageMu.Lock()
+	defer g.damageMu.Unlock()
+
+	unit := model.EntityUnit(target)
+	if unit != nil && hitLocation > 0 {
+		damaged := unit.ApplyLocationDamage(damage*multiplier, hitLocation)
+		for _, location := range damaged {
+			// critical hits are rolled after the projectile updates so combat rolls are not drawn concurrently
+			g.critRolls = append(g.critRolls, criticalRoll{unit: unit, location: location})
+		}
+	} else {
+		target.ApplyDamage(damage * multiplier)
+	}
 
 	if g.debug {
-		unit := model.EntityUnit(target)
 		hp, maxHP := target.ArmorPoints()+target.StructurePoints(), target.MaxArmorPoints()+target.MaxStructurePoints()
 		percentHP := 100 * (hp / maxHP)
 
 		if unit == g.player.Unit {
 			// TODO: visual response to player being hit
-			log.Debugf("[player] hit for %0.1f | multiplier: %0.1fx | HP: %0.1f/%0.0f (%0.2f%%)", damage, multiplier, hp, maxHP, percentHP)
+			log.Debugf("[player] hit %s for %0.1f | multiplier: %0.1fx | HP: %0.1f/%0.0f (%0.2f%%)", hitLocation.ShortName(), damage, multiplier, hp, maxHP, percentHP)
 		} else if unit != nil {
 			// TODO: ui indicator for showing damage was done
-			log.Debugf("[%s] hit for %0.1f | multiplier: %0.1fx | HP: %0.1f/%0.0f (%0.2f%%)", unit.ID(), damage, multiplier, hp, maxHP, percentHP)
+			log.Debugf("[%s] hit %s for %0.1f | multiplier: %0.1fx | HP: %0.1f/%0.0f (%0.2f%%)", unit.ID(), hitLocation.ShortName(), damage, multiplier, hp, maxHP, percentHP)
+		}
+	}
+}
+
+// rollCriticalHits rolls for critical hits on each unit location that took structure damage from projectiles,
+// in the order they were hit which is only reproducible when projectiles are updated sequentially
+func (g *Game) rollCriticalHits() {
+	for _, r := range g.critRolls {
+		g.applyCriticalHits(r.unit, r.location)
+	}
+	clear(g.critRolls)
+	g.critRolls = g.critRolls[:0]
+}
+
+// applyCriticalHits rolls for critical hits on a unit location that has taken structure damage
+func (g *Game) applyCriticalHits(unit model.Unit, location model.Location) {
+	if unit.IsDestroyed() {
+		return
+	}
+
+	hits := unit.ApplyCriticalHits(location, g.combatRNG)
+	for _, hit := range hits {
+		if g.debug {
+			log.Debugf("[%s] critical hit: %s", unit.ID(), hit)
+		}
+
+		if hit.Type == model.CRITICAL_AMMO_EXPLOSION {
+			// visual effect for ammo explosion at the unit
+			s := g.getSpriteFromEntity(unit)
+			if s != nil {
+				x, y, z := s.Pos().X, s.Pos().Y, s.PosZ()+s.CollisionHeight()/2
+				g.sprites.AddEffect(g.randExplosionEffect(x, y, z, s.Heading(), 0))
+			}
 		}
 	}
 }
@@ -140,6 +203,10 @@ func (g *Game) firePlayerWeapon(weaponGroupFire int) bool {
 
 		if g.fireUnitWeapon(g.player.Unit, weapon) {
 			weaponsFired = true
+			if g.client != nil {
+				// the server fires the same weapon for the player unit
+				g.client.fired(i)
+			}
 		} else {
 			missileWeapon, isMissile := weapon.(*model.MissileWeapon)
 			if isMissile && missileWeapon.IsLockOnLockRequired() {
@@ -164,7 +231,7 @@ func (g *Game) fireUnitWeapon(unit model.Unit, weapon model.Weapon) bool {
 		return false
 	}
 
-	if weapon.Cooldown() > 0 {
+	if weapon.Destroyed() || weapon.Cooldown() > 0 {
 		return false
 	}
 
@@ -219,7 +286,12 @@ func (g *Game) updateProjectiles() {
 		}
 
 		wg.Add(1)
-		go g.asyncProjectileUpdate(p, &wg)
+		if g.deterministic() {
+			// sequential projectile updates for consistent simulation and replay results
+			g.asyncProjectileUpdate(p, &wg)
+		} else {
+			go g.asyncProjectileUpdate(p, &wg)
+		}
 
 		return true
 	})
@@ -236,6 +308,7 @@ func (g *Game) updateProjectiles() {
 	})
 
 	wg.Wait()
+	g.rollCriticalHits()
 }
 
 // asyncProjectileUpdate updates the positions of a projectile in a parallel fashion
@@ -331,15 +404,21 @@ func (g *Game) asyncProjectileUpdate(p *sprites.ProjectileSprite, wg *sync.WaitG
 		zCheck := trajectory.Z2
 
 		newPos, newPosZ, isCollision, collisions := g.getValidMove(p.Entity, xCheck, yCheck, zCheck, false)
-		if isCollision || p.PosZ() <= 0 {
+		if isCollision || p.PosZ() <= g.mission.Map().HeightAt(p.Pos().X, p.Pos().Y) {
 			var collisionEntity *EntityCollision
 			if len(collisions) > 0 {
 				// apply damage to the first sprite entity that was hit
 				collisionEntity = collisions[0]
 				entity := collisionEntity.entity
 
+				// determine location on the unit hit based on the collision point and height
+				var hitLocation model.Location
+				if unit := model.EntityUnit(entity); unit != nil {
+					hitLocation = unit.HitLocation(collisionEntity.collision, collisionEntity.collisionZ)
+				}
+
 				damage := p.Damage()
-				g.applyDamage(p.Parent(), entity, damage)
+				g.applyDamage(p.Parent(), entity, damage, hitLocation)
 			}
 
 			// destroy projectile after applying damage so it can calculate dropoff if needed
@@ -409,18 +488,21 @@ func (g *Game) queueDelayedProjectile(pIndex int, w model.Weapon, e model.Entity
 	}
 
 	p := NewDelayedProjectileSpawn(delay, spread, w, e, playSFX)
-	g.delayedProjectiles[p] = struct{}{}
+	g.delayedProjectiles = append(g.delayedProjectiles, p)
 }
 
 // updateDelayedProjectiles updates timers on delayed projectiles and spawns them as they finish counting down
 func (g *Game) updateDelayedProjectiles() {
-	for p := range g.delayedProjectiles {
+	remaining := g.delayedProjectiles[:0]
+	for _, p := range g.delayedProjectiles {
 		p.delay -= model.SECONDS_PER_TICK
 		if p.delay <= 0 {
-			delete(g.delayedProjectiles, p)
 			g.spawnProjectile(p)
+		} else {
+			remaining = append(remaining, p)
 		}
 	}
+	g.delayedProjectiles = remaining
 }
 
 // spawnProjectile puts a projectile in play
@@ -436,12 +518,17 @@ func (g *Game) spawnProjectile(p *ProjectileSpawn) *model.Projectile {
 	var spreadAngle, spreadPitch float64
 	if p.spread > 0 {
 		// randomly generate spread for this projectile
-		spreadAngle = randFloat(-p.spread, p.spread)
-		spreadPitch = randFloat(-p.spread, p.spread)
+		spreadAngle = g.combatRNG.RandFloat64In(-p.spread, p.spread)
+		spreadPitch = g.combatRNG.RandFloat64In(-p.spread, p.spread)
+	}
+	if band := u.HeatScaleBand(); band != nil && band.AimJitter > 0 {
+		// heat throws off weapon convergence
+		spreadAngle += g.combatRNG.RandFloat64In(-band.AimJitter, band.AimJitter)
+		spreadPitch += g.combatRNG.RandFloat64In(-band.AimJitter, band.AimJitter)
 	}
 
 	var convergencePoint *geom3d.Vector3
-	if isPlayerProjectile {
+	if isPlayerProjectile && !g.player.autopilot {
 		cSprite := g.spriteInCrosshairs()
 		var cEntity model.Entity
 		if cSprite != nil {
@@ -465,7 +552,7 @@ func (g *Game) spawnProjectile(p *ProjectileSpawn) *model.Projectile {
 
 	if useConvergencePoint {
 		projectile = w.SpawnProjectileToward(convergencePoint, u)
-		if p.spread > 0 {
+		if spreadAngle != 0 || spreadPitch != 0 {
 			projectile.SetHeading(projectile.Heading() + spreadAngle)
 			projectile.SetPitch(projectile.Pitch() + spreadPitch)
 		}
@@ -478,11 +565,13 @@ func (g *Game) spawnProjectile(p *ProjectileSpawn) *model.Projectile {
 	}
 
 	if projectile != nil {
-		pTemplate := projectileSpriteForWeapon(w)
-		pSprite := pTemplate.Clone()
-		pSprite.Projectile = projectile
-		pSprite.Entity = projectile
-		g.sprites.AddProjectile(pSprite)
+		g.addProjectileSprite(w, projectile)
+
+		if fxKey := w.MuzzleEffect(); fxKey != "" {
+			// weapon specific muzzle effect at the projectile spawn point
+			pPos := projectile.Pos()
+			g.sprites.AddEffect(g.effect(fxKey, pPos.X, pPos.Y, projectile.PosZ()))
+		}
 
 		if p.sfxEnabled {
 			if u == g.player.Unit {
@@ -500,3 +589,12 @@ func (g *Game) spawnProjectile(p *ProjectileSpawn) *model.Projectile {
 	}
 	return projectile
 }
+
+// addProjectileSprite adds the sprite for a projectile fired from the weapon
+func (g *Game) addProjectileSprite(w model.Weapon, projectile *model.Projectile) *sprites.ProjectileSprite {
+	pSprite := projectileSpriteForWeapon(w).Clone()
+	pSprite.Projectile = projectile
+	pSprite.Entity = projectile
+	g.sprites.AddProjectile(pSprite)
+	return pSprite
+}
@@ -49,3 +49,12 @@ func (s *FIFOStack[T]) Pop() *T {
 func (s *FIFOStack[T]) Len() int {
 	return s.list.Len()
 }
+
+// Values returns the elements in the stack in order without removing them
+func (s *FIFOStack[T]) Values() []T {
+	values := make([]T, 0, s.list.Len())
+	for e := s.list.Front(); e != nil; e = e.Next() {
+		values = append(values, e.Value.(T))
+	}
+	return values
+}
@@ -21,6 +21,7 @@ const (
 	PARAM_KEY_DEBUG         = "debug"
 	PARAM_KEY_BENCHMARK     = "benchmark"
 	PARAM_KEY_IGNORE_PLAYER = "ignore-player"
+	PARAM_KEY_AI_TRACE      = "ai-trace"
 
 	CONFIG_KEY_GAME_DIFFICULTY = "game.difficulty"
 
@@ -60,6 +61,7 @@ func (g *Game) initConfig() {
 	// handle global flag values
 	g.benchmark = globalViper.GetBool(PARAM_KEY_BENCHMARK)
 	g.debug = globalViper.GetBool(PARAM_KEY_DEBUG)
+	g.traceAI = globalViper.GetBool(PARAM_KEY_AI_TRACE)
 	if g.debug {
 		log.SetLevel(log.DebugLevel)
 
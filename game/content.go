@@ -154,6 +154,57 @@ func (g *Game) loadMissionSprites() {
 		emplacement := g.CreateUnitSprite(modelEmplacement).(*sprites.EmplacementSprite)
 		g.sprites.AddEmplacementSprite(emplacement)
 	}
+
+	for _, lance := range g.mission.Lances {
+		g.loadMissionLance(lance)
+	}
+
+	if g.server != nil || g.client != nil {
+		// lancemates follow the player, there is no one player for them to follow in multiplayer
+		return
+	}
+	for _, missionMech := range g.mission.Lancemates {
+		modelMech, err := createMissionUnitModel[model.Mech](g, missionMech)
+		if err != nil {
+			log.Errorf("error creating lancemate mech: %v", err)
+			continue
+		}
+		mech := g.CreateUnitSprite(modelMech).(*sprites.MechSprite)
+		g.sprites.AddMechSprite(mech)
+	}
+}
+
+// loadMissionLance loads the member units of a mission lance, which already have the lance orders applied
+func (g *Game) loadMissionLance(lance *model.MissionLance) {
+	for _, missionMech := range lance.Mechs {
+		modelMech, err := createMissionUnitModel[model.Mech](g, missionMech)
+		if err != nil {
+			log.Errorf("error creating lance '%s' mech: %v", lance.Name, err)
+			continue
+		}
+		mech := g.CreateUnitSprite(modelMech).(*sprites.MechSprite)
+		g.sprites.AddMechSprite(mech)
+	}
+
+	for _, missionVehicle := range lance.Vehicles {
+		modelVehicle, err := createMissionUnitModel[model.Vehicle](g, missionVehicle)
+		if err != nil {
+			log.Errorf("error creating lance '%s' vehicle: %v", lance.Name, err)
+			continue
+		}
+		vehicle := g.CreateUnitSprite(modelVehicle).(*sprites.VehicleSprite)
+		g.sprites.AddVehicleSprite(vehicle)
+	}
+
+	for _, missionInfantry := range lance.Infantry {
+		modelInfantry, err := createMissionUnitModel[model.Infantry](g, missionInfantry)
+		if err != nil {
+			log.Errorf("error creating lance '%s' infantry: %v", lance.Name, err)
+			continue
+		}
+		infantry := g.CreateUnitSprite(modelInfantry).(*sprites.InfantrySprite)
+		g.sprites.AddInfantrySprite(infantry)
+	}
 }
 
 func createMissionUnitModel[T model.MissionUnitModels](g *Game, unit model.MissionUnit) (model.Unit, error) {
@@ -196,6 +247,7 @@ func createMissionUnitModel[T model.MissionUnitModels](g *Game, unit model.Missi
 
 	u.SetPowerConditions(unit.PowerConditions)
 
+	u.SetPilot(g.missionPilot(unit.Pilot))
 	u.SetGuardUnit(unit.GuardUnit)
 	if len(unit.GuardArea.Position) == 2 && unit.GuardArea.Radius >= 0 &&
 		unit.GuardArea.Position[0] > 0 && unit.GuardArea.Position[1] > 0 {
@@ -239,6 +291,7 @@ func createMissionFlyingUnitModel[T model.MissionFlyingUnitModels](g *Game, unit
 	u.SetTurretAngle(rHeading)
 	u.SetTargetTurretAngle(rHeading)
 
+	u.SetPilot(g.missionPilot(unit.Pilot))
 	u.SetGuardUnit(unit.GuardUnit)
 	if len(unit.GuardArea.Position) == 2 && unit.GuardArea.Radius >= 0 &&
 		unit.GuardArea.Position[0] > 0 && unit.GuardArea.Position[1] > 0 {
@@ -281,9 +334,28 @@ func createMissionStaticUnitModel[T model.MissionStaticUnitModels](g *Game, unit
 	u.SetTurretAngle(rHeading)
 	u.SetTargetTurretAngle(rHeading)
 
+	u.SetPilot(g.missionPilot(unit.Pilot))
+
 	return u, nil
 }
 
+// missionPilot creates the pilot of a mission unit, using difficulty level default skills for any not given
+func (g *Game) missionPilot(mPilot *model.MissionPilot) *model.Pilot {
+	pilot := g.difficulty.DefaultPilot()
+	if mPilot == nil {
+		return pilot
+	}
+
+	pilot.Name = mPilot.Name
+	if mPilot.Gunnery != nil {
+		pilot.Gunnery = *mPilot.Gunnery
+	}
+	if mPilot.Piloting != nil {
+		pilot.Piloting = *mPilot.Piloting
+	}
+	return model.NewPilot(pilot.Name, pilot.Gunnery, pilot.Piloting)
+}
+
 func (g *Game) createModelMechFromResource(mechResource *model.ModelMechResource) *model.Mech {
 	m := model.NewMech(mechResource)
 	g.loadUnitWeapons(m, mechResource.Armament, m.PixelWidth(), m.PixelHeight(), m.PixelScale())
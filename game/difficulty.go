@@ -1,5 +1,7 @@
 package game
 
+import "github.com/pixelmek-3d/pixelmek-3d/game/model"
+
 var DifficultyLevels []*DifficultyLevel
 
 type DifficultyLevel struct {
@@ -7,12 +9,19 @@ type DifficultyLevel struct {
 	EnemyDamageTakenModifier  float64
 	PlayerDamageTakenModifier float64
 	FriendlyFireEnabled       bool
+	PilotGunnery              int
+	PilotPiloting             int
 }
 
 func (d *DifficultyLevel) String() string {
 	return d.Name
 }
 
+// DefaultPilot returns a new pilot with the skills used for units that do not specify their own
+func (d *DifficultyLevel) DefaultPilot() *model.Pilot {
+	return model.NewPilot("", d.PilotGunnery, d.PilotPiloting)
+}
+
 func init() {
 	DifficultyLevels = []*DifficultyLevel{
 		difficultyRecruit(),
@@ -28,6 +37,8 @@ func difficultyRecruit() *DifficultyLevel {
 		EnemyDamageTakenModifier:  4.0,
 		PlayerDamageTakenModifier: 0.5,
 		FriendlyFireEnabled:       false,
+		PilotGunnery:              5,
+		PilotPiloting:             6,
 	}
 }
 
@@ -37,6 +48,8 @@ func difficultyRegular() *DifficultyLevel {
 		EnemyDamageTakenModifier:  2.5,
 		PlayerDamageTakenModifier: 1.0,
 		FriendlyFireEnabled:       false,
+		PilotGunnery:              4,
+		PilotPiloting:             5,
 	}
 }
 
@@ -46,6 +59,8 @@ func difficultyVeteran() *DifficultyLevel {
 		EnemyDamageTakenModifier:  1.5,
 		PlayerDamageTakenModifier: 1.0,
 		FriendlyFireEnabled:       true,
+		PilotGunnery:              3,
+		PilotPiloting:             4,
 	}
 }
 
@@ -55,5 +70,7 @@ func difficultyAce() *DifficultyLevel {
 		EnemyDamageTakenModifier:  1.0,
 		PlayerDamageTakenModifier: 1.5,
 		FriendlyFireEnabled:       true,
+		PilotGunnery:              2,
+		PilotPiloting:             3,
 	}
 }
@@ -3,7 +3,6 @@ package game
 import (
 	"fmt"
 	"math"
-	"math/rand"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/harbdog/raycaster-go/geom"
@@ -11,7 +10,6 @@ import (
 	renderFx "github.com/pixelmek-3d/pixelmek-3d/game/render/effects"
 	"github.com/pixelmek-3d/pixelmek-3d/game/render/sprites"
 	"github.com/pixelmek-3d/pixelmek-3d/game/resources"
-	"github.com/pixelmek-3d/pixelmek-3d/game/resources/effects"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -28,18 +26,13 @@ var (
 	jumpJetEffect     *sprites.EffectSprite
 	attachedJJEffects map[*sprites.Sprite]*sprites.EffectSprite
 
-	bloodEffects     map[string]*sprites.EffectSprite
-	explosionEffects map[string]*sprites.EffectSprite
-	fireEffects      map[string]*sprites.EffectSprite
-	smokeEffects     map[string]*sprites.EffectSprite
+	// effect sprite templates by effect resource key
+	effectSprites map[string]*sprites.EffectSprite
 )
 
 func init() {
 	attachedJJEffects = make(map[*sprites.Sprite]*sprites.EffectSprite)
-	bloodEffects = make(map[string]*sprites.EffectSprite)
-	explosionEffects = make(map[string]*sprites.EffectSprite)
-	fireEffects = make(map[string]*sprites.EffectSprite)
-	smokeEffects = make(map[string]*sprites.EffectSprite)
+	effectSprites = make(map[string]*sprites.EffectSprite)
 }
 
 func (g *Game) loadSpecialEffects() {
@@ -50,19 +43,10 @@ func (g *Game) loadSpecialEffects() {
 	_loadEjectionPodResource(g)
 
 	// load the jump jet effect sprite template
-	_loadJumpJetEffectResource()
+	_loadJumpJetEffectResource(g)
 
-	// load the blood effect sprite templates
-	_loadEffectSpritesFromResourceList(effects.Blood, bloodEffects)
-
-	// load the explosion effect sprite templates
-	_loadEffectSpritesFromResourceList(effects.Explosions, explosionEffects)
-
-	// load the fire effect sprite templates
-	_loadEffectSpritesFromResourceList(effects.Fires, fireEffects)
-
-	// load the smoke effect sprite templates
-	_loadEffectSpritesFromResourceList(effects.Smokes, smokeEffects)
+	// load the blood, explosion, fire, smoke and any other effect sprite templates
+	_loadEffectSpritesFromResourceList(g.resources.Effects, effectSprites)
 }
 
 func _getEffectImageFromResource(r *model.ModelEffectResource) *ebiten.Image {
@@ -74,10 +58,16 @@ func _loadShaderEffects() {
 	crtShader = renderFx.NewCRT()
 }
 
-func _loadJumpJetEffectResource() {
+func _loadJumpJetEffectResource(g *Game) {
 	if jumpJetEffect == nil {
-		jumpJetImg := _getEffectImageFromResource(effects.JumpJet)
-		jumpJetEffect = sprites.NewAnimatedEffect(effects.JumpJet, jumpJetImg, math.MaxInt)
+		// use the first jump jet effect, mods can replace it by using the same effect file name
+		jjKeys := g.resources.GetEffectKeys(model.JumpJetEffectType)
+		if len(jjKeys) == 0 {
+			log.Fatalf("no %s effect resources found", model.JumpJetEffectType)
+		}
+		jumpJetResource := g.resources.Effects[jjKeys[0]]
+		jumpJetImg := _getEffectImageFromResource(jumpJetResource)
+		jumpJetEffect = sprites.NewAnimatedEffect(jumpJetResource, jumpJetImg, math.MaxInt)
 	}
 	for s := range attachedJJEffects {
 		delete(attachedJJEffects, s)
@@ -143,7 +133,7 @@ func _loadEffectSpritesFromResourceList(
 		if _, ok := spriteMap[key]; ok {
 			continue
 		}
-		// load the blood effect sprite template
+		// load the effect sprite template
 		eSpriteTemplate := sprites.NewAnimatedEffect(fx, _getEffectImageFromResource(fx), 1)
 		spriteMap[key] = eSpriteTemplate
 	}
@@ -200,7 +190,7 @@ func (g *Game) spawnEjectionPodSmokeEffects(s *sprites.ProjectileSprite) (durati
 		duration = fxDuration
 	}
 
-	s.SetEffectCounter(1 + rand.Intn(3))
+	s.SetEffectCounter(1 + model.EffectsRandIntn(3))
 	return
 }
 
@@ -291,7 +281,7 @@ func (g *Game) spawnPlayerDestroyEffects() (duration int) {
 		yFx := y + randFloat(-r, r)
 		zFx := z + randFloat(h/4, h)
 
-		explosionFx := g.randExplosionEffect(xFx, yFx, zFx, s.Heading(), 0)
+		explosionFx := g.unitDestroyEffect(s.Entity, xFx, yFx, zFx, s.Heading(), 0)
 		g.sprites.AddEffect(explosionFx)
 
 		smokeFx := g.randSmokeEffect(xFx, yFx, zFx, s.Heading(), 0)
@@ -307,7 +297,7 @@ func (g *Game) spawnPlayerDestroyEffects() (duration int) {
 			duration = fxDuration
 		}
 	}
-	s.SetEffectCounter(1 + rand.Intn(4))
+	s.SetEffectCounter(1 + model.EffectsRandIntn(4))
 	return
 }
 
@@ -337,7 +327,7 @@ func (g *Game) spawnMechDestroyEffects(s *sprites.MechSprite) (duration int) {
 		// only spawn effects in front of sprite relative to camera position
 		xFx, yFx = g.clampToCameraSpriteView(xFx, yFx, x, y)
 
-		explosionFx := g.randExplosionEffect(xFx, yFx, zFx, s.Heading(), 0)
+		explosionFx := g.unitDestroyEffect(s.Entity, xFx, yFx, zFx, s.Heading(), 0)
 		g.sprites.AddEffect(explosionFx)
 
 		smokeFx := g.randSmokeEffect(xFx, yFx, zFx, s.Heading(), 0)
@@ -353,7 +343,7 @@ func (g *Game) spawnMechDestroyEffects(s *sprites.MechSprite) (duration int) {
 			duration = fxDuration
 		}
 	}
-	s.SetEffectCounter(1 + rand.Intn(2))
+	s.SetEffectCounter(1 + model.EffectsRandIntn(2))
 	return
 }
 
@@ -370,7 +360,7 @@ func (g *Game) spawnInfantryDestroyEffects(s *sprites.InfantrySprite) (duration
 		// only spawn effects in front of sprite relative to camera position
 		xFx, yFx = g.clampToCameraSpriteView(xFx, yFx, x, y)
 
-		bloodFx := g.randBloodEffect(xFx, yFx, zFx, s.Heading(), 0)
+		bloodFx := g.unitDestroyEffect(s.Entity, xFx, yFx, zFx, s.Heading(), 0)
 		g.sprites.AddEffect(bloodFx)
 
 		fxDuration := bloodFx.AnimationDuration()
@@ -394,7 +384,7 @@ func (g *Game) spawnVehicleDestroyEffects(s *sprites.VehicleSprite) (duration in
 		// only spawn effects in front of sprite relative to camera position
 		xFx, yFx = g.clampToCameraSpriteView(xFx, yFx, x, y)
 
-		explosionFx := g.randExplosionEffect(xFx, yFx, zFx, s.Heading(), 0)
+		explosionFx := g.unitDestroyEffect(s.Entity, xFx, yFx, zFx, s.Heading(), 0)
 		g.sprites.AddEffect(explosionFx)
 
 		smokeFx := g.randSmokeEffect(xFx, yFx, zFx, s.Heading(), 0)
@@ -434,7 +424,7 @@ func (g *Game) spawnVTOLDestroyEffects(s *sprites.VTOLSprite, spawnExplosions bo
 		xFx, yFx = g.clampToCameraSpriteView(xFx, yFx, x, y)
 
 		if spawnExplosions {
-			explosionFx := g.randExplosionEffect(xFx, yFx, zFx, s.Heading(), 0)
+			explosionFx := g.unitDestroyEffect(s.Entity, xFx, yFx, zFx, s.Heading(), 0)
 			g.sprites.AddEffect(explosionFx)
 			if i == 0 || i == numFx/2 {
 				// only play two audio tracks for now since they are played at once
@@ -462,7 +452,7 @@ func (g *Game) spawnVTOLDestroyEffects(s *sprites.VTOLSprite, spawnExplosions bo
 	if fxCounter > 0 {
 		s.SetEffectCounter(fxCounter - 1)
 	} else {
-		s.SetEffectCounter(1 + rand.Intn(3))
+		s.SetEffectCounter(1 + model.EffectsRandIntn(3))
 	}
 
 	return
@@ -481,7 +471,7 @@ func (g *Game) spawnEmplacementDestroyEffects(s *sprites.EmplacementSprite) (dur
 		// only spawn effects in front of sprite relative to camera position
 		xFx, yFx = g.clampToCameraSpriteView(xFx, yFx, x, y)
 
-		explosionFx := g.randExplosionEffect(xFx, yFx, zFx, s.Heading(), 0)
+		explosionFx := g.unitDestroyEffect(s.Entity, xFx, yFx, zFx, s.Heading(), 0)
 		g.sprites.AddEffect(explosionFx)
 
 		smokeFx := g.randSmokeEffect(xFx, yFx, zFx, s.Heading(), 0)
@@ -520,21 +510,37 @@ func (g *Game) spawnEmplacementDestroyEffects(s *sprites.EmplacementSprite) (dur
 	return
 }
 
-func (g *Game) randBloodEffect(x, y, z, angle, pitch float64) *sprites.EffectSprite {
-	// return random blood effect
-	randKey := effects.RandBloodKey()
-	e := bloodEffects[randKey].Clone()
+// effect returns a new effect from the sprite template of the effect resource key
+func (g *Game) effect(key string, x, y, z float64) *sprites.EffectSprite {
+	e := effectSprites[key].Clone()
 	e.SetPos(&geom.Vector2{X: x, Y: y})
 	e.SetPosZ(z)
 	return e
 }
 
+// unitDestroyEffect returns a random effect from the unit specific destroy effects,
+// or a random explosion effect (or blood for infantry) if the unit does not define any
+func (g *Game) unitDestroyEffect(entity model.Entity, x, y, z, angle, pitch float64) *sprites.EffectSprite {
+	unit := model.EntityUnit(entity)
+	if unit != nil {
+		if fxKeys := unit.DestroyEffects(); len(fxKeys) > 0 {
+			return g.effect(fxKeys[model.EffectsRandIntn(len(fxKeys))], x, y, z)
+		}
+		if unit.UnitType() == model.InfantryUnitType {
+			return g.randBloodEffect(x, y, z, angle, pitch)
+		}
+	}
+	return g.randExplosionEffect(x, y, z, angle, pitch)
+}
+
+func (g *Game) randBloodEffect(x, y, z, angle, pitch float64) *sprites.EffectSprite {
+	// return random blood effect
+	return g.effect(g.resources.RandEffectKey(model.BloodEffectType), x, y, z)
+}
+
 func (g *Game) randExplosionEffect(x, y, z, angle, pitch float64) *sprites.EffectSprite {
 	// return random explosion effect
-	randKey := effects.RandExplosionKey()
-	e := explosionEffects[randKey].Clone()
-	e.SetPos(&geom.Vector2{X: x, Y: y})
-	e.SetPosZ(z)
+	e := g.effect(g.resources.RandEffectKey(model.ExplosionEffectType), x, y, z)
 
 	// TODO: give small negative Z velocity so it falls with the unit being destroyed?
 	return e
@@ -542,19 +548,12 @@ func (g *Game) randExplosionEffect(x, y, z, angle, pitch float64) *sprites.Effec
 
 func (g *Game) randFireEffect(x, y, z, angle, pitch float64) *sprites.EffectSprite {
 	// return random fire effect
-	randKey := effects.RandFireKey()
-	e := fireEffects[randKey].Clone()
-	e.SetPos(&geom.Vector2{X: x, Y: y})
-	e.SetPosZ(z)
-	return e
+	return g.effect(g.resources.RandEffectKey(model.FireEffectType), x, y, z)
 }
 
 func (g *Game) randSmokeEffect(x, y, z, angle, pitch float64) *sprites.EffectSprite {
 	// return random smoke effect
-	randKey := effects.RandSmokeKey()
-	e := smokeEffects[randKey].Clone()
-	e.SetPos(&geom.Vector2{X: x, Y: y})
-	e.SetPosZ(z)
+	e := g.effect(g.resources.RandEffectKey(model.SmokeEffectType), x, y, z)
 
 	// give Z velocity so it rises
 	e.SetVelocityZ(0.003) // TODO: define velocity of rise in resource model
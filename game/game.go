@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"image"
 	"math"
-	"math/rand"
 	"os"
 	"sort"
+	"sync"
 
 	"image/color"
 
@@ -49,6 +49,8 @@ type Game struct {
 
 	ai             *AIHandler
 	aiIgnorePlayer bool
+	aiDebug        *AIDebugOverlay
+	aiTrace        *AITraceHandler
 
 	resources   *model.ModelResources
 	audio       *AudioHandler
@@ -113,21 +115,46 @@ type Game struct {
 	mapWidth, mapHeight int
 	mission             *model.Mission
 	collisionMap        []*geom.Line
+	spatial             *SpatialIndex
+	spatialDisabled     bool
 
 	sprites            *sprites.SpriteHandler
 	clutter            *ClutterHandler
-	delayedProjectiles map[*ProjectileSpawn]struct{}
+	delayedProjectiles []*ProjectileSpawn
 
 	// Gameplay
 	objectives *ObjectivesHandler
+	triggers   *TriggersHandler
+	scripts    *ScriptHandler
+	sensors    *SensorsHandler
+	commands   *LanceCommands
 	difficulty *DifficultyLevel
+	combatRNG  *model.Rand
+	damageMu   sync.Mutex
+	// unit locations that took structure damage from projectiles this tick, rolled for critical hits once all have hit
+	critRolls []criticalRoll
 
 	// control options
 	throttleDecay bool
 
+	// replay recording or playback of the current mission
+	replay *Replay
+
+	// active campaign progress, nil when not playing a campaign
+	campaign *Campaign
+
+	// saved game to restore when the next mission is launched
+	saveGame *SaveGame
+
+	// multiplayer server hosting the mission, or client connection to the server
+	server *Server
+	client *Client
+
 	osType     osType
+	headless   bool
 	benchmark  bool
 	debug      bool
+	traceAI    bool
 	fpsEnabled bool
 }
 
@@ -331,9 +358,25 @@ func (g *Game) InProgress() bool {
 	return g.objectives != nil && g.objectives.Status() == OBJECTIVES_IN_PROGRESS
 }
 
+// deterministic returns true when game updates need to be reproducible from the same random seed
+func (g *Game) deterministic() bool {
+	return g.headless || g.replay != nil
+}
+
 func (g *Game) updateObjectives() {
+	if g.client != nil {
+		// objectives are updated from the multiplayer server
+		return
+	}
+
 	if g.InProgress() {
+		if g.triggers != nil {
+			g.triggers.Update(g)
+		}
 		g.objectives.Update(g)
+		if g.scripts != nil {
+			g.scripts.Update()
+		}
 
 		switch g.objectives.Status() {
 		case OBJECTIVES_FAILED:
@@ -346,6 +389,15 @@ func (g *Game) updateObjectives() {
 	}
 }
 
+// isLockOnBlocked returns true if the target is a unit that missile lock on from the source is blocked by ECM
+func (g *Game) isLockOnBlocked(source model.Unit, target model.Entity) bool {
+	tUnit := model.EntityUnit(target)
+	if g.sensors == nil || tUnit == nil {
+		return false
+	}
+	return g.sensors.IsECMProtected(g, source, tUnit)
+}
+
 func (g *Game) updateAI() {
 	if g.ai == nil {
 		return
@@ -390,19 +442,15 @@ func (g *Game) updatePlayer() {
 		g.player.moved = true
 
 		// check for nav point visits
-		for _, nav := range g.mission.NavPoints {
-			if nav.Visited() {
+		for _, nav := range g.visitNavPoints(newPos) {
+			// automatically cycle to next nav point, unless the objective is to stay at the nav point
+			if g.player.NavPoint() != nav {
 				continue
 			}
-
-			navX, navY := nav.Position[0], nav.Position[1]
-			if model.PointInProximity(1.0, newPos.X, newPos.Y, navX, navY) {
-				nav.SetVisited(true)
-
-				// automatically cycle to next nav point
-				if g.player.NavPoint() == nav && nav.Objective() != model.NavDustoffObjective {
-					g.navPointCycle(false)
-				}
+			switch nav.Objective() {
+			case model.NavDustoffObjective, model.NavDefendObjective, model.NavCaptureObjective:
+			default:
+				g.navPointCycle(false)
 			}
 		}
 	}
@@ -472,36 +520,16 @@ func (g *Game) updatePlayer() {
 		g.player.SetTarget(nil)
 	}
 
-	if target == nil || !g.player.HasLockOnWeapon() || g.IsFriendly(g.player, target) || g.player.Powered() != model.POWER_ON {
-		// clear target lock if no target, no lock on weapons, is friendly target, or player is not fully powered on
+	if target == nil || !g.player.HasLockOnWeapon() || g.IsFriendly(g.player, target) || g.player.Powered() != model.POWER_ON ||
+		g.isLockOnBlocked(g.player.Unit, target) {
+		// clear target lock if no target, no lock on weapons, is friendly target, player is not fully powered on,
+		// or target is protected by ECM
 		g.player.SetTargetLock(0)
-	} else {
+	} else if !g.player.autopilot {
 		// only increment lock percent on target if reticle near target area and in weapon range
 		s := g.getSpriteFromEntity(target)
 		if s != nil {
-			acquireLock := false
-			crosshairLockSize := int(math.Ceil(float64(g.screenWidth) * 0.05))
-			midW, midH := g.screenWidth/2, g.screenHeight/2
-			crosshairBounds := image.Rect(
-				midW-crosshairLockSize/2, midH-crosshairLockSize/2,
-				midW+crosshairLockSize/2, midH+crosshairLockSize/2,
-			)
-			targetBounds := s.ScreenRect(g.renderScale)
-			targetLeadBounds := g.player.reticleLead.Sprite.ScreenRect(g.renderScale)
-			if targetBounds != nil {
-				acquireLock = targetBounds.Overlaps(crosshairBounds) ||
-					(targetLeadBounds != nil && targetLeadBounds.Overlaps(crosshairBounds))
-
-				if !acquireLock && targetLeadBounds != nil {
-					// check if the crosshairs are on the line between the target and lead indicator
-					targetMidPoint := targetBounds.Min.Add(image.Point{X: targetBounds.Dx() / 2, Y: targetBounds.Dy() / 2})
-					targetLeadMidPoint := targetLeadBounds.Min.Add(image.Point{X: targetLeadBounds.Dx() / 2, Y: targetLeadBounds.Dy() / 2})
-					targetLeadLine := geom.Line{X1: float64(targetMidPoint.X), Y1: float64(targetMidPoint.Y), X2: float64(targetLeadMidPoint.X), Y2: float64(targetLeadMidPoint.Y)}
-
-					crossHairRect := model.NewRect(float64(crosshairBounds.Min.X), float64(crosshairBounds.Min.Y), float64(crosshairBounds.Max.X), float64(crosshairBounds.Max.Y))
-					acquireLock = crossHairRect.IntersectsLine(targetLeadLine)
-				}
-			}
+			acquireLock := g.crosshairsOnTarget(s)
 
 			targetDistance := model.EntityDistance(g.player, target) - g.player.CollisionRadius() - target.CollisionRadius()
 			if int(targetDistance) <= int(baseLockOnRange) {
@@ -523,6 +551,60 @@ func (g *Game) updatePlayer() {
 	}
 }
 
+// visitNavPoints sets nav points within proximity of the position as visited, returning those that were just visited
+func (g *Game) visitNavPoints(pos *geom.Vector2) []*model.NavPoint {
+	var visited []*model.NavPoint
+	for _, nav := range g.mission.NavPoints {
+		if nav.Visited() {
+			continue
+		}
+
+		navX, navY := nav.Position[0], nav.Position[1]
+		if model.PointInProximity(1.0, pos.X, pos.Y, navX, navY) {
+			nav.SetVisited(true)
+			visited = append(visited, nav)
+		}
+	}
+	return visited
+}
+
+// crosshairsOnTarget returns true if the crosshairs are near the target sprite or its lead indicator on screen
+func (g *Game) crosshairsOnTarget(s *sprites.Sprite) bool {
+	if g.replay != nil && g.replay.Playback() {
+		// screen bounds depend on rendering, use the recorded result instead
+		return g.replay.lockOn
+	}
+
+	onTarget := false
+	crosshairLockSize := int(math.Ceil(float64(g.screenWidth) * 0.05))
+	midW, midH := g.screenWidth/2, g.screenHeight/2
+	crosshairBounds := image.Rect(
+		midW-crosshairLockSize/2, midH-crosshairLockSize/2,
+		midW+crosshairLockSize/2, midH+crosshairLockSize/2,
+	)
+	targetBounds := s.ScreenRect(g.renderScale)
+	targetLeadBounds := g.player.reticleLead.Sprite.ScreenRect(g.renderScale)
+	if targetBounds != nil {
+		onTarget = targetBounds.Overlaps(crosshairBounds) ||
+			(targetLeadBounds != nil && targetLeadBounds.Overlaps(crosshairBounds))
+
+		if !onTarget && targetLeadBounds != nil {
+			// check if the crosshairs are on the line between the target and lead indicator
+			targetMidPoint := targetBounds.Min.Add(image.Point{X: targetBounds.Dx() / 2, Y: targetBounds.Dy() / 2})
+			targetLeadMidPoint := targetLeadBounds.Min.Add(image.Point{X: targetLeadBounds.Dx() / 2, Y: targetLeadBounds.Dy() / 2})
+			targetLeadLine := geom.Line{X1: float64(targetMidPoint.X), Y1: float64(targetMidPoint.Y), X2: float64(targetLeadMidPoint.X), Y2: float64(targetLeadMidPoint.Y)}
+
+			crossHairRect := model.NewRect(float64(crosshairBounds.Min.X), float64(crosshairBounds.Min.Y), float64(crosshairBounds.Max.X), float64(crosshairBounds.Max.Y))
+			onTarget = crossHairRect.IntersectsLine(targetLeadLine)
+		}
+	}
+
+	if g.replay != nil {
+		g.replay.recordLockOn(onTarget)
+	}
+	return onTarget
+}
+
 func (g *Game) navPointCycle(replaceTarget bool) {
 	if len(g.mission.NavPoints) == 0 {
 		return
@@ -574,6 +656,11 @@ func (g *Game) targetCrosshairs() model.Entity {
 }
 
 func (g *Game) spriteInCrosshairs() *sprites.Sprite {
+	if g.replay != nil && g.replay.Playback() {
+		// crosshairs depend on rendering, use the recorded sprite instead
+		return g.replay.crosshairsSprite(g)
+	}
+
 	cSprite := g.player.convergenceSprite
 	if cSprite == nil {
 		// check for target in crosshairs bounds if not directly at the single center raycasted pixel
@@ -692,12 +779,27 @@ func (g *Game) updateWeaponCooldowns(unit model.Unit) {
 }
 
 func (g *Game) LoadUnit(unitResourceType, unitFile string) model.Unit {
-	// TODO: make it useful for unit of any unit type
 	switch unitResourceType {
 	case model.MechResourceType:
 		if resource, ok := g.resources.Mechs[unitFile]; ok {
 			return g.createModelMechFromResource(resource)
 		}
+	case model.VehicleResourceType:
+		if resource, ok := g.resources.Vehicles[unitFile]; ok {
+			return g.createModelVehicleFromResource(resource)
+		}
+	case model.VTOLResourceType:
+		if resource, ok := g.resources.VTOLs[unitFile]; ok {
+			return g.createModelVTOLFromResource(resource)
+		}
+	case model.InfantryResourceType:
+		if resource, ok := g.resources.Infantry[unitFile]; ok {
+			return g.createModelInfantryFromResource(resource)
+		}
+	case model.EmplacementResourceType:
+		if resource, ok := g.resources.Emplacements[unitFile]; ok {
+			return g.createModelEmplacementFromResource(resource)
+		}
 	default:
 		panic(fmt.Errorf("loading model.Unit for resource type not implemented: %v", unitResourceType))
 	}
@@ -709,7 +811,7 @@ func (g *Game) RandomUnit(unitResourceType string) model.Unit {
 	switch unitResourceType {
 	case model.MechResourceType:
 		mechResources := g.resources.GetMechResourceList()
-		randIndex := rand.Intn(len(mechResources))
+		randIndex := model.RandIntn(len(mechResources))
 		randResource := mechResources[randIndex]
 		return g.createModelMechFromResource(randResource)
 	default:
@@ -724,12 +826,57 @@ func (g *Game) IsFriendly(e1, e2 model.Entity) bool {
 	if e1 == e2 {
 		return true
 	}
-	if e1 == g.player.Unit || e2 == g.player.Unit {
-		return e1.Team() < 0 && e2.Team() < 0
+	if g.isPlayerUnit(e1) || g.isPlayerUnit(e2) {
+		return e1.Team() == e2.Team() || (e1.Team() < 0 && e2.Team() < 0)
 	}
 	return e1.Team() == e2.Team()
 }
 
+// isPlayerUnit returns true if the entity is the player unit, or a client unit on a multiplayer server
+func (g *Game) isPlayerUnit(e model.Entity) bool {
+	if e == nil {
+		return false
+	}
+	if g.player != nil && e == g.player.Unit {
+		return true
+	}
+	return g.server != nil && g.server.clientUnit(e) != nil
+}
+
+// playerUnits returns the units piloted by players, which are the client units on a multiplayer server
+// where the player unit is only an observer
+func (g *Game) playerUnits() []model.Unit {
+	if g.server != nil {
+		units := make([]model.Unit, 0, len(g.server.clients))
+		for _, c := range g.server.clients {
+			units = append(units, c.unit)
+		}
+		return units
+	}
+	return []model.Unit{g.player.Unit}
+}
+
+// playerUnitsDestroyed returns true if there are player units and all of them have been destroyed
+func (g *Game) playerUnitsDestroyed() bool {
+	units := g.playerUnits()
+	for _, u := range units {
+		if !u.IsDestroyed() {
+			return false
+		}
+	}
+	return len(units) > 0
+}
+
+// isPlayerFriendly returns true if the entity is friendly with any of the player units
+func (g *Game) isPlayerFriendly(e model.Entity) bool {
+	for _, u := range g.playerUnits() {
+		if g.IsFriendly(u, e) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsTargetable returns true if the source unit is able to target the target unit based on current distance and power conditions
 func (g *Game) IsTargetable(source, target model.Unit) bool {
 	if target == nil {
@@ -740,17 +887,30 @@ func (g *Game) IsTargetable(source, target model.Unit) bool {
 	return g.IsTargetableAtDistance(source, target, tDist)
 }
 
-// IsTargetableAtDistance returns true if the source unit is able to target the target unit based on given distance and power conditions
+// IsTargetableAtDistance returns true if the source unit is able to target the target unit based on given distance
+// and power conditions, and the target has been detected by the sensors of the source
 func (g *Game) IsTargetableAtDistance(source, target model.Unit, distance float64) bool {
+	if !g.inSensorRange(source, target, distance) {
+		return false
+	}
+	if g.sensors != nil && !g.IsFriendly(source, target) {
+		return g.sensors.IsDetected(source, target)
+	}
+	return true
+}
+
+// inSensorRange returns true if the target is within the sensor range of the source unit based on given distance and power conditions
+func (g *Game) inSensorRange(source, target model.Unit, distance float64) bool {
 	if target == nil || source.Powered() != model.POWER_ON {
 		return false
 	}
 	if target.Powered() != model.POWER_ON {
-		return distance <= 200/model.METERS_PER_UNIT
+		return distance <= math.Min(source.SensorRange(), model.SENSOR_POWERED_DOWN_RANGE_METERS/model.METERS_PER_UNIT)
 	}
-	return distance <= 1000/model.METERS_PER_UNIT
+	return distance <= source.SensorRange()
 }
 
+// randFloat returns a random number for cosmetic use from the seedable effects random source
 func randFloat(min, max float64) float64 {
 	return model.RandFloat64In(min, max, nil)
 }
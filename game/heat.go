@@ -0,0 +1,48 @@
+package game
+
+import (
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// updateHeatScale rolls for the shutdown and ammo explosion risks of the heat scale band the unit is in
+func (g *Game) updateHeatScale(unit model.Unit) {
+	if unit == nil || unit.IsDestroyed() || unit.Powered() != model.POWER_ON {
+		return
+	}
+	if g.client != nil {
+		// heat effects are rolled by the multiplayer server
+		return
+	}
+
+	band := unit.HeatScaleBand()
+	if band == nil {
+		return
+	}
+
+	if band.ShutdownChance > 0 && g.combatRNG.Float64() < band.ShutdownChance*model.SECONDS_PER_TICK {
+		// failed to avoid automatic shutdown
+		if g.debug {
+			log.Debugf("[%s] heat shutdown at %0.1f/%0.1f", unit.ID(), unit.Heat(), unit.MaxHeat())
+		}
+		unit.SetPowered(model.POWER_OFF_HEAT)
+	}
+
+	if band.AmmoExplosionChance > 0 && g.combatRNG.Float64() < band.AmmoExplosionChance*model.SECONDS_PER_TICK {
+		hit := unit.CookOffAmmo(g.combatRNG)
+		if hit == nil {
+			return
+		}
+		if g.debug {
+			log.Debugf("[%s] heat %s", unit.ID(), hit)
+		}
+
+		// visual effect for ammo explosion at the unit
+		s := g.getSpriteFromEntity(unit)
+		if s != nil {
+			x, y, z := s.Pos().X, s.Pos().Y, s.PosZ()+s.CollisionHeight()/2
+			g.sprites.AddEffect(g.randExplosionEffect(x, y, z, s.Heading(), 0))
+		}
+	}
+}
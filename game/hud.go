@@ -16,9 +16,11 @@ type HUDElementType int
 
 const (
 	HUD_FPS HUDElementType = iota
+	HUD_AI_DEBUG
 	HUD_BANNER
 	HUD_ALTIMETER
 	HUD_ARMAMENT
+	HUD_COMMAND_WHEEL
 	HUD_COMPASS
 	HUD_CROSSHAIRS
 	HUD_HEAT
@@ -120,8 +122,19 @@ func (g *Game) loadHUD() {
 	banner := render.NewMissionBanner(g.fonts.HUDFont)
 	g.playerHUD[HUD_BANNER] = banner
 
+	commandWheel := render.NewCommandWheel(g.fonts.HUDFont)
+	commands := make([]string, 0, AI_COMMAND_COUNT)
+	for c := AICommand(0); c < AI_COMMAND_COUNT; c++ {
+		commands = append(commands, c.String())
+	}
+	commandWheel.SetCommands(commands)
+	g.playerHUD[HUD_COMMAND_WHEEL] = commandWheel
+
 	fps := render.NewFPSIndicator(g.fonts.HUDFont)
 	g.playerHUD[HUD_FPS] = fps
+
+	aiDebug := render.NewAIDebugOverlay(g.fonts.HUDFont)
+	g.playerHUD[HUD_AI_DEBUG] = aiDebug
 }
 
 func (g *Game) resetHUDElementScale() {
@@ -167,6 +180,9 @@ func (g *Game) drawHUD(screen *ebiten.Image) {
 	// draw FPS display
 	g.drawFPS(hudOpts)
 
+	// draw AI debug overlay
+	g.drawAIDebugOverlay(hudOpts)
+
 	if !g.hudEnabled {
 		return
 	}
@@ -295,17 +311,31 @@ func (g *Game) drawHUD(screen *ebiten.Image) {
 
 	// draw mission banner
 	g.drawMissionBanner(hudOpts)
+
+	// draw lance command wheel
+	g.drawCommandWheel(hudOpts)
 }
 
 func (g *Game) drawFPS(hudOpts *render.DrawHudOptions) {
 	fps := g.GetHUDElement(HUD_FPS).(*render.FPSIndicator)
-	if fps == nil || !g.fpsEnabled {
+	showReplay := g.replay != nil && g.replay.Playback()
+	if fps == nil || !(g.fpsEnabled || showReplay) {
 		return
 	}
 
-	fpsText := fmt.Sprintf("FPS: %0.1f | TPS: %0.1f/%d", ebiten.ActualFPS(), ebiten.ActualTPS(), ebiten.TPS())
-	if debugProfCPU {
-		fpsText += " | CPU Profiling Enabled"
+	var fpsText string
+	if g.fpsEnabled {
+		fpsText = fmt.Sprintf("FPS: %0.1f | TPS: %0.1f/%d", ebiten.ActualFPS(), ebiten.ActualTPS(), ebiten.TPS())
+		if debugProfCPU {
+			fpsText += " | CPU Profiling Enabled"
+		}
+	}
+	if showReplay {
+		// show replay playback status along with FPS
+		if len(fpsText) > 0 {
+			fpsText += " | "
+		}
+		fpsText += g.replay.String()
 	}
 	fps.SetFPSText(fpsText)
 
@@ -600,6 +630,16 @@ func (g *Game) drawHeatIndicator(hudOpts *render.DrawHudOptions) {
 		hX, hY, hX+heatWidth, hY+heatHeight,
 	)
 	heat.SetValues(currHeat, maxHeat, dissipationPerSec)
+
+	// show heat scale band thresholds only for units that use the heat scale
+	var thresholds []float64
+	if hudOpts.HudUnit.UnitType() == model.MechUnitType {
+		thresholds = make([]float64, 0, len(model.HeatScale))
+		for _, band := range model.HeatScale {
+			thresholds = append(thresholds, band.Threshold)
+		}
+	}
+	heat.SetThresholds(thresholds)
 	heat.Draw(hBounds, hudOpts)
 }
 
@@ -784,8 +824,9 @@ func (g *Game) drawRadar(hudOpts *render.DrawHudOptions) {
 
 		if !g.IsTargetableAtDistance(g.player, unit, unitDistance) {
 			// the unit is not targetable, do not show it as a blip
-			if unit.Powered() == model.POWER_ON_IN_PROGRESS {
-				// however the unit is powering on, show as a ping
+			detecting := g.sensors != nil && g.sensors.IsDetecting(g.player.Unit, unit)
+			if unit.Powered() == model.POWER_ON_IN_PROGRESS || detecting {
+				// however the unit is powering on or still being detected by sensors, show as a ping
 				ping := &render.RadarPing{
 					Entity:   entity,
 					Angle:    relAngle,
@@ -813,10 +854,13 @@ func (g *Game) drawRadar(hudOpts *render.DrawHudOptions) {
 		radarBlips = append(radarBlips, blip)
 	}
 
-	if g.debug && (camTarget != nil || debugCamTgt != nil) {
-		// draw debug nav lines for AI pathing of player target
+	aiDebugUnit := g.aiDebug.Unit()
+	if g.debug && (camTarget != nil || debugCamTgt != nil || aiDebugUnit != nil) {
+		// draw debug nav lines for AI pathing of the AI debug overlay unit, or else player target
 		var debugNavLineTargetUnit model.Unit
-		if debugCamTgt != nil {
+		if aiDebugUnit != nil {
+			debugNavLineTargetUnit = aiDebugUnit
+		} else if debugCamTgt != nil {
 			debugNavLineTargetUnit = debugCamTgt
 		} else {
 			debugNavLineTargetUnit = model.EntityUnit(camTarget)
@@ -849,6 +893,7 @@ func (g *Game) drawRadar(hudOpts *render.DrawHudOptions) {
 
 	radar.SetNavPoints(rNavPoints)
 	radar.SetRadarBlips(radarBlips)
+	radar.SetJammed(g.sensors != nil && g.sensors.IsJammed(g, g.player.Unit))
 
 	radar.Draw(radarBounds, hudOpts)
 }
@@ -945,6 +990,19 @@ func (g *Game) drawMissionBanner(hudOpts *render.DrawHudOptions) {
 		} else {
 			bannerText = "Mission Failed..."
 		}
+	} else {
+		if g.triggers != nil {
+			// message from a mission trigger
+			bannerText = g.triggers.Message(g)
+		}
+		if len(bannerText) == 0 {
+			// acknowledgement of an order to lancemates
+			bannerText = g.commands.Message(g)
+		}
+		if len(bannerText) == 0 {
+			// progress of timed objectives
+			bannerText = g.objectives.BannerText()
+		}
 	}
 	if len(bannerText) == 0 {
 		return
@@ -964,3 +1022,55 @@ func (g *Game) drawMissionBanner(hudOpts *render.DrawHudOptions) {
 	)
 	banner.Draw(bBounds, hudOpts)
 }
+
+func (g *Game) drawCommandWheel(hudOpts *render.DrawHudOptions) {
+	commandWheel := g.GetHUDElement(HUD_COMMAND_WHEEL).(*render.CommandWheel)
+	wheelOpen, selected := g.commands.WheelOpen()
+	if commandWheel == nil || !wheelOpen {
+		return
+	}
+
+	hudRect := hudOpts.HudRect
+	hudW, hudH := hudRect.Dx(), hudRect.Dy()
+
+	wheelScale := commandWheel.Scale() * g.hudScale
+	if wheelScale == 0 {
+		return
+	}
+	wheelSize := int(wheelScale * float64(hudH) / 2)
+
+	wX, wY := hudRect.Min.X+hudW/2-wheelSize/2, hudRect.Min.Y+hudH/2-wheelSize/2
+	wBounds := image.Rect(
+		wX, wY, wX+wheelSize, wY+wheelSize,
+	)
+
+	commandWheel.SetSelected(int(selected))
+	commandWheel.Draw(wBounds, hudOpts)
+}
+
+func (g *Game) drawAIDebugOverlay(hudOpts *render.DrawHudOptions) {
+	aiDebug := g.GetHUDElement(HUD_AI_DEBUG).(*render.AIDebugOverlay)
+	u := g.aiDebug.Unit()
+	if aiDebug == nil || u == nil {
+		return
+	}
+	a := g.ai.UnitAI(u)
+	if a == nil {
+		return
+	}
+
+	aiDebug.SetLines(g.aiDebugLines(a))
+	aiDebug.SetPathPoints(g.aiDebugPathPoints(a))
+
+	marginX, marginY := hudOpts.MarginX, hudOpts.MarginY
+	hudRect := hudOpts.HudRect
+
+	dScale := aiDebug.Scale() * g.hudScale
+	dWidth, dHeight := int(dScale*float64(hudRect.Dx())/3), int(dScale*float64(hudRect.Dy())/2)
+
+	dX, dY := hudRect.Max.X-dWidth-marginX, hudRect.Min.Y+3*marginY
+	dBounds := image.Rect(
+		dX, dY, dX+dWidth, dY+dHeight,
+	)
+	aiDebug.Draw(dBounds, hudOpts)
+}
@@ -73,6 +73,12 @@ const (
 	ActionLightAmpToggle
 	ActionPowerToggle
 	ActionCameraCycle
+	ActionCommandWheel
+	ActionCommandAttack
+	ActionCommandFormOnMe
+	ActionCommandHold
+	ActionCommandNavPoint
+	ActionCommandCeaseFire
 	actionCount
 )
 
@@ -172,6 +178,18 @@ func actionString(a input.Action) string {
 		return "power_toggle"
 	case ActionCameraCycle:
 		return "camera_cycle"
+	case ActionCommandWheel:
+		return "command_wheel"
+	case ActionCommandAttack:
+		return "command_attack"
+	case ActionCommandFormOnMe:
+		return "command_form_on_me"
+	case ActionCommandHold:
+		return "command_hold"
+	case ActionCommandNavPoint:
+		return "command_nav_point"
+	case ActionCommandCeaseFire:
+		return "command_cease_fire"
 	default:
 		panic(fmt.Errorf("currently unable to handle actionString for input.Action: %v", a))
 	}
@@ -193,17 +211,37 @@ func (g *Game) initControls() {
 		}
 	}
 
-	// TODO: initialize default for new controls even if not first time?
-
 	if len(keymap) == 0 {
 		// first time intitialize defaults into file
 		g.setDefaultControls()
 		g.saveControls()
+		return
+	}
+
+	// initialize defaults for controls added since the keymap file was saved
+	missingActions := false
+	for a, keys := range defaultKeymap() {
+		if _, ok := keymap[a]; !ok {
+			keymap[a] = keys
+			missingActions = true
+		}
+	}
+	if missingActions {
+		g.input = g.inputSystem.NewHandler(0, keymap)
+		g.saveControls()
 	}
 }
 
 func (g *Game) setDefaultControls() {
-	keymap := input.Keymap{
+	g.inputSystem.Init(input.SystemConfig{
+		DevicesEnabled: input.AnyDevice,
+	})
+	g.input = g.inputSystem.NewHandler(0, defaultKeymap())
+}
+
+// defaultKeymap returns the default keys for each action
+func defaultKeymap() input.Keymap {
+	return input.Keymap{
 		ActionUp:       {input.KeyW, input.KeyUp},
 		ActionDown:     {input.KeyS, input.KeyDown},
 		ActionLeft:     {input.KeyA, input.KeyLeft},
@@ -247,12 +285,14 @@ func (g *Game) setDefaultControls() {
 		ActionLightAmpToggle: {input.KeyL, input.KeyGamepadDown},
 		ActionPowerToggle:    {input.KeyP},
 		ActionCameraCycle:    {input.KeyF3},
-	}
 
-	g.inputSystem.Init(input.SystemConfig{
-		DevicesEnabled: input.AnyDevice,
-	})
-	g.input = g.inputSystem.NewHandler(0, keymap)
+		ActionCommandWheel:     {input.KeyC, input.KeyGamepadL1},
+		ActionCommandAttack:    {input.KeyF5},
+		ActionCommandFormOnMe:  {input.KeyF6},
+		ActionCommandHold:      {input.KeyF7},
+		ActionCommandNavPoint:  {input.KeyF8},
+		ActionCommandCeaseFire: {input.KeyF9},
+	}
 }
 
 func (g *Game) restoreControls() (input.Keymap, error) {
@@ -356,6 +396,70 @@ func (g *Game) saveControls() error {
 	return nil
 }
 
+// playerInput is the source of player unit control input, from input devices or from a replay
+type playerInput interface {
+	ActionIsPressed(input.Action) bool
+	ActionIsJustPressed(input.Action) bool
+	ActionIsJustReleased(input.Action) bool
+
+	// Axes returns the movement and turret deltas from analog sticks and the mouse
+	Axes() (moveDx, moveDy, turretDx, turretDy float64)
+}
+
+// deviceInput is player input from the keyboard, mouse and gamepad
+type deviceInput struct {
+	*input.Handler
+	g *Game
+}
+
+func (d *deviceInput) Axes() (moveDx, moveDy, turretDx, turretDy float64) {
+	g := d.g
+	if (g.mouseMode == MouseModeTurret || g.mouseMode == MouseModeBody) && ebiten.CursorMode() != ebiten.CursorModeCaptured {
+		ebiten.SetCursorMode(ebiten.CursorModeCaptured)
+
+		// reset initial mouse capture position
+		g.mouseX, g.mouseY = math.MinInt32, math.MinInt32
+	}
+
+	cursorX, cursorY := ebiten.CursorPosition()
+
+	if moveAxes, ok := d.PressedActionInfo(ActionMoveAxes); ok {
+		// TODO: configurable deadzone and sensitivity (for mouse and gamepad)
+		if math.Abs(moveAxes.Pos.X) >= 0.2 {
+			moveDx = 10 * -moveAxes.Pos.X
+		}
+		if math.Abs(moveAxes.Pos.Y) >= 0.2 {
+			moveDy = 5 * -moveAxes.Pos.Y
+		}
+	} // else {
+	// TODO: handle mouse mode body
+	//}
+
+	if turretAxes, ok := d.PressedActionInfo(ActionTurretAxes); ok {
+		// TODO: configurable deadzone and sensitivity (for mouse and gamepad)
+		if math.Abs(turretAxes.Pos.X) >= 0.2 {
+			turretDx = 10 * -turretAxes.Pos.X
+		}
+		if math.Abs(turretAxes.Pos.Y) >= 0.2 {
+			turretDy = 5 * -turretAxes.Pos.Y
+		}
+	} else {
+		// handle mouse mode turret
+		switch {
+		case g.mouseX == math.MinInt32 && g.mouseY == math.MinInt32:
+			// initialize first position to establish delta
+			if cursorX != 0 && cursorY != 0 {
+				g.mouseX, g.mouseY = cursorX, cursorY
+			}
+
+		default:
+			turretDx, turretDy = float64(g.mouseX-cursorX), float64(g.mouseY-cursorY)
+			g.mouseX, g.mouseY = cursorX, cursorY
+		}
+	}
+	return
+}
+
 func (g *Game) handleInput() {
 	menuKeyPressed := g.input.ActionIsJustPressed(ActionMenu)
 	if menuKeyPressed {
@@ -377,13 +481,31 @@ func (g *Game) handleInput() {
 		return
 	}
 
-	g.handleDebugInput()
+	if g.replay != nil && g.replay.Playback() {
+		// player input comes from the replay during playback, handled with each tick
+		g.handlePlaybackInput()
+		return
+	}
+
+	var in playerInput = &deviceInput{Handler: g.input, g: g}
+	if g.replay != nil {
+		// player input is handled from the recorded frame so it is the same as playback
+		in = g.replay.record(g, in)
+	} else {
+		// debug input changes game state outside of player input, so not while recording
+		g.handleDebugInput()
+	}
+
+	g.handlePlayerInput(in)
+}
 
+// handlePlayerInput handles player unit control input for the current tick
+func (g *Game) handlePlayerInput(in playerInput) {
 	_, isInfantry := g.player.Unit.(*model.Infantry)
 	//_, isMech := g.player.Unit.(*model.Mech)
 	_, isVTOL := g.player.Unit.(*model.VTOL)
 
-	if g.input.ActionIsJustPressed(ActionPowerToggle) {
+	if in.ActionIsJustPressed(ActionPowerToggle) {
 		switch g.player.Powered() {
 		case model.POWER_ON:
 			g.player.SetPowered(model.POWER_OFF_MANUAL)
@@ -392,29 +514,13 @@ func (g *Game) handleInput() {
 		}
 	}
 
-	if (g.mouseMode == MouseModeTurret || g.mouseMode == MouseModeBody) && ebiten.CursorMode() != ebiten.CursorModeCaptured {
-		ebiten.SetCursorMode(ebiten.CursorModeCaptured)
+	moveDx, moveDy, turretDx, turretDy := in.Axes()
 
-		// reset initial mouse capture position
-		g.mouseX, g.mouseY = math.MinInt32, math.MinInt32
+	if g.handleLanceCommandInput(in, turretDx, turretDy) {
+		// turret input is selecting from the lance command wheel
+		turretDx, turretDy = 0, 0
 	}
 
-	var moveDx, moveDy float64
-	var turretDx, turretDy float64
-	cursorX, cursorY := ebiten.CursorPosition()
-
-	if moveAxes, ok := g.input.PressedActionInfo(ActionMoveAxes); ok {
-		// TODO: configurable deadzone and sensitivity (for mouse and gamepad)
-		if math.Abs(moveAxes.Pos.X) >= 0.2 {
-			moveDx = 10 * -moveAxes.Pos.X
-		}
-		if math.Abs(moveAxes.Pos.Y) >= 0.2 {
-			moveDy = 5 * -moveAxes.Pos.Y
-		}
-	} // else {
-	// TODO: handle mouse mode body
-	//}
-
 	if moveDx != 0 {
 		turnAmount := 0.01 * float64(moveDx) / g.zoomFovDepth
 		g.player.SetTargetRelativeHeading(turnAmount)
@@ -428,29 +534,6 @@ func (g *Game) handleInput() {
 	// handled in throttle section below
 	// }
 
-	if turretAxes, ok := g.input.PressedActionInfo(ActionTurretAxes); ok {
-		// TODO: configurable deadzone and sensitivity (for mouse and gamepad)
-		if math.Abs(turretAxes.Pos.X) >= 0.2 {
-			turretDx = 10 * -turretAxes.Pos.X
-		}
-		if math.Abs(turretAxes.Pos.Y) >= 0.2 {
-			turretDy = 5 * -turretAxes.Pos.Y
-		}
-	} else {
-		// handle mouse mode turret
-		switch {
-		case g.mouseX == math.MinInt32 && g.mouseY == math.MinInt32:
-			// initialize first position to establish delta
-			if cursorX != 0 && cursorY != 0 {
-				g.mouseX, g.mouseY = cursorX, cursorY
-			}
-
-		default:
-			turretDx, turretDy = float64(g.mouseX-cursorX), float64(g.mouseY-cursorY)
-			g.mouseX, g.mouseY = cursorX, cursorY
-		}
-	}
-
 	if turretDx != 0 {
 		if g.player.HasTurret() {
 			g.player.RotateCamera(0.005 * turretDx / g.zoomFovDepth)
@@ -479,11 +562,11 @@ func (g *Game) handleInput() {
 	if g.player.Target() == nil {
 		// auto-target on crosshairs if just fired weapon without a target selected
 		justFired := false
-		if g.input.ActionIsJustPressed(ActionWeaponFire) {
+		if in.ActionIsJustPressed(ActionWeaponFire) {
 			justFired = true
 		} else {
 			for _, actionGroup := range weaponFireGroups {
-				if g.input.ActionIsJustPressed(actionGroup) {
+				if in.ActionIsJustPressed(actionGroup) {
 					justFired = true
 					break
 				}
@@ -499,16 +582,16 @@ func (g *Game) handleInput() {
 	}
 
 	for weaponGroup, actionGroup := range weaponFireGroups {
-		if g.input.ActionIsPressed(actionGroup) {
+		if in.ActionIsPressed(actionGroup) {
 			g.firePlayerWeapon(weaponGroup)
 		}
 	}
 
-	if g.input.ActionIsPressed(ActionWeaponFire) {
+	if in.ActionIsPressed(ActionWeaponFire) {
 		g.firePlayerWeapon(-1)
 	}
 
-	isFireButtonJustReleased := g.input.ActionIsJustReleased(ActionWeaponFire)
+	isFireButtonJustReleased := in.ActionIsJustReleased(ActionWeaponFire)
 	if isFireButtonJustReleased {
 		if g.player.fireMode == model.CHAIN_FIRE {
 			// cycle to next weapon only in same group (g.player.selectedGroup)
@@ -559,7 +642,7 @@ func (g *Game) handleInput() {
 		}
 	}
 
-	if g.input.ActionIsJustPressed(ActionWeaponCycle) {
+	if in.ActionIsJustPressed(ActionWeaponCycle) {
 		playerPrevGroup := g.player.selectedGroup
 		playerPrevWeapon := g.player.selectedWeapon
 
@@ -602,19 +685,19 @@ func (g *Game) handleInput() {
 		}
 	}
 
-	if g.input.ActionIsPressed(ActionWeaponGroupSetModifier) {
+	if in.ActionIsPressed(ActionWeaponGroupSetModifier) {
 		// set group for selected weapon
 		setGroupIndex := model.WEAPON_GROUP_NONE
 		switch {
-		case g.input.ActionIsJustPressed(ActionWeaponGroup1):
+		case in.ActionIsJustPressed(ActionWeaponGroup1):
 			setGroupIndex = model.WEAPON_GROUP_1
-		case g.input.ActionIsJustPressed(ActionWeaponGroup2):
+		case in.ActionIsJustPressed(ActionWeaponGroup2):
 			setGroupIndex = model.WEAPON_GROUP_2
-		case g.input.ActionIsJustPressed(ActionWeaponGroup3):
+		case in.ActionIsJustPressed(ActionWeaponGroup3):
 			setGroupIndex = model.WEAPON_GROUP_3
-		case g.input.ActionIsJustPressed(ActionWeaponGroup4):
+		case in.ActionIsJustPressed(ActionWeaponGroup4):
 			setGroupIndex = model.WEAPON_GROUP_4
-		case g.input.ActionIsJustPressed(ActionWeaponGroup5):
+		case in.ActionIsJustPressed(ActionWeaponGroup5):
 			setGroupIndex = model.WEAPON_GROUP_5
 		}
 
@@ -641,10 +724,12 @@ func (g *Game) handleInput() {
 			}
 			g.player.selectedGroup = setGroupIndex
 
-			// TODO: use background thread queue to avoid multiple writes at same time
-			setUnitWeaponGroups(g.player, g.player.weaponGroups)
-			if err := saveUserWeaponGroups(); err != nil {
-				log.Error("failed to save user weapon groups: " + err.Error())
+			if g.replay == nil || !g.replay.Playback() {
+				// TODO: use background thread queue to avoid multiple writes at same time
+				setUnitWeaponGroups(g.player, g.player.weaponGroups)
+				if err := saveUserWeaponGroups(); err != nil {
+					log.Error("failed to save user weapon groups: " + err.Error())
+				}
 			}
 
 			go g.audio.PlayButtonAudio(AUDIO_BUTTON_OVER)
@@ -653,15 +738,15 @@ func (g *Game) handleInput() {
 		// set currently selected weapon/group if weapon group number key pressed
 		selectGroupIndex := model.WEAPON_GROUP_NONE
 		switch {
-		case g.input.ActionIsJustPressed(ActionWeaponGroup1):
+		case in.ActionIsJustPressed(ActionWeaponGroup1):
 			selectGroupIndex = model.WEAPON_GROUP_1
-		case g.input.ActionIsJustPressed(ActionWeaponGroup2):
+		case in.ActionIsJustPressed(ActionWeaponGroup2):
 			selectGroupIndex = model.WEAPON_GROUP_2
-		case g.input.ActionIsJustPressed(ActionWeaponGroup3):
+		case in.ActionIsJustPressed(ActionWeaponGroup3):
 			selectGroupIndex = model.WEAPON_GROUP_3
-		case g.input.ActionIsJustPressed(ActionWeaponGroup4):
+		case in.ActionIsJustPressed(ActionWeaponGroup4):
 			selectGroupIndex = model.WEAPON_GROUP_4
-		case g.input.ActionIsJustPressed(ActionWeaponGroup5):
+		case in.ActionIsJustPressed(ActionWeaponGroup5):
 			selectGroupIndex = model.WEAPON_GROUP_5
 		}
 
@@ -682,7 +767,7 @@ func (g *Game) handleInput() {
 		}
 	}
 
-	if g.input.ActionIsJustPressed(ActionWeaponGroupFireToggle) {
+	if in.ActionIsJustPressed(ActionWeaponGroupFireToggle) {
 		// toggle group fire mode
 		if g.player.fireMode == model.CHAIN_FIRE {
 			g.player.fireMode = model.GROUP_FIRE
@@ -717,17 +802,17 @@ func (g *Game) handleInput() {
 		}
 	}
 
-	if g.input.ActionIsJustPressed(ActionNavCycle) {
+	if in.ActionIsJustPressed(ActionNavCycle) {
 		// cycle nav points
 		g.navPointCycle(true)
 	}
 
-	if g.input.ActionIsJustPressed(ActionRadarRangeCycle) {
+	if in.ActionIsJustPressed(ActionRadarRangeCycle) {
 		// cycle radar HUD range
 		g.cycleRadarRange()
 	}
 
-	if g.input.ActionIsJustPressed(ActionTargetCrosshairs) {
+	if in.ActionIsJustPressed(ActionTargetCrosshairs) {
 		// target on crosshairs
 		targetEntity := g.targetCrosshairs()
 		if targetEntity != nil {
@@ -735,7 +820,7 @@ func (g *Game) handleInput() {
 		}
 	}
 
-	if g.input.ActionIsJustPressed(ActionTargetNearest) {
+	if in.ActionIsJustPressed(ActionTargetNearest) {
 		// target nearest to player
 		targetEntity := g.targetCycle(TARGET_NEAREST)
 		if targetEntity != nil {
@@ -743,7 +828,7 @@ func (g *Game) handleInput() {
 		}
 	}
 
-	if g.input.ActionIsJustPressed(ActionTargetNext) {
+	if in.ActionIsJustPressed(ActionTargetNext) {
 		// cycle player targets
 		targetEntity := g.targetCycle(TARGET_NEXT)
 		if targetEntity != nil {
@@ -751,7 +836,7 @@ func (g *Game) handleInput() {
 		}
 	}
 
-	if g.input.ActionIsJustPressed(ActionTargetPrevious) {
+	if in.ActionIsJustPressed(ActionTargetPrevious) {
 		// cycle player targets in reverse order
 		targetEntity := g.targetCycle(TARGET_PREVIOUS)
 		if targetEntity != nil {
@@ -759,7 +844,7 @@ func (g *Game) handleInput() {
 		}
 	}
 
-	if g.input.ActionIsJustPressed(ActionZoomToggle) {
+	if in.ActionIsJustPressed(ActionZoomToggle) {
 		// toggle zoom
 		if g.camera.FovDepth() != g.zoomFovDepth {
 			// zoom in
@@ -773,7 +858,7 @@ func (g *Game) handleInput() {
 		}
 	}
 
-	if g.input.ActionIsJustPressed(ActionLightAmpToggle) {
+	if in.ActionIsJustPressed(ActionLightAmpToggle) {
 		// toggle light amplification
 		if g.lightAmpEngaged {
 			// disable light amplification
@@ -795,7 +880,7 @@ func (g *Game) handleInput() {
 		g.audio.PlayButtonAudio(AUDIO_CLICK_AFF)
 	}
 
-	if g.input.ActionIsJustPressed(ActionThrottleReverse) {
+	if in.ActionIsJustPressed(ActionThrottleReverse) {
 		// toggle reverse throttle
 		if g.player.TargetVelocity() > 0 {
 			// switch to reverse
@@ -808,7 +893,7 @@ func (g *Game) handleInput() {
 		}
 	}
 
-	if g.input.ActionIsPressed(ActionJumpJet) {
+	if in.ActionIsPressed(ActionJumpJet) {
 		switch {
 		case isVTOL:
 			// TODO: use unit tonnage and gravity to determine ascent speed
@@ -830,7 +915,7 @@ func (g *Game) handleInput() {
 		// reset jump jet active status
 		g.player.SetJumpJetsActive(false)
 
-	} else if g.input.ActionIsPressed(ActionDescend) {
+	} else if in.ActionIsPressed(ActionDescend) {
 		if isVTOL {
 			// TODO: use unit tonnage and gravity to determine descent speed
 			g.player.SetTargetVelocityZ(-g.player.MaxVelocity() / 2)
@@ -841,38 +926,38 @@ func (g *Game) handleInput() {
 	var rotLeft, rotRight bool
 	var lookUp, lookDown, lookLeft, lookRight bool
 
-	if g.input.ActionIsPressed(ActionTurretLeft) {
+	if in.ActionIsPressed(ActionTurretLeft) {
 		lookLeft = true
-	} else if g.input.ActionIsPressed(ActionTurretRight) {
+	} else if in.ActionIsPressed(ActionTurretRight) {
 		lookRight = true
 	}
 
-	if g.input.ActionIsPressed(ActionTurretUp) {
+	if in.ActionIsPressed(ActionTurretUp) {
 		lookUp = true
-	} else if g.input.ActionIsPressed(ActionTurretDown) {
+	} else if in.ActionIsPressed(ActionTurretDown) {
 		lookDown = true
 	}
 
-	if g.input.ActionIsPressed(ActionLeft) {
+	if in.ActionIsPressed(ActionLeft) {
 		rotLeft = true
 	}
-	if g.input.ActionIsPressed(ActionRight) {
+	if in.ActionIsPressed(ActionRight) {
 		rotRight = true
 	}
 
-	if g.input.ActionIsPressed(ActionUp) || moveDy >= 0.2 {
+	if in.ActionIsPressed(ActionUp) || moveDy >= 0.2 {
 		forward = true
 	}
-	if g.input.ActionIsPressed(ActionDown) || moveDy <= -0.2 {
+	if in.ActionIsPressed(ActionDown) || moveDy <= -0.2 {
 		backward = true
 	}
 
-	if g.input.ActionIsPressed(ActionThrottle0) {
+	if in.ActionIsPressed(ActionThrottle0) {
 		stop = true
 	}
 
 	switch {
-	case g.input.ActionIsPressed(ActionJumpJet) && (forward || backward || rotLeft || rotRight):
+	case in.ActionIsPressed(ActionJumpJet) && (forward || backward || rotLeft || rotRight):
 		// set jump jets as directional with desired heading
 		if g.player.JumpJetsActive() {
 			jumpJetHeading := g.player.cameraAngle
@@ -1001,7 +1086,10 @@ func (g *Game) handleDebugInput() {
 		}
 	}
 
-	if ctrl_test && alt_test && g.input.ActionIsJustPressed(ActionCameraCycle) {
+	if ctrl_test && !alt_test && g.input.ActionIsJustPressed(ActionCameraCycle) {
+		// debug only: show/hide AI debug overlay for player target
+		g.toggleAIDebugOverlay()
+	} else if ctrl_test && alt_test && g.input.ActionIsJustPressed(ActionCameraCycle) {
 		// debug only: start/stop CPU profiler
 		if debugProfCPU {
 			pprof.StopCPUProfile()
@@ -5,7 +5,8 @@ import (
 )
 
 type InstantActionMissionOpts struct {
-	enemies []model.Unit
+	enemies    []model.Unit
+	lancemates []string
 }
 
 func (g *Game) LoadInstantActionFromMapPath(mapPath string, opts *InstantActionMissionOpts) (*model.Mission, error) {
@@ -36,6 +37,7 @@ func initInstantActionMission(mission *model.Mission, opts *InstantActionMission
 	var enemies []model.Unit
 	if opts != nil {
 		enemies = opts.enemies
+		mission.AddLancemates(opts.lancemates...)
 	}
 
 	// initialize enemy spawns
@@ -0,0 +1,207 @@
+package game
+
+import (
+	"math"
+
+	"github.com/harbdog/raycaster-go/geom"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	input "github.com/quasilyte/ebitengine-input"
+)
+
+const (
+	// LANCE_COMMAND_WHEEL_DEADZONE is how far turret input must move from the center of the wheel to select a command
+	LANCE_COMMAND_WHEEL_DEADZONE = 30
+
+	// LANCE_COMMAND_WHEEL_RADIUS is the furthest turret input is tracked from the center of the wheel
+	LANCE_COMMAND_WHEEL_RADIUS = 100
+
+	// LANCE_COMMAND_MESSAGE_SECONDS is how long the acknowledgement of an order is shown in the HUD banner
+	LANCE_COMMAND_MESSAGE_SECONDS = 3.0
+)
+
+// lanceCommandActions are the input actions that give each command directly, without the command wheel
+var lanceCommandActions = [AI_COMMAND_COUNT]input.Action{
+	AI_COMMAND_ATTACK_TARGET: ActionCommandAttack,
+	AI_COMMAND_FORM_ON_ME:    ActionCommandFormOnMe,
+	AI_COMMAND_HOLD_POSITION: ActionCommandHold,
+	AI_COMMAND_NAV_POINT:     ActionCommandNavPoint,
+	AI_COMMAND_CEASE_FIRE:    ActionCommandCeaseFire,
+}
+
+// LanceCommands is the state of the player command wheel and acknowledgement of orders given to lancemates
+type LanceCommands struct {
+	wheelOpen      bool
+	wheelX, wheelY float64
+	selected       AICommand
+
+	message        string
+	messageSeconds float64
+}
+
+func NewLanceCommands() *LanceCommands {
+	return &LanceCommands{selected: -1}
+}
+
+// WheelOpen returns true if the command wheel is being shown, and the command currently selected from it or -1 if none
+func (c *LanceCommands) WheelOpen() (bool, AICommand) {
+	if c == nil {
+		return false, -1
+	}
+	return c.wheelOpen, c.selected
+}
+
+// Message returns the acknowledgement of the last order to show in the HUD banner, if any
+func (c *LanceCommands) Message(g *Game) string {
+	if c == nil || len(c.message) == 0 || g.mission.TimerSeconds() > c.messageSeconds {
+		return ""
+	}
+	return c.message
+}
+
+func (c *LanceCommands) showMessage(g *Game, message string) {
+	c.message = message
+	c.messageSeconds = g.mission.TimerSeconds() + LANCE_COMMAND_MESSAGE_SECONDS
+}
+
+// handleLanceCommandInput handles player input giving orders to lancemates, returning true if turret input
+// was used to select from the command wheel so it should not also move the turret
+func (g *Game) handleLanceCommandInput(in playerInput, turretDx, turretDy float64) bool {
+	c := g.commands
+	if c == nil {
+		return false
+	}
+
+	for command, action := range lanceCommandActions {
+		if in.ActionIsJustPressed(action) {
+			g.commandLancemates(AICommand(command))
+		}
+	}
+
+	wheelPressed := in.ActionIsPressed(ActionCommandWheel)
+	switch {
+	case wheelPressed && !c.wheelOpen:
+		c.wheelOpen = true
+		c.wheelX, c.wheelY = 0, 0
+		c.selected = -1
+
+	case !wheelPressed && c.wheelOpen:
+		// give the selected command when the wheel is released
+		c.wheelOpen = false
+		if c.selected >= 0 {
+			g.commandLancemates(c.selected)
+		}
+		c.selected = -1
+	}
+
+	if !c.wheelOpen {
+		return false
+	}
+
+	// turret input moves away from the center of the wheel towards a command, screen up is positive Y
+	c.wheelX -= turretDx
+	c.wheelY += turretDy
+	if wheelDist := math.Hypot(c.wheelX, c.wheelY); wheelDist > LANCE_COMMAND_WHEEL_RADIUS {
+		c.wheelX *= LANCE_COMMAND_WHEEL_RADIUS / wheelDist
+		c.wheelY *= LANCE_COMMAND_WHEEL_RADIUS / wheelDist
+	}
+
+	c.selected = lanceCommandWheelSelection(c.wheelX, c.wheelY)
+	return true
+}
+
+// lanceCommandWheelSelection returns the command in the direction from the center of the wheel, starting from the top
+// and going clockwise, or -1 if still within the deadzone
+func lanceCommandWheelSelection(x, y float64) AICommand {
+	if math.Hypot(x, y) < LANCE_COMMAND_WHEEL_DEADZONE {
+		return -1
+	}
+
+	angle := math.Atan2(x, y)
+	if angle < 0 {
+		angle += geom.Pi2
+	}
+	segment := geom.Pi2 / float64(AI_COMMAND_COUNT)
+	return AICommand(int(math.Round(angle/segment)) % int(AI_COMMAND_COUNT))
+}
+
+// commandLancemates gives an order to all player lancemates still in play, returning true if the order was given
+func (g *Game) commandLancemates(command AICommand) bool {
+	lancemates := g.ai.Lancemates()
+	if len(lancemates) == 0 {
+		g.commands.showMessage(g, "No lancemates to command")
+		go g.audio.PlayButtonAudio(AUDIO_BUTTON_NEG)
+		return false
+	}
+
+	var target model.Unit
+	var navPos geom.Vector2
+	switch command {
+	case AI_COMMAND_ATTACK_TARGET:
+		target = model.EntityUnit(g.player.Target())
+		if target == nil || target.IsDestroyed() || g.IsFriendly(g.player, target) {
+			g.commands.showMessage(g, "Lance: no enemy target to attack")
+			go g.audio.PlayButtonAudio(AUDIO_BUTTON_NEG)
+			return false
+		}
+
+	case AI_COMMAND_NAV_POINT:
+		navPoint := g.player.NavPoint()
+		if navPoint == nil {
+			g.commands.showMessage(g, "Lance: no nav point selected")
+			go g.audio.PlayButtonAudio(AUDIO_BUTTON_NEG)
+			return false
+		}
+		navPos = navPoint.Pos()
+	}
+
+	formation := g.ai.lancemates
+	pPos := g.player.Pos()
+	navLine := geom.Line{X1: pPos.X, Y1: pPos.Y, X2: navPos.X, Y2: navPos.Y}
+	navHeading := navLine.Angle()
+
+	for _, a := range lancemates {
+		order := a.order
+		switch command {
+		case AI_COMMAND_CEASE_FIRE:
+			// keep moving as ordered, only stop firing until given another order
+			if order.command == AI_COMMAND_ATTACK_TARGET {
+				order.command = AI_COMMAND_FORM_ON_ME
+				order.target = nil
+			}
+			order.holdFire = true
+			a.u.SetTarget(nil)
+			continue
+
+		case AI_COMMAND_ATTACK_TARGET:
+			order.target = target
+			order.position = nil
+
+		case AI_COMMAND_FORM_ON_ME:
+			order.target = nil
+			order.position = nil
+
+		case AI_COMMAND_HOLD_POSITION:
+			uPos := a.u.Pos()
+			order.target = nil
+			order.position = &geom.Vector2{X: uPos.X, Y: uPos.Y}
+
+		case AI_COMMAND_NAV_POINT:
+			// spread out around the nav point in the same formation as following the player
+			slotPos := model.FormationSlotPosition(formation.shape, formation.slots[a.u], formation.spacing, navPos, navHeading)
+			if !g.isOpenPosition(slotPos) {
+				slotPos = navPos
+			}
+			order.target = nil
+			order.position = &slotPos
+		}
+
+		// any other order also resumes firing
+		order.command = command
+		order.holdFire = false
+		a.piloting.Reset()
+	}
+
+	g.commands.showMessage(g, "Lance: "+command.String())
+	go g.audio.PlayButtonAudio(AUDIO_BUTTON_AFF)
+	return true
+}
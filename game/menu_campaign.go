@@ -0,0 +1,276 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ebitenui/ebitenui"
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type CampaignMenu struct {
+	*MenuModel
+	selectedFile string
+	continueBtn  *widget.Button
+}
+
+type campaignMenuPage struct {
+	title        string
+	campaignFile string
+	campaign     *model.Campaign
+}
+
+func createCampaignMenu(g *Game) *CampaignMenu {
+	var ui *ebitenui.UI = &ebitenui.UI{}
+
+	menu := &CampaignMenu{
+		MenuModel: &MenuModel{
+			game:   g,
+			ui:     ui,
+			active: true,
+		},
+	}
+
+	menu.initResources()
+	menu.initMenu()
+
+	return menu
+}
+
+func (m *CampaignMenu) initMenu() {
+	m.MenuModel.initMenu()
+	m.root.SetBackgroundImage(m.Resources().background)
+
+	// menu title
+	titleBar := campaignTitleContainer(m)
+	m.root.AddChild(titleBar)
+
+	// footer is created first so the continue button is available when the first campaign is selected
+	footer := campaignMenuFooterContainer(m)
+
+	// campaign selection
+	selection := campaignMenuSelectionContainer(m)
+	m.root.AddChild(selection)
+
+	m.root.AddChild(footer)
+}
+
+func (m *CampaignMenu) Update() {
+	m.ui.Update()
+}
+
+func (m *CampaignMenu) Draw(screen *ebiten.Image) {
+	m.ui.Draw(screen)
+}
+
+func campaignTitleContainer(m *CampaignMenu) *widget.Container {
+	res := m.Resources()
+
+	c := widget.NewContainer(
+		widget.ContainerOpts.BackgroundImage(res.panel.titleBar),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(widget.GridLayoutOpts.Columns(1),
+			widget.GridLayoutOpts.Stretch([]bool{true}, []bool{true}),
+			widget.GridLayoutOpts.Padding(&widget.Insets{
+				Left:   m.Padding(),
+				Right:  m.Padding(),
+				Top:    m.Padding(),
+				Bottom: m.Padding(),
+			}))))
+
+	c.AddChild(widget.NewText(
+		widget.TextOpts.Text("Campaign Selection", res.text.bigTitleFace, res.text.idleColor),
+		widget.TextOpts.Position(widget.TextPositionStart, widget.TextPositionCenter),
+	))
+
+	return c
+}
+
+func campaignMenuFooterContainer(m *CampaignMenu) *widget.Container {
+	game := m.Game()
+	res := m.Resources()
+
+	c := widget.NewContainer(
+		widget.ContainerOpts.BackgroundImage(res.panel.titleBar),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(widget.GridLayoutOpts.Columns(4),
+			widget.GridLayoutOpts.Stretch([]bool{false, true, false, false}, []bool{false}),
+			widget.GridLayoutOpts.Spacing(m.Spacing(), 0),
+			widget.GridLayoutOpts.Padding(&widget.Insets{
+				Left:   m.Padding(),
+				Right:  m.Padding(),
+				Top:    m.Padding(),
+				Bottom: m.Padding(),
+			}))))
+
+	back := widget.NewButton(
+		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ButtonOpts.Image(res.button.image),
+		widget.ButtonOpts.Text("Back", res.button.face, res.button.text),
+		widget.ButtonOpts.TextPadding(res.button.padding),
+		widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+			iScene, _ := game.scene.(MenuScene)
+			iScene.back()
+		}),
+	)
+	c.AddChild(back)
+
+	c.AddChild(newBlankSeparator(m.Resources(), m.Padding(), widget.RowLayoutData{
+		Stretch: true,
+	}))
+
+	newCampaign := widget.NewButton(
+		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ButtonOpts.Image(res.button.image),
+		widget.ButtonOpts.Text("New Campaign", res.button.face, res.button.text),
+		widget.ButtonOpts.TextPadding(res.button.padding),
+		widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+			if err := game.NewCampaign(m.selectedFile); err != nil {
+				log.Error("Error starting campaign: ", m.selectedFile)
+				log.Error(err)
+				return
+			}
+			iScene, _ := game.scene.(MenuScene)
+			iScene.next()
+		}),
+	)
+	c.AddChild(newCampaign)
+
+	m.continueBtn = widget.NewButton(
+		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ButtonOpts.Image(res.button.image),
+		widget.ButtonOpts.Text("Continue", res.button.face, res.button.text),
+		widget.ButtonOpts.TextPadding(res.button.padding),
+		widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+			if err := game.LoadCampaign(m.selectedFile); err != nil {
+				log.Error("Error loading campaign save: ", m.selectedFile)
+				log.Error(err)
+				return
+			}
+			iScene, _ := game.scene.(MenuScene)
+			iScene.next()
+		}),
+	)
+	c.AddChild(m.continueBtn)
+
+	return c
+}
+
+func campaignMenuSelectionContainer(m *CampaignMenu) widget.PreferredSizeLocateableWidget {
+	res := m.Resources()
+	g := m.Game()
+
+	campaignList, err := model.ListCampaignFilenames()
+	if err != nil {
+		log.Error(err)
+	}
+
+	c := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Padding(&widget.Insets{
+				Left:  m.Spacing(),
+				Right: m.Spacing(),
+			}),
+			widget.GridLayoutOpts.Columns(2),
+			widget.GridLayoutOpts.Stretch([]bool{false, true}, []bool{true}),
+			widget.GridLayoutOpts.Spacing(m.Spacing(), 0),
+		)))
+
+	pages := make([]any, 0, len(campaignList))
+	for _, campaignFile := range campaignList {
+		campaign, err := model.LoadCampaign(campaignFile)
+		if err != nil {
+			log.Error("Error loading campaign: ", campaignFile)
+			log.Error(err)
+			continue
+		}
+		pages = append(pages, &campaignMenuPage{
+			title:        strings.ToTitle(strings.ReplaceAll(strings.TrimSuffix(campaignFile, ".yaml"), "_", " ")),
+			campaignFile: campaignFile,
+			campaign:     campaign,
+		})
+	}
+
+	// campaign details panel
+	details := widget.NewContainer(
+		widget.ContainerOpts.BackgroundImage(res.panel.image),
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Direction(widget.DirectionVertical),
+			widget.RowLayoutOpts.Padding(res.panel.padding),
+			widget.RowLayoutOpts.Spacing(m.Spacing()))),
+	)
+
+	titleText := widget.NewText(
+		widget.TextOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.TextOpts.Text("", res.text.titleFace, res.text.idleColor))
+	details.AddChild(titleText)
+
+	briefingText := newTextArea("", res, widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+		Stretch:   true,
+		MaxHeight: g.uiRect().Dy() / 3,
+	}))
+	details.AddChild(briefingText)
+
+	statusText := widget.NewText(
+		widget.TextOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.TextOpts.Text("", res.text.face, res.text.idleColor))
+	details.AddChild(statusText)
+
+	pageList := widget.NewList(
+		widget.ListOpts.Entries(pages),
+		widget.ListOpts.EntryLabelFunc(func(e any) string {
+			return e.(*campaignMenuPage).title
+		}),
+		widget.ListOpts.ScrollContainerImage(res.list.image),
+		widget.ListOpts.SliderParams(&widget.SliderParams{
+			TrackImage:    res.list.track,
+			HandleImage:   res.list.handle,
+			MinHandleSize: res.list.handleSize,
+			TrackPadding:  res.list.trackPadding,
+		}),
+		widget.ListOpts.EntryColor(res.list.entry),
+		widget.ListOpts.EntryFontFace(res.list.face),
+		widget.ListOpts.EntryTextPadding(res.list.entryPadding),
+		widget.ListOpts.HideHorizontalSlider(),
+		widget.ListOpts.EntryTextPosition(widget.TextPositionStart, widget.TextPositionCenter),
+		widget.ListOpts.EntrySelectedHandler(func(args *widget.ListEntrySelectedEventArgs) {
+			page := args.Entry.(*campaignMenuPage)
+			m.selectedFile = page.campaignFile
+
+			hasSave := HasCampaignSave(page.campaignFile)
+			m.continueBtn.GetWidget().Disabled = !hasSave
+
+			saveStr := "No"
+			if hasSave {
+				saveStr = "Yes"
+			}
+
+			titleText.Label = page.campaign.Title
+			briefingText.SetText(page.campaign.Briefing)
+			statusText.Label = fmt.Sprintf("Missions: %d | Saved Progress: %s", len(page.campaign.Missions), saveStr)
+			m.Root().RequestRelayout()
+		}))
+
+	c.AddChild(pageList)
+	c.AddChild(details)
+
+	if len(pages) > 0 {
+		pageList.SetSelectedEntry(pages[0])
+	} else {
+		m.continueBtn.GetWidget().Disabled = true
+	}
+
+	return c
+}
@@ -118,9 +118,17 @@ func debriefMenuFooterContainer(m *DebriefMenu) *widget.Container {
 	)
 	c.AddChild(back)
 
-	c.AddChild(newBlankSeparator(m.Resources(), m.Padding(), widget.RowLayoutData{
-		Stretch: true,
-	}))
+	if game.campaign != nil && game.campaign.result != nil {
+		// show campaign rewards and salvage from the mission
+		c.AddChild(widget.NewText(
+			widget.TextOpts.Text(game.campaign.result.String(), res.text.face, res.text.idleColor),
+			widget.TextOpts.Position(widget.TextPositionCenter, widget.TextPositionCenter),
+		))
+	} else {
+		c.AddChild(newBlankSeparator(m.Resources(), m.Padding(), widget.RowLayoutData{
+			Stretch: true,
+		}))
+	}
 
 	return c
 }
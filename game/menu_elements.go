@@ -333,12 +333,22 @@ func openExitWindow(m Menu) {
 				widget.ButtonOpts.TextPadding(res.button.padding),
 				widget.ButtonOpts.Text("Leave Battle", res.button.face, res.button.text),
 				widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+					if game.replay != nil && game.replay.Playback() {
+						// leave replay playback first so settings from the replay are not saved
+						game.LeaveGame()
+						game.saveConfig()
+						return
+					}
+
 					// save config now in case settings changes were made
 					game.saveConfig()
 
 					if game.InProgress() && game.player.ejectionPod == nil {
 						// destroy player to make them eject
 						destroyEntity(game.player)
+						if game.replay != nil {
+							game.replay.recordEject()
+						}
 						game.closeMenu()
 					} else {
 						game.LeaveGame()
@@ -354,6 +364,9 @@ func openExitWindow(m Menu) {
 				widget.ButtonOpts.TextPadding(res.button.padding),
 				widget.ButtonOpts.Text("Exit Game", res.button.face, res.button.text),
 				widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+					// finish any replay recording or playback before exit
+					game.stopReplay()
+
 					// save config now in case settings changes were made
 					game.saveConfig()
 					os.Exit(0)
@@ -107,6 +107,19 @@ func mainMenuItemsContainer(m *MainMenu) *widget.Container {
 	)
 	c.AddChild(missions)
 
+	campaign := widget.NewButton(
+		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ButtonOpts.Image(res.button.image),
+		widget.ButtonOpts.Text("Campaign", res.text.titleFace, res.button.text),
+		widget.ButtonOpts.TextPadding(res.button.padding),
+		widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+			game.scene = NewCampaignScene(game)
+		}),
+	)
+	c.AddChild(campaign)
+
 	settings := widget.NewButton(
 		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
 			Stretch: true,
@@ -15,9 +15,15 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	// map size in cells of the random instant action map in each direction
+	RANDOM_MAP_SIZE int = 200
+)
+
 type MapMenu struct {
 	*MenuModel
-	selectedMap *model.Map
+	selectedMap   *model.Map
+	pageContainer *mapMenuPageContainer
 }
 
 type mapMenuPageContainer struct {
@@ -30,6 +36,7 @@ type mapMenuPageContainer struct {
 type mapMenuPage struct {
 	title    string
 	mapFile  string
+	random   bool
 	content  *widget.Container
 	modelMap *model.Map
 }
@@ -187,9 +194,8 @@ func mapMenuSelectionContainer(m *MapMenu) widget.PreferredSizeLocateableWidget
 			widget.GridLayoutOpts.Spacing(m.Spacing(), 0),
 		)))
 
-	pages := make([]any, 0, len(mapList))
-
-	// TODO: add entry for random map
+	pages := make([]any, 0, len(mapList)+1)
+	pages = append(pages, randomMapSelectionPage(m))
 
 	for _, mapFile := range mapList {
 		if !g.debug && strings.HasPrefix(strings.ToLower(mapFile), "debug") {
@@ -201,6 +207,7 @@ func mapMenuSelectionContainer(m *MapMenu) widget.PreferredSizeLocateableWidget
 	}
 
 	pageContainer := newMapMenuPageContainer(m)
+	m.pageContainer = pageContainer
 
 	pageList := widget.NewList(
 		widget.ListOpts.Entries(pages),
@@ -232,7 +239,8 @@ func mapMenuSelectionContainer(m *MapMenu) widget.PreferredSizeLocateableWidget
 
 	c.AddChild(pageContainer.widget)
 
-	pageList.SetSelectedEntry(pages[0])
+	// select the first map file, the random map is only generated if it is selected
+	pageList.SetSelectedEntry(pages[min(1, len(pages)-1)])
 
 	return c
 }
@@ -294,9 +302,35 @@ func mapSelectionPage(_ *MapMenu, mapFile string) *mapMenuPage {
 	return page
 }
 
+func randomMapSelectionPage(_ *MapMenu) *mapMenuPage {
+	// create page stub container, not generating map data until it is selected
+	page := &mapMenuPage{
+		title:   strings.ToTitle("Random Map"),
+		random:  true,
+		content: newPageContentContainer(),
+	}
+	return page
+}
+
 func (p *mapMenuPage) setMap(m *MapMenu) {
 	p.content.RemoveChildren()
-	if p.modelMap == nil {
+	if p.modelMap == nil && p.random {
+		// generate random map data with the same generator as the map generate command
+		biomes := model.MapBiomes()
+		opts := model.MapGenerateOptions{
+			Seed:   model.NewRNG().Int63(),
+			Width:  RANDOM_MAP_SIZE,
+			Height: RANDOM_MAP_SIZE,
+			Biome:  biomes[model.RandIntn(len(biomes))],
+		}
+		var err error
+		p.modelMap, err = model.LoadGeneratedMap(opts)
+		if err != nil {
+			log.Errorf("Error generating random map: %+v", opts)
+			log.Error(err)
+			exit(1)
+		}
+	} else if p.modelMap == nil {
 		// load map data
 		var err error
 		p.modelMap, err = model.LoadMap(p.mapFile)
@@ -307,6 +341,26 @@ func (p *mapMenuPage) setMap(m *MapMenu) {
 		}
 	}
 
+	if p.random {
+		res := m.Resources()
+		regenerate := widget.NewButton(
+			widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+				Stretch: true,
+			})),
+			widget.ButtonOpts.Image(res.button.image),
+			widget.ButtonOpts.Text("Generate New Map", res.button.face, res.button.text),
+			widget.ButtonOpts.TextPadding(res.button.padding),
+			widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+				p.modelMap = nil
+				m.pageContainer.setPage(p)
+				m.Root().RequestRelayout()
+
+				m.selectedMap = p.modelMap
+			}),
+		)
+		p.content.AddChild(regenerate)
+	}
+
 	mapCard := createMapCard(m.game, m.Resources(), p.modelMap, MapCardSelect)
 	p.content.AddChild(mapCard)
 }
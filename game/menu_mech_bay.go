@@ -0,0 +1,303 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/ebitenui/ebitenui"
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type MechBayMenu struct {
+	*MenuModel
+	titleText    *widget.Text
+	nextMission  string
+	rosterList   *widget.List
+	unitContent  *widget.Container
+	statusText   *widget.TextArea
+	repairBtn    *widget.Button
+	launchBtn    *widget.Button
+	tickUpdaters []tickUpdater
+}
+
+type mechBayEntry struct {
+	index int
+	unit  model.Unit
+}
+
+func createMechBayMenu(g *Game) *MechBayMenu {
+	var ui *ebitenui.UI = &ebitenui.UI{}
+
+	menu := &MechBayMenu{
+		MenuModel: &MenuModel{
+			game:   g,
+			ui:     ui,
+			active: true,
+		},
+	}
+
+	menu.initResources()
+	menu.initMenu()
+
+	return menu
+}
+
+func (m *MechBayMenu) initMenu() {
+	m.MenuModel.initMenu()
+	m.root.SetBackgroundImage(m.Resources().background)
+
+	// menu title
+	titleBar := mechBayTitleContainer(m)
+	m.root.AddChild(titleBar)
+
+	// mech roster
+	roster := mechBayRosterContainer(m)
+	m.root.AddChild(roster)
+
+	// footer
+	footer := mechBayFooterContainer(m)
+	m.root.AddChild(footer)
+}
+
+func (m *MechBayMenu) Update() {
+	for _, updater := range m.tickUpdaters {
+		updater.update()
+	}
+	m.ui.Update()
+}
+
+func (m *MechBayMenu) Draw(screen *ebiten.Image) {
+	m.ui.Draw(screen)
+}
+
+func mechBayTitleContainer(m *MechBayMenu) *widget.Container {
+	res := m.Resources()
+
+	c := widget.NewContainer(
+		widget.ContainerOpts.BackgroundImage(res.panel.titleBar),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(widget.GridLayoutOpts.Columns(1),
+			widget.GridLayoutOpts.Stretch([]bool{true}, []bool{true}),
+			widget.GridLayoutOpts.Padding(&widget.Insets{
+				Left:   m.Padding(),
+				Right:  m.Padding(),
+				Top:    m.Padding(),
+				Bottom: m.Padding(),
+			}))))
+
+	m.titleText = widget.NewText(
+		widget.TextOpts.Text("Mech Bay", res.text.bigTitleFace, res.text.idleColor),
+		widget.TextOpts.Position(widget.TextPositionStart, widget.TextPositionCenter),
+	)
+	c.AddChild(m.titleText)
+
+	return c
+}
+
+func mechBayFooterContainer(m *MechBayMenu) *widget.Container {
+	game := m.Game()
+	res := m.Resources()
+
+	c := widget.NewContainer(
+		widget.ContainerOpts.BackgroundImage(res.panel.titleBar),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(widget.GridLayoutOpts.Columns(4),
+			widget.GridLayoutOpts.Stretch([]bool{false, true, false, false}, []bool{false}),
+			widget.GridLayoutOpts.Spacing(m.Spacing(), 0),
+			widget.GridLayoutOpts.Padding(&widget.Insets{
+				Left:   m.Padding(),
+				Right:  m.Padding(),
+				Top:    m.Padding(),
+				Bottom: m.Padding(),
+			}))))
+
+	back := widget.NewButton(
+		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ButtonOpts.Image(res.button.image),
+		widget.ButtonOpts.Text("Back", res.button.face, res.button.text),
+		widget.ButtonOpts.TextPadding(res.button.padding),
+		widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+			iScene, _ := game.scene.(MenuScene)
+			iScene.back()
+		}),
+	)
+	c.AddChild(back)
+
+	c.AddChild(newBlankSeparator(m.Resources(), m.Padding(), widget.RowLayoutData{
+		Stretch: true,
+	}))
+
+	m.repairBtn = widget.NewButton(
+		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ButtonOpts.Image(res.button.image),
+		widget.ButtonOpts.Text("Repair", res.button.face, res.button.text),
+		widget.ButtonOpts.TextPadding(res.button.padding),
+		widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+			c := game.campaign
+			if err := c.repair(game, c.Selected()); err != nil {
+				log.Error(err)
+				m.setStatus(err.Error())
+				return
+			}
+			m.loadRoster()
+		}),
+	)
+	c.AddChild(m.repairBtn)
+
+	m.launchBtn = widget.NewButton(
+		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ButtonOpts.Image(res.button.image),
+		widget.ButtonOpts.Text("Next", res.button.face, res.button.text),
+		widget.ButtonOpts.TextPadding(res.button.padding),
+		widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+			iScene, _ := game.scene.(MenuScene)
+			iScene.next()
+		}),
+	)
+	c.AddChild(m.launchBtn)
+
+	return c
+}
+
+func mechBayRosterContainer(m *MechBayMenu) widget.PreferredSizeLocateableWidget {
+	res := m.Resources()
+	g := m.Game()
+
+	c := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Padding(&widget.Insets{
+				Left:  m.Spacing(),
+				Right: m.Spacing(),
+			}),
+			widget.GridLayoutOpts.Columns(2),
+			widget.GridLayoutOpts.Stretch([]bool{false, true}, []bool{true}),
+			widget.GridLayoutOpts.Spacing(m.Spacing(), 0),
+		)))
+
+	details := widget.NewContainer(
+		widget.ContainerOpts.BackgroundImage(res.panel.image),
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Direction(widget.DirectionVertical),
+			widget.RowLayoutOpts.Padding(res.panel.padding),
+			widget.RowLayoutOpts.Spacing(m.Spacing()))),
+	)
+
+	m.statusText = newTextArea("", res, widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+		Stretch:   true,
+		MaxHeight: g.uiRect().Dy() / 6,
+	}))
+	details.AddChild(m.statusText)
+
+	m.unitContent = newPageContentContainer()
+	details.AddChild(m.unitContent)
+
+	m.rosterList = widget.NewList(
+		widget.ListOpts.Entries([]any{}),
+		widget.ListOpts.EntryLabelFunc(func(e any) string {
+			entry := e.(*mechBayEntry)
+			label := fmt.Sprintf("%s %s", entry.unit.Name(), entry.unit.Variant())
+			switch {
+			case entry.unit.IsDestroyed():
+				label += " (Destroyed)"
+			case entry.unit.HasDamage():
+				label += " (Damaged)"
+			}
+			return label
+		}),
+		widget.ListOpts.ScrollContainerImage(res.list.image),
+		widget.ListOpts.SliderParams(&widget.SliderParams{
+			TrackImage:    res.list.track,
+			HandleImage:   res.list.handle,
+			MinHandleSize: res.list.handleSize,
+			TrackPadding:  res.list.trackPadding,
+		}),
+		widget.ListOpts.EntryColor(res.list.entry),
+		widget.ListOpts.EntryFontFace(res.list.face),
+		widget.ListOpts.EntryTextPadding(res.list.entryPadding),
+		widget.ListOpts.HideHorizontalSlider(),
+		widget.ListOpts.EntryTextPosition(widget.TextPositionStart, widget.TextPositionCenter),
+		widget.ListOpts.EntrySelectedHandler(func(args *widget.ListEntrySelectedEventArgs) {
+			entry := args.Entry.(*mechBayEntry)
+			g.campaign.SetSelected(entry.index)
+			m.setSelected(entry)
+		}))
+
+	c.AddChild(m.rosterList)
+	c.AddChild(details)
+
+	return c
+}
+
+// loadRoster refreshes the mech bay from the current campaign progress
+func (m *MechBayMenu) loadRoster() {
+	g := m.Game()
+	campaign := g.campaign
+
+	m.titleText.Label = "Mech Bay: " + campaign.Title()
+
+	m.nextMission = "Campaign Complete"
+	if cMission := campaign.Mission(); cMission != nil {
+		m.nextMission = "Next Mission: " + cMission.ID
+		if mission, err := model.LoadMission(cMission.Mission); err == nil {
+			m.nextMission = "Next Mission: " + mission.Title
+		}
+	}
+
+	entries := make([]any, 0, len(campaign.Roster()))
+	var selected *mechBayEntry
+	for i := range campaign.Roster() {
+		unit, err := campaign.rosterUnit(g, i)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		entry := &mechBayEntry{index: i, unit: unit}
+		if i == campaign.Selected() {
+			selected = entry
+		}
+		entries = append(entries, entry)
+	}
+
+	m.rosterList.SetEntries(entries)
+	if selected != nil {
+		m.rosterList.SetSelectedEntry(selected)
+	}
+	m.launchBtn.GetWidget().Disabled = campaign.Complete()
+}
+
+func (m *MechBayMenu) setSelected(entry *mechBayEntry) {
+	g := m.Game()
+	campaign := g.campaign
+	res := m.Resources()
+
+	m.unitContent.RemoveChildren()
+	unitCard := createUnitCard(g, res, entry.unit, UnitCardDebrief)
+	m.tickUpdaters = []tickUpdater{unitCard}
+	m.unitContent.AddChild(unitCard)
+
+	repairCost := campaign.repairCost(g, entry.index)
+	m.repairBtn.GetWidget().Disabled = repairCost == 0
+	m.setStatus(fmt.Sprintf("Repair Cost: %d C-Bills", repairCost))
+}
+
+// setStatus shows the campaign status followed by the given message
+func (m *MechBayMenu) setStatus(message string) {
+	campaign := m.Game().campaign
+
+	statusStr := fmt.Sprintf("%s\nC-Bills: %d", m.nextMission, campaign.CBills())
+	if campaign.result != nil {
+		statusStr += "\nLast Mission: " + campaign.result.String()
+	}
+	if message != "" {
+		statusStr += "\n" + message
+	}
+	m.statusText.SetText(statusStr)
+	m.Root().RequestRelayout()
+}
@@ -0,0 +1,431 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ebitenui/ebitenui"
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type MechLabMenu struct {
+	*MenuModel
+	unitMenu *UnitMenu
+	loadout  *model.MechLoadout
+	weapons  []*model.MechLabWeapon
+
+	titleText    *widget.Text
+	armamentList *widget.List
+	weaponCombo  *widget.ListComboButton
+	locationList *widget.ListComboButton
+	equipContent *widget.Container
+	statusText   *widget.TextArea
+	saveBtn      *widget.Button
+
+	// message shown after the loadout status, such as the result of saving
+	message string
+}
+
+type mechLabArmament struct {
+	index    int
+	armament *model.ModelResourceArmament
+	weapon   *model.MechLabWeapon
+}
+
+func createMechLabMenu(g *Game, unitMenu *UnitMenu, mech *model.Mech) (*MechLabMenu, error) {
+	loadout, err := model.NewMechLoadout(g.resources, mech.Resource)
+	if err != nil {
+		return nil, err
+	}
+	loadout.Resource.Variant = nextCustomVariant(g, mech.Resource)
+
+	var ui *ebitenui.UI = &ebitenui.UI{}
+
+	menu := &MechLabMenu{
+		MenuModel: &MenuModel{
+			game:   g,
+			ui:     ui,
+			active: true,
+		},
+		unitMenu: unitMenu,
+		loadout:  loadout,
+		weapons:  g.resources.GetMechLabWeaponList(mech.Resource.Tech.TechBase),
+	}
+
+	menu.initResources()
+	menu.initMenu()
+	menu.refresh()
+
+	return menu, nil
+}
+
+// nextCustomVariant finds an unused variant designation for a custom loadout of the mech chassis
+func nextCustomVariant(g *Game, r *model.ModelMechResource) string {
+	prefix, _, _ := strings.Cut(r.Variant, "-")
+	for i := 1; ; i++ {
+		variant := fmt.Sprintf("%s-C%d", prefix, i)
+
+		found := false
+		for _, v := range g.resources.GetMechResourceList() {
+			if v.Name == r.Name && strings.EqualFold(v.Variant, variant) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return variant
+		}
+	}
+}
+
+func (m *MechLabMenu) initMenu() {
+	m.MenuModel.initMenu()
+	m.root.SetBackgroundImage(m.Resources().background)
+
+	// menu title
+	titleBar := mechLabTitleContainer(m)
+	m.root.AddChild(titleBar)
+
+	// loadout editing
+	loadout := mechLabLoadoutContainer(m)
+	m.root.AddChild(loadout)
+
+	// footer
+	footer := mechLabFooterContainer(m)
+	m.root.AddChild(footer)
+}
+
+func (m *MechLabMenu) Update() {
+	m.ui.Update()
+}
+
+func (m *MechLabMenu) Draw(screen *ebiten.Image) {
+	m.ui.Draw(screen)
+}
+
+func mechLabButton(res *uiResources, label string, clicked func()) *widget.Button {
+	return widget.NewButton(
+		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ButtonOpts.Image(res.button.image),
+		widget.ButtonOpts.Text(label, res.button.face, res.button.text),
+		widget.ButtonOpts.TextPadding(res.button.padding),
+		widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+			clicked()
+		}),
+	)
+}
+
+func mechLabTitleContainer(m *MechLabMenu) *widget.Container {
+	res := m.Resources()
+
+	c := widget.NewContainer(
+		widget.ContainerOpts.BackgroundImage(res.panel.titleBar),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(widget.GridLayoutOpts.Columns(1),
+			widget.GridLayoutOpts.Stretch([]bool{true}, []bool{true}),
+			widget.GridLayoutOpts.Padding(&widget.Insets{
+				Left:   m.Padding(),
+				Right:  m.Padding(),
+				Top:    m.Padding(),
+				Bottom: m.Padding(),
+			}))))
+
+	m.titleText = widget.NewText(
+		widget.TextOpts.Text("Mech Lab", res.text.bigTitleFace, res.text.idleColor),
+		widget.TextOpts.Position(widget.TextPositionStart, widget.TextPositionCenter),
+	)
+	c.AddChild(m.titleText)
+
+	return c
+}
+
+func mechLabFooterContainer(m *MechLabMenu) *widget.Container {
+	res := m.Resources()
+
+	c := widget.NewContainer(
+		widget.ContainerOpts.BackgroundImage(res.panel.titleBar),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(widget.GridLayoutOpts.Columns(3),
+			widget.GridLayoutOpts.Stretch([]bool{false, true, false}, []bool{false}),
+			widget.GridLayoutOpts.Padding(&widget.Insets{
+				Left:   m.Padding(),
+				Right:  m.Padding(),
+				Top:    m.Padding(),
+				Bottom: m.Padding(),
+			}))))
+
+	back := mechLabButton(res, "Back", func() {
+		m.Game().menu = m.unitMenu
+	})
+	c.AddChild(back)
+
+	c.AddChild(newBlankSeparator(m.Resources(), m.Padding(), widget.RowLayoutData{
+		Stretch: true,
+	}))
+
+	m.saveBtn = mechLabButton(res, "Save", func() {
+		unit, err := m.loadout.Save()
+		if err != nil {
+			log.Error(err)
+			m.message = err.Error()
+			m.refresh()
+			return
+		}
+		log.Infof("saved mech lab unit %s", unit)
+
+		// reload the unit selection to include the new variant
+		m.unitMenu.initMenu()
+
+		// continue editing as the next custom variant
+		m.loadout.Resource.Variant = nextCustomVariant(m.Game(), m.loadout.Resource)
+		m.message = "Saved as " + unit
+		m.refresh()
+	})
+	c.AddChild(m.saveBtn)
+
+	return c
+}
+
+func mechLabLoadoutContainer(m *MechLabMenu) widget.PreferredSizeLocateableWidget {
+	res := m.Resources()
+
+	c := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Padding(&widget.Insets{
+				Left:  m.Spacing(),
+				Right: m.Spacing(),
+			}),
+			widget.GridLayoutOpts.Columns(2),
+			widget.GridLayoutOpts.Stretch([]bool{true, true}, []bool{true}),
+			widget.GridLayoutOpts.Spacing(m.Spacing(), 0),
+		)))
+
+	// armament list with controls to add, move and remove weapons
+	armament := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(1),
+			widget.GridLayoutOpts.Stretch([]bool{true}, []bool{true, false, false}),
+			widget.GridLayoutOpts.Spacing(0, m.Spacing()),
+		)))
+
+	m.armamentList = widget.NewList(
+		widget.ListOpts.Entries([]any{}),
+		widget.ListOpts.EntryLabelFunc(func(e any) string {
+			entry := e.(*mechLabArmament)
+			location := strings.ToUpper(entry.armament.Location.ShortName())
+			if entry.weapon == nil {
+				return fmt.Sprintf("%s  %s", location, entry.armament.Weapon)
+			}
+			return fmt.Sprintf("%s  %s (%0.1ft, %d slots)", location, entry.weapon.Name, entry.weapon.Tonnage, entry.weapon.Slots)
+		}),
+		widget.ListOpts.ScrollContainerImage(res.list.image),
+		widget.ListOpts.SliderParams(&widget.SliderParams{
+			TrackImage:    res.list.track,
+			HandleImage:   res.list.handle,
+			MinHandleSize: res.list.handleSize,
+			TrackPadding:  res.list.trackPadding,
+		}),
+		widget.ListOpts.EntryColor(res.list.entry),
+		widget.ListOpts.EntryFontFace(res.list.face),
+		widget.ListOpts.EntryTextPadding(res.list.entryPadding),
+		widget.ListOpts.HideHorizontalSlider(),
+		widget.ListOpts.EntryTextPosition(widget.TextPositionStart, widget.TextPositionCenter),
+	)
+	armament.AddChild(m.armamentList)
+
+	selectors := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(2),
+			widget.GridLayoutOpts.Stretch([]bool{true, false}, []bool{false}),
+			widget.GridLayoutOpts.Spacing(m.Spacing(), 0),
+		)))
+	armament.AddChild(selectors)
+
+	weaponEntries := make([]any, 0, len(m.weapons))
+	for _, w := range m.weapons {
+		weaponEntries = append(weaponEntries, w)
+	}
+	weaponLabel := func(e any) string {
+		w := e.(*model.MechLabWeapon)
+		return fmt.Sprintf("%s (%0.1ft, %d slots)", w.Name, w.Tonnage, w.Slots)
+	}
+	var selectedWeapon any
+	if len(weaponEntries) > 0 {
+		selectedWeapon = weaponEntries[0]
+	}
+	m.weaponCombo = newListComboButton(weaponEntries, selectedWeapon, weaponLabel, weaponLabel,
+		func(args *widget.ListComboButtonEntrySelectedEventArgs) {}, res)
+	selectors.AddChild(m.weaponCombo)
+
+	locationEntries := make([]any, 0, 8)
+	for _, location := range model.UnitTypeLocations(model.MechUnitType) {
+		locationEntries = append(locationEntries, location)
+	}
+	locationLabel := func(e any) string {
+		location := e.(model.Location)
+		return fmt.Sprintf("%s (%d slots)", strings.ToUpper(location.ShortName()), model.MechLocationSlots(location))
+	}
+	m.locationList = newListComboButton(locationEntries, locationEntries[0], locationLabel, locationLabel,
+		func(args *widget.ListComboButtonEntrySelectedEventArgs) {}, res)
+	selectors.AddChild(m.locationList)
+
+	buttons := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(3),
+			widget.GridLayoutOpts.Stretch([]bool{true, true, true}, []bool{false}),
+			widget.GridLayoutOpts.Spacing(m.Spacing(), 0),
+		)))
+	armament.AddChild(buttons)
+
+	buttons.AddChild(mechLabButton(res, "Add", func() {
+		w, _ := m.weaponCombo.SelectedEntry().(*model.MechLabWeapon)
+		location, _ := m.locationList.SelectedEntry().(model.Location)
+		if w == nil {
+			return
+		}
+		m.loadout.AddWeapon(w, location)
+		m.message = ""
+		m.refresh()
+	}))
+	buttons.AddChild(mechLabButton(res, "Move", func() {
+		entry, _ := m.armamentList.SelectedEntry().(*mechLabArmament)
+		location, _ := m.locationList.SelectedEntry().(model.Location)
+		if entry == nil {
+			return
+		}
+		m.loadout.MoveWeapon(entry.index, location)
+		m.message = ""
+		m.refresh()
+	}))
+	buttons.AddChild(mechLabButton(res, "Remove", func() {
+		entry, _ := m.armamentList.SelectedEntry().(*mechLabArmament)
+		if entry == nil {
+			return
+		}
+		m.loadout.RemoveWeapon(entry.index)
+		m.message = ""
+		m.refresh()
+	}))
+
+	c.AddChild(armament)
+
+	// loadout status with heat sinks and ammo adjustments
+	details := widget.NewContainer(
+		widget.ContainerOpts.BackgroundImage(res.panel.image),
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Direction(widget.DirectionVertical),
+			widget.RowLayoutOpts.Padding(res.panel.padding),
+			widget.RowLayoutOpts.Spacing(m.Spacing()))),
+	)
+
+	m.equipContent = widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(4),
+			widget.GridLayoutOpts.Stretch([]bool{true, false, false, false}, nil),
+			widget.GridLayoutOpts.Spacing(m.Spacing(), m.Spacing()/4),
+		)))
+	details.AddChild(m.equipContent)
+
+	m.statusText = newTextArea("", res, widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+		Stretch:   true,
+		MaxHeight: m.Game().uiRect().Dy() / 2,
+	}))
+	details.AddChild(m.statusText)
+
+	c.AddChild(details)
+
+	return c
+}
+
+// refresh updates the menu content from the current loadout
+func (m *MechLabMenu) refresh() {
+	g := m.Game()
+	l := m.loadout
+	r := l.Resource
+
+	m.titleText.Label = fmt.Sprintf("Mech Lab: %s %s", r.Name, r.Variant)
+
+	// armament list
+	selected, _ := m.armamentList.SelectedEntry().(*mechLabArmament)
+	entries := make([]any, 0, len(r.Armament))
+	var selectedEntry *mechLabArmament
+	for i, a := range r.Armament {
+		w, err := g.resources.GetMechLabWeapon(a.Weapon, a.Type.WeaponType)
+		if err != nil {
+			log.Error(err)
+		}
+		entry := &mechLabArmament{index: i, armament: a, weapon: w}
+		if selected != nil && selected.armament == a {
+			selectedEntry = entry
+		}
+		entries = append(entries, entry)
+	}
+	m.armamentList.SetEntries(entries)
+	if selectedEntry != nil {
+		m.armamentList.SetSelectedEntry(selectedEntry)
+	}
+
+	// heat sinks and ammo
+	res := m.Resources()
+	m.equipContent.RemoveChildren()
+
+	addEquipRow := func(label, value string, decrease, increase func()) {
+		m.equipContent.AddChild(newUnitContentText(res, label))
+		m.equipContent.AddChild(mechLabButton(res, "-", func() {
+			decrease()
+			m.message = ""
+			m.refresh()
+		}))
+		m.equipContent.AddChild(newUnitContentText(res, value))
+		m.equipContent.AddChild(mechLabButton(res, "+", func() {
+			increase()
+			m.message = ""
+			m.refresh()
+		}))
+	}
+
+	heatSinks := r.HeatSinks.Quantity
+	heatSinkLabel := "Heat Sinks:"
+	if r.HeatSinks.Type.HeatSinkType == model.DOUBLE {
+		heatSinkLabel = "Double Heat Sinks:"
+	}
+	addEquipRow(heatSinkLabel, fmt.Sprintf("%d", heatSinks),
+		func() { l.SetHeatSinks(heatSinks - 1) },
+		func() { l.SetHeatSinks(heatSinks + 1) },
+	)
+
+	for _, ammo := range l.AmmoList() {
+		ammoName := ammo.Type.ShortName()
+		if ammo.ForWeapon != "" {
+			if w, err := g.resources.GetMechLabWeapon(ammo.ForWeapon, model.BALLISTIC); err == nil {
+				ammoName = w.ShortName
+			}
+		}
+		addEquipRow(ammoName+" Ammo:", fmt.Sprintf("%0.1ft", ammo.Tons),
+			func() { l.SetAmmoTons(ammo, ammo.Tons-1) },
+			func() { l.SetAmmoTons(ammo, ammo.Tons+1) },
+		)
+	}
+
+	// tonnage, critical slots and validation status
+	status := fmt.Sprintf("Tonnage: %0.1f / %0.0f\nCritical Slots: %d / %d\n", l.Tonnage(), l.MaxTonnage(), l.Slots(), l.MaxSlots())
+	for _, location := range model.UnitTypeLocations(model.MechUnitType) {
+		status += fmt.Sprintf("  %s: %d / %d\n", strings.ToUpper(location.ShortName()), l.LocationSlots(location), model.MechLocationSlots(location))
+	}
+
+	err := l.Validate()
+	if err != nil {
+		status += "\n" + err.Error()
+	}
+	if m.message != "" {
+		status += "\n" + m.message
+	}
+	m.statusText.SetText(status)
+
+	m.saveBtn.GetWidget().Disabled = err != nil
+	m.Root().RequestRelayout()
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/pixelmek-3d/pixelmek-3d/game/model"
 	"github.com/pixelmek-3d/pixelmek-3d/game/render"
 	"github.com/pixelmek-3d/pixelmek-3d/game/resources"
+
+	log "github.com/sirupsen/logrus"
 )
 
 type settingsPageContainer struct {
@@ -143,12 +145,13 @@ func gameMissionPage(m Menu) *settingsPage {
 	)
 	c.AddChild(mContainer)
 
-	// show container with Exit/Resume buttons
+	// show container with Exit/Save/Load/Resume buttons
 	bContainer := widget.NewContainer(
 		// TODO: fix exit/resume container buttons not stretching to fit width
 		widget.ContainerOpts.BackgroundImage(res.panel.titleBar),
-		widget.ContainerOpts.Layout(widget.NewGridLayout(widget.GridLayoutOpts.Columns(3),
-			widget.GridLayoutOpts.Stretch([]bool{false, true, false}, []bool{false}),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(widget.GridLayoutOpts.Columns(5),
+			widget.GridLayoutOpts.Stretch([]bool{false, true, false, false, false}, []bool{false}),
+			widget.GridLayoutOpts.Spacing(m.Spacing(), 0),
 			widget.GridLayoutOpts.Padding(&widget.Insets{
 				Left:   m.Padding(),
 				Right:  m.Padding(),
@@ -181,6 +184,36 @@ func gameMissionPage(m Menu) *settingsPage {
 		Stretch: true,
 	}))
 
+	saveButtons := &saveGameButtons{}
+	saveButtons.save = widget.NewButton(
+		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ButtonOpts.Image(res.button.image),
+		widget.ButtonOpts.Text("Save", res.button.face, res.button.text),
+		widget.ButtonOpts.TextPadding(res.button.padding),
+		widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+			if err := g.quickSave(); err != nil {
+				log.Error("Error saving game: ", err)
+				return
+			}
+			g.closeMenu()
+		}),
+	)
+	bContainer.AddChild(saveButtons.save)
+
+	saveButtons.load = widget.NewButton(
+		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ButtonOpts.Image(res.button.image),
+		widget.ButtonOpts.Text("Load", res.button.face, res.button.text),
+		widget.ButtonOpts.TextPadding(res.button.padding),
+		widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) { g.loadQuickSave() }),
+	)
+	bContainer.AddChild(saveButtons.load)
+	saveButtons.updateContent(g)
+
 	resume := widget.NewButton(
 		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
 			Stretch: true,
@@ -199,10 +232,21 @@ func gameMissionPage(m Menu) *settingsPage {
 	return &settingsPage{
 		title:           "Mission",
 		content:         c,
-		contentUpdaters: []contentUpdater{missionCard},
+		contentUpdaters: []contentUpdater{missionCard, saveButtons},
 	}
 }
 
+// saveGameButtons are the in-game menu buttons to save and load the mission in progress
+type saveGameButtons struct {
+	save *widget.Button
+	load *widget.Button
+}
+
+func (b *saveGameButtons) updateContent(g *Game) {
+	b.save.GetWidget().Disabled = g.CanSaveGame() != nil
+	b.load.GetWidget().Disabled = g.replay != nil || g.client != nil || !HasQuickSave()
+}
+
 func gameUnitPage(m Menu) *settingsPage {
 	c := newPageContentContainer()
 	res := m.Resources()
@@ -16,12 +16,15 @@ import (
 	"github.com/pixelmek-3d/pixelmek-3d/game/render/sprites"
 	"github.com/pixelmek-3d/pixelmek-3d/game/resources"
 	"github.com/tinne26/etxt"
+
+	log "github.com/sirupsen/logrus"
 )
 
 type UnitMenu struct {
 	*MenuModel
 	purpose      UnitMenuPurpose
 	selectedUnit model.Unit
+	mechLabBtn   *widget.Button
 	tickUpdaters []tickUpdater
 }
 
@@ -163,10 +166,17 @@ func unitMenuFooterContainer(m *UnitMenu) *widget.Container {
 	game := m.Game()
 	res := m.Resources()
 
+	columns, stretch := 3, []bool{false, true, false}
+	if m.purpose == PlayerUnitMenu {
+		// player unit selection also has the mech lab to customize the selected unit
+		columns, stretch = 4, []bool{false, true, false, false}
+	}
+
 	c := widget.NewContainer(
 		widget.ContainerOpts.BackgroundImage(res.panel.titleBar),
-		widget.ContainerOpts.Layout(widget.NewGridLayout(widget.GridLayoutOpts.Columns(3),
-			widget.GridLayoutOpts.Stretch([]bool{false, true, false}, []bool{false}),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(widget.GridLayoutOpts.Columns(columns),
+			widget.GridLayoutOpts.Stretch(stretch, []bool{false}),
+			widget.GridLayoutOpts.Spacing(m.Spacing(), 0),
 			widget.GridLayoutOpts.Padding(&widget.Insets{
 				Left:   m.Padding(),
 				Right:  m.Padding(),
@@ -192,6 +202,31 @@ func unitMenuFooterContainer(m *UnitMenu) *widget.Container {
 		Stretch: true,
 	}))
 
+	if m.purpose == PlayerUnitMenu {
+		m.mechLabBtn = widget.NewButton(
+			widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+				Stretch: true,
+			})),
+			widget.ButtonOpts.Image(res.button.image),
+			widget.ButtonOpts.Text("Mech Lab", res.button.face, res.button.text),
+			widget.ButtonOpts.TextPadding(res.button.padding),
+			widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+				mech, ok := m.selectedUnit.(*model.Mech)
+				if !ok {
+					return
+				}
+				mechLab, err := createMechLabMenu(game, m, mech)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+				game.menu = mechLab
+			}),
+		)
+		m.mechLabBtn.GetWidget().Disabled = m.selectedUnit == nil
+		c.AddChild(m.mechLabBtn)
+	}
+
 	next := widget.NewButton(
 		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
 			Stretch: true,
@@ -296,7 +331,7 @@ func unitMenuSelectionContainer(m *UnitMenu) widget.PreferredSizeLocateableWidge
 			pageContainer.setPage(nextPage)
 			m.Root().RequestRelayout()
 
-			m.selectedUnit = nextPage.unit
+			m.setSelectedUnit(nextPage.unit)
 		}))
 
 	c.AddChild(pageList)
@@ -308,6 +343,13 @@ func unitMenuSelectionContainer(m *UnitMenu) widget.PreferredSizeLocateableWidge
 	return c
 }
 
+func (m *UnitMenu) setSelectedUnit(unit model.Unit) {
+	m.selectedUnit = unit
+	if m.mechLabBtn != nil {
+		m.mechLabBtn.GetWidget().Disabled = unit == nil
+	}
+}
+
 func newUnitPageContainer(m *UnitMenu) *unitPageContainer {
 	res := m.Resources()
 
@@ -388,7 +430,7 @@ func (p *unitPageContainer) setPage(page *unitPage) {
 			},
 			func(args *widget.ListComboButtonEntrySelectedEventArgs) {
 				u := args.Entry.(model.Unit)
-				m.selectedUnit = u
+				m.setSelectedUnit(u)
 
 				// update page content info
 				page.setUnit(m, u)
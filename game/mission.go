@@ -9,6 +9,8 @@ import (
 	"github.com/pixelmek-3d/pixelmek-3d/game/render"
 	"github.com/pixelmek-3d/pixelmek-3d/game/resources"
 	"github.com/pixelmek-3d/pixelmek-3d/game/texture"
+
+	log "github.com/sirupsen/logrus"
 )
 
 func (g *Game) LoadMission(missionFile string) (*model.Mission, error) {
@@ -43,9 +45,18 @@ func (g *Game) initMission() {
 	// load map and mission content
 	g.loadContent()
 
+	// index walls and collision entities for collision and line of sight checks
+	g.initSpatialIndex()
+
 	// initialize objectives
 	g.objectives = NewObjectivesHandler(g, g.mission.Objectives)
 
+	// initialize mission triggers
+	g.triggers = NewTriggersHandler(g.mission.Triggers)
+
+	// initialize unit sensors
+	g.sensors = NewSensorsHandler()
+
 	// init player at DZ
 	pX, pY, pDegrees := g.mission.DropZone.Position[0], g.mission.DropZone.Position[1], g.mission.DropZone.Heading
 	pHeading := model.CardinalToAngle(pDegrees)
@@ -105,4 +116,23 @@ func (g *Game) initMission() {
 
 	// initialize AI
 	g.ai = NewAIHandler(g)
+	g.aiDebug = nil
+	if g.debug {
+		g.aiDebug = NewAIDebugOverlay()
+	}
+
+	// initialize player orders to lancemates
+	g.commands = NewLanceCommands()
+
+	// initialize mission script last so it can use everything in the mission
+	g.scripts = nil
+	if len(g.mission.Script) > 0 {
+		scripts, err := NewScriptHandler(g, g.mission.Script)
+		if err != nil {
+			log.Error("Error loading mission script: ", g.mission.Script)
+			log.Error(err)
+		} else {
+			g.scripts = scripts
+		}
+	}
 }
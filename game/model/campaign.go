@@ -0,0 +1,147 @@
+package model
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game/resources"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+)
+
+// CAMPAIGN_END can be used as a mission branch to end the campaign
+const CAMPAIGN_END = "end"
+
+type Campaign struct {
+	Title    string             `yaml:"title" validate:"required"`
+	Briefing string             `yaml:"briefing" validate:"required"`
+	CBills   int                `yaml:"cBills" validate:"gte=0"`
+	Mechs    []string           `yaml:"mechs" validate:"required,min=1"`
+	Missions []*CampaignMission `yaml:"missions" validate:"required,min=1,dive"`
+}
+
+// CampaignMission is a mission in the campaign with the rewards for completing it and which mission follows.
+// When not set, success continues to the next mission in the list and failure retries the same mission.
+type CampaignMission struct {
+	ID        string `yaml:"id" validate:"required"`
+	Mission   string `yaml:"mission" validate:"required"`
+	Reward    int    `yaml:"reward" validate:"gte=0"`
+	Salvage   int    `yaml:"salvage" validate:"gte=0"`
+	OnSuccess string `yaml:"onSuccess"`
+	OnFailure string `yaml:"onFailure"`
+}
+
+func LoadCampaign(campaignFile string) (*Campaign, error) {
+	v := validator.New()
+	campaignPath := path.Join("campaigns", campaignFile)
+
+	campaignYaml, err := resources.ReadFile(campaignPath)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Campaign{}
+	err = yaml.Unmarshal(campaignYaml, c)
+	if err != nil {
+		return nil, err
+	}
+
+	err = v.Struct(c)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %s", campaignPath, err.Error())
+	}
+
+	err = c.validateMissions()
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %s", campaignPath, err.Error())
+	}
+
+	return c, nil
+}
+
+// validateMissions checks mission ids are unique and that mission branches reference known ids
+func (c *Campaign) validateMissions() error {
+	missionFiles, err := ListMissionFilenames()
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]bool, len(c.Missions))
+	for _, m := range c.Missions {
+		if m.ID == CAMPAIGN_END {
+			return fmt.Errorf("mission id '%s' is reserved", CAMPAIGN_END)
+		}
+		if ids[m.ID] {
+			return fmt.Errorf("duplicate mission id '%s'", m.ID)
+		}
+		ids[m.ID] = true
+
+		if !slices.Contains(missionFiles, m.Mission) {
+			return fmt.Errorf("mission '%s' not found: %s", m.ID, m.Mission)
+		}
+	}
+
+	for _, m := range c.Missions {
+		for _, next := range []string{m.OnSuccess, m.OnFailure} {
+			if next != "" && next != CAMPAIGN_END && !ids[next] {
+				return fmt.Errorf("mission '%s' references unknown mission id '%s'", m.ID, next)
+			}
+		}
+	}
+	return nil
+}
+
+// GetMission returns the campaign mission with the given id
+func (c *Campaign) GetMission(id string) *CampaignMission {
+	for _, m := range c.Missions {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+// NextMission returns the id of the mission that follows the result of the given mission,
+// or CAMPAIGN_END if the campaign is over
+func (c *Campaign) NextMission(id string, success bool) string {
+	i := slices.IndexFunc(c.Missions, func(m *CampaignMission) bool { return m.ID == id })
+	if i < 0 {
+		return CAMPAIGN_END
+	}
+
+	m := c.Missions[i]
+	if !success {
+		if m.OnFailure != "" {
+			return m.OnFailure
+		}
+		return m.ID
+	}
+
+	switch {
+	case m.OnSuccess != "":
+		return m.OnSuccess
+	case i+1 < len(c.Missions):
+		return c.Missions[i+1].ID
+	}
+	return CAMPAIGN_END
+}
+
+func ListCampaignFilenames() ([]string, error) {
+	campaignFilenames := make([]string, 0, 16)
+	campaignsPath := "campaigns"
+	campaignFiles, err := resources.ReadDir(campaignsPath, true)
+	if err != nil {
+		return campaignFilenames, err
+	}
+
+	for _, f := range campaignFiles {
+		if f.IsDir() {
+			continue
+		}
+		campaignFilenames = append(campaignFilenames, f.Name())
+	}
+
+	return campaignFilenames, nil
+}
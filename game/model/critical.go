@@ -0,0 +1,162 @@
+package model
+
+import (
+	"fmt"
+)
+
+type CriticalHitType int
+
+const (
+	CRITICAL_WEAPON_DESTROYED CriticalHitType = iota
+	CRITICAL_AMMO_EXPLOSION
+	CRITICAL_HEAT_SINK
+	CRITICAL_JUMP_JET
+)
+
+func (t CriticalHitType) String() string {
+	switch t {
+	case CRITICAL_WEAPON_DESTROYED:
+		return "weapon destroyed"
+	case CRITICAL_AMMO_EXPLOSION:
+		return "ammo explosion"
+	case CRITICAL_HEAT_SINK:
+		return "heat sink destroyed"
+	case CRITICAL_JUMP_JET:
+		return "jump jet destroyed"
+	}
+	return "unknown"
+}
+
+// CriticalHit describes the result of a single critical hit on a unit
+type CriticalHit struct {
+	Type     CriticalHitType
+	Location Location
+	Weapon   Weapon
+	AmmoBin  *AmmoBin
+	Damage   float64
+}
+
+func (c *CriticalHit) String() string {
+	switch c.Type {
+	case CRITICAL_WEAPON_DESTROYED:
+		return fmt.Sprintf("%s (%s) %s", c.Location.ShortName(), c.Weapon.ShortName(), c.Type)
+	case CRITICAL_AMMO_EXPLOSION:
+		return fmt.Sprintf("%s (%s) %s for %0.1f", c.Location.ShortName(), c.AmmoBin.AmmoType().ShortName(), c.Type, c.Damage)
+	}
+	return fmt.Sprintf("%s %s", c.Location.ShortName(), c.Type)
+}
+
+// criticalSlot is a mounted item that can be chosen to receive a critical hit
+type criticalSlot struct {
+	hitType CriticalHitType
+	weapon  Weapon
+	ammoBin *AmmoBin
+}
+
+// locations which can have heat sinks and jump jets mounted, by unit type
+var (
+	heatSinkLocations = map[UnitType][]Location{
+		MechUnitType:    {CENTER_TORSO, LEFT_TORSO, RIGHT_TORSO, LEFT_LEG, RIGHT_LEG},
+		VehicleUnitType: {FRONT, LEFT, RIGHT, REAR},
+		VTOLUnitType:    {FRONT, LEFT, RIGHT, REAR},
+	}
+	jumpJetLocations = map[UnitType][]Location{
+		MechUnitType: {CENTER_TORSO, LEFT_TORSO, RIGHT_TORSO, LEFT_LEG, RIGHT_LEG},
+	}
+)
+
+// CriticalHitCount rolls 2D6 to determine the number of critical hits from structure damage
+func CriticalHitCount(rng *Rand) int {
+	roll := rng.Intn(6) + rng.Intn(6) + 2
+	switch {
+	case roll >= 12:
+		return 3
+	case roll >= 10:
+		return 2
+	case roll >= 8:
+		return 1
+	}
+	return 0
+}
+
+// ApplyCriticalHits rolls for critical hits against items mounted in the location after
+// it has taken structure damage, applying and returning the results of each critical hit
+func (e *UnitModel) ApplyCriticalHits(location Location, rng *Rand) []*CriticalHit {
+	l := e.GetLocation(location)
+	if l == nil || rng == nil || e.IsDestroyed() {
+		return nil
+	}
+
+	numCrits := CriticalHitCount(rng)
+	if numCrits == 0 {
+		return nil
+	}
+
+	hits := make([]*CriticalHit, 0, numCrits)
+	for i := 0; i < numCrits; i++ {
+		slots := e.criticalSlots(location)
+		if len(slots) == 0 {
+			break
+		}
+
+		// each mounted item in the location is equally likely to be hit
+		slot := slots[rng.Intn(len(slots))]
+		hit := &CriticalHit{Type: slot.hitType, Location: location}
+
+		switch slot.hitType {
+		case CRITICAL_WEAPON_DESTROYED:
+			hit.Weapon = slot.weapon
+			slot.weapon.SetDestroyed(true)
+
+		case CRITICAL_AMMO_EXPLOSION:
+			hit.AmmoBin = slot.ammoBin
+			hit.Damage = slot.ammoBin.ExplosionDamage()
+			slot.ammoBin.ammoCount = 0
+
+			// ammo explosions deal damage directly to internal structure
+			e.applyLocationDamage(hit.Damage, location, true)
+
+		case CRITICAL_HEAT_SINK:
+			e.SetHeatSinks(e.heatSinks - 1)
+
+		case CRITICAL_JUMP_JET:
+			e.jumpJets--
+		}
+
+		hits = append(hits, hit)
+
+		if e.IsDestroyed() || l.IsDestroyed() {
+			break
+		}
+	}
+
+	return hits
+}
+
+// criticalSlots returns the items mounted in the location which can still take a critical hit
+func (e *UnitModel) criticalSlots(location Location) []*criticalSlot {
+	slots := make([]*criticalSlot, 0, 8)
+	for _, w := range e.armament {
+		if w.Location() == location && !w.Destroyed() {
+			slots = append(slots, &criticalSlot{hitType: CRITICAL_WEAPON_DESTROYED, weapon: w})
+		}
+	}
+
+	if e.ammunition != nil {
+		for _, ammoBin := range e.ammunition.AmmoBinList() {
+			if ammoBin.Location() == location && ammoBin.AmmoCount() > 0 {
+				slots = append(slots, &criticalSlot{hitType: CRITICAL_AMMO_EXPLOSION, ammoBin: ammoBin})
+			}
+		}
+	}
+
+	if e.heatSinks > 0 && InArray(heatSinkLocations[e.unitType], location) {
+		slots = append(slots, &criticalSlot{hitType: CRITICAL_HEAT_SINK})
+	}
+
+	if e.jumpJets > 0 && InArray(jumpJetLocations[e.unitType], location) {
+		slots = append(slots, &criticalSlot{hitType: CRITICAL_JUMP_JET})
+	}
+
+	return slots
+}
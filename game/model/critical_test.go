@@ -0,0 +1,245 @@
+package model
+
+import (
+	"testing"
+)
+
+func newCriticalTestUnit() *UnitModel {
+	u := &UnitModel{unitType: MechUnitType, heatSinkType: SINGLE, ammunition: NewAmmoStock()}
+	for _, l := range UnitTypeLocations(MechUnitType) {
+		u.locations = append(u.locations, NewUnitLocation(l, 10, 10))
+		u.armor += 10
+		u.structure += 10
+	}
+	return u
+}
+
+func newCriticalTestLaser(location Location) *EnergyWeapon {
+	return &EnergyWeapon{
+		Resource: &ModelEnergyWeaponResource{File: "test_laser", ProjectileCount: 1},
+		location: location,
+		damage:   5,
+	}
+}
+
+func newCriticalTestAutocannon(location Location) *BallisticWeapon {
+	return &BallisticWeapon{
+		Resource: &ModelBallisticWeaponResource{File: "test_autocannon", AmmoPerTon: 2, ProjectileCount: 1},
+		location: location,
+		damage:   7,
+	}
+}
+
+// criticalTestSeed returns the first seed that rolls at least the number of critical hits
+func criticalTestSeed(t *testing.T, minCrits int) int64 {
+	t.Helper()
+	for seed := int64(0); seed < 1000; seed++ {
+		if CriticalHitCount(NewSeededRNG(seed)) >= minCrits {
+			return seed
+		}
+	}
+	t.Fatalf("no seed found rolling at least %d critical hits", minCrits)
+	return 0
+}
+
+func TestCriticalHitCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		minRoll int
+		maxRoll int
+		crits   int
+	}{
+		{"no crits", 2, 7, 0},
+		{"one crit", 8, 9, 1},
+		{"two crits", 10, 11, 2},
+		{"three crits", 12, 12, 3},
+	}
+
+	rolled := make(map[string]bool, len(tests))
+	for seed := int64(0); seed < 1000; seed++ {
+		// same seed rolls the same 2D6 as the critical hit count does
+		dice := NewSeededRNG(seed)
+		roll := dice.Intn(6) + dice.Intn(6) + 2
+
+		crits := CriticalHitCount(NewSeededRNG(seed))
+		for _, tt := range tests {
+			if roll < tt.minRoll || roll > tt.maxRoll {
+				continue
+			}
+			rolled[tt.name] = true
+			if crits != tt.crits {
+				t.Errorf("%s: seed %d rolled %d, got %d crits, want %d", tt.name, seed, roll, crits, tt.crits)
+			}
+		}
+	}
+
+	for _, tt := range tests {
+		if !rolled[tt.name] {
+			t.Errorf("%s: no seed rolled between %d and %d", tt.name, tt.minRoll, tt.maxRoll)
+		}
+	}
+}
+
+func TestApplyCriticalHitsSlotSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		location Location
+		mounted  []Location
+	}{
+		{"single weapon", LEFT_ARM, []Location{LEFT_ARM, RIGHT_ARM}},
+		{"several weapons", LEFT_ARM, []Location{LEFT_ARM, LEFT_ARM, LEFT_ARM, RIGHT_ARM}},
+		{"no weapons", HEAD, []Location{LEFT_ARM, RIGHT_ARM}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(0); seed < 100; seed++ {
+				u := newCriticalTestUnit()
+				for _, l := range tt.mounted {
+					u.armament = append(u.armament, newCriticalTestLaser(l))
+				}
+
+				// the first hit is chosen from all slots in the location with the roll after the crit count
+				slots := u.criticalSlots(tt.location)
+				dice := NewSeededRNG(seed)
+				numCrits := CriticalHitCount(dice)
+
+				hits := u.ApplyCriticalHits(tt.location, NewSeededRNG(seed))
+				if len(slots) == 0 || numCrits == 0 {
+					if len(hits) != 0 {
+						t.Fatalf("seed %d: got %d hits, want none", seed, len(hits))
+					}
+					continue
+				}
+				if want := min(numCrits, len(slots)); len(hits) != want {
+					t.Fatalf("seed %d: got %d hits, want %d", seed, len(hits), want)
+				}
+				if want := slots[dice.Intn(len(slots))].weapon; hits[0].Weapon != want {
+					t.Errorf("seed %d: first hit on wrong weapon slot", seed)
+				}
+
+				hitWeapons := make(map[Weapon]bool, len(hits))
+				for _, hit := range hits {
+					if hit.Type != CRITICAL_WEAPON_DESTROYED || hit.Location != tt.location {
+						t.Errorf("seed %d: got %s, want weapon destroyed in location %v", seed, hit, tt.location)
+					}
+					if hitWeapons[hit.Weapon] {
+						t.Errorf("seed %d: destroyed weapon hit again", seed)
+					}
+					hitWeapons[hit.Weapon] = true
+				}
+				for _, w := range u.armament {
+					if w.Destroyed() != hitWeapons[w] {
+						t.Errorf("seed %d: weapon in %v destroyed %v, hit %v", seed, w.Location(), w.Destroyed(), hitWeapons[w])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestApplyCriticalHitsAmmoExplosion(t *testing.T) {
+	tests := []struct {
+		name      string
+		ammoTons  float64
+		structure map[Location]float64
+	}{
+		// 1 ammo for 7 damage
+		{"contained", 0.5, map[Location]float64{RIGHT_ARM: 3, RIGHT_TORSO: 10, CENTER_TORSO: 10}},
+		// 4 ammo for 7 damage each, 18 more than the arm structure
+		{"transferred", 2, map[Location]float64{RIGHT_ARM: 0, RIGHT_TORSO: 0, CENTER_TORSO: 2}},
+	}
+
+	seed := criticalTestSeed(t, 1)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newCriticalTestUnit()
+			w := newCriticalTestAutocannon(RIGHT_ARM)
+			ammoBin := u.ammunition.AddAmmoBin(AMMO_BALLISTIC, tt.ammoTons, w)
+			u.armament = append(u.armament, w)
+
+			// only the ammo bin is left to take a critical hit
+			w.SetDestroyed(true)
+			wantDamage := ammoBin.ExplosionDamage()
+			wantStructure := u.StructurePoints() - wantDamage
+
+			hits := u.ApplyCriticalHits(RIGHT_ARM, NewSeededRNG(seed))
+			if len(hits) != 1 {
+				t.Fatalf("got %d hits, want 1", len(hits))
+			}
+			hit := hits[0]
+			if hit.Type != CRITICAL_AMMO_EXPLOSION || hit.AmmoBin != ammoBin || hit.Damage != wantDamage {
+				t.Errorf("got %s, want ammo explosion for %0.1f", hit, wantDamage)
+			}
+			if ammoBin.AmmoCount() != 0 {
+				t.Errorf("ammo remaining after explosion: %d", ammoBin.AmmoCount())
+			}
+
+			for location, want := range tt.structure {
+				l := u.GetLocation(location)
+				if l.StructurePoints() != want {
+					t.Errorf("%v structure %0.1f, want %0.1f", location, l.StructurePoints(), want)
+				}
+				// explosion damage goes directly to internal structure
+				if l.ArmorPoints() != l.MaxArmorPoints() {
+					t.Errorf("%v armor %0.1f, want %0.1f", location, l.ArmorPoints(), l.MaxArmorPoints())
+				}
+			}
+			if u.StructurePoints() != wantStructure {
+				t.Errorf("unit structure %0.1f, want %0.1f", u.StructurePoints(), wantStructure)
+			}
+		})
+	}
+}
+
+func TestApplyCriticalHitsHeatSinksJumpJets(t *testing.T) {
+	tests := []struct {
+		name      string
+		location  Location
+		heatSinks int
+		jumpJets  int
+		wantHits  int
+	}{
+		{"heat sinks", CENTER_TORSO, 10, 0, 3},
+		{"jump jets", LEFT_LEG, 0, 4, 3},
+		{"last heat sink", LEFT_TORSO, 1, 0, 1},
+		{"last jump jet", RIGHT_LEG, 0, 1, 1},
+		{"not mounted in location", LEFT_ARM, 10, 4, 0},
+	}
+
+	seed := criticalTestSeed(t, 3)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newCriticalTestUnit()
+			u.SetHeatSinks(tt.heatSinks)
+			u.jumpJets = tt.jumpJets
+
+			hits := u.ApplyCriticalHits(tt.location, NewSeededRNG(seed))
+			if len(hits) != tt.wantHits {
+				t.Fatalf("got %d hits, want %d", len(hits), tt.wantHits)
+			}
+
+			heatSinksLost, jumpJetsLost := 0, 0
+			for _, hit := range hits {
+				switch hit.Type {
+				case CRITICAL_HEAT_SINK:
+					heatSinksLost++
+				case CRITICAL_JUMP_JET:
+					jumpJetsLost++
+				default:
+					t.Errorf("got %s, want heat sink or jump jet", hit)
+				}
+			}
+
+			want := newCriticalTestUnit()
+			want.SetHeatSinks(tt.heatSinks - heatSinksLost)
+			if u.HeatSinks() != want.HeatSinks() || u.HeatDissipation() != want.HeatDissipation() {
+				t.Errorf("heat sinks %d dissipating %f, want %d dissipating %f",
+					u.HeatSinks(), u.HeatDissipation(), want.HeatSinks(), want.HeatDissipation())
+			}
+			if u.JumpJets() != tt.jumpJets-jumpJetsLost {
+				t.Errorf("jump jets %d, want %d", u.JumpJets(), tt.jumpJets-jumpJetsLost)
+			}
+		})
+	}
+}
@@ -22,18 +22,20 @@ func NewEmplacement(r *ModelEmplacementResource) *Emplacement {
 	m := &Emplacement{
 		Resource: r,
 		UnitModel: &UnitModel{
-			name:          r.Name,
-			variant:       r.Variant,
-			unitType:      EmplacementUnitType,
-			anchor:        raycaster.AnchorBottom,
-			armor:         r.Armor,
-			structure:     r.Structure,
-			armament:      make([]Weapon, 0),
-			ammunition:    NewAmmoStock(),
-			maxVelocity:   0,
-			maxTurnRate:   EMPLACEMENT_TURRET_RATE_FACTOR,
-			maxTurretRate: EMPLACEMENT_TURRET_RATE_FACTOR,
-			powered:       POWER_ON,
+			name:           r.Name,
+			variant:        r.Variant,
+			destroyEffects: r.DestroyEffects,
+			unitType:       EmplacementUnitType,
+			anchor:         raycaster.AnchorBottom,
+			armor:          r.Armor,
+			structure:      r.Structure,
+			armament:       make([]Weapon, 0),
+			ammunition:     NewAmmoStock(),
+			maxVelocity:    0,
+			maxTurnRate:    EMPLACEMENT_TURRET_RATE_FACTOR,
+			maxTurretRate:  EMPLACEMENT_TURRET_RATE_FACTOR,
+			powered:        POWER_ON,
+			sensorRange:    sensorRangeUnits(r.SensorRange),
 		},
 	}
 
@@ -100,7 +102,7 @@ func (e *Emplacement) HeatDissipation() float64 {
 }
 
 func (e *Emplacement) TriggerWeapon(w Weapon) bool {
-	if w.Cooldown() > 0 {
+	if w.Destroyed() || w.Cooldown() > 0 {
 		return false
 	}
 	w.TriggerCooldown()
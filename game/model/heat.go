@@ -0,0 +1,73 @@
+package model
+
+// HeatScaleBand is a range of the heat scale and the penalties applied to a unit with heat in it
+type HeatScaleBand struct {
+	// Threshold is the fraction of max heat the band begins at
+	Threshold float64
+	// VelocityPenalty is the fraction of max velocity lost
+	VelocityPenalty float64
+	// AimJitter is the max random offset in radians applied to weapon convergence
+	AimJitter float64
+	// ShutdownChance is the chance per second of an automatic shutdown that the unit fails to avoid
+	ShutdownChance float64
+	// AmmoExplosionChance is the chance per second of stored ammo cooking off
+	AmmoExplosionChance float64
+}
+
+// HeatScale is the graduated heat scale for mechs, ordered from the lowest to highest band
+var HeatScale = []HeatScaleBand{
+	{Threshold: 0.3, VelocityPenalty: 0.1},
+	{Threshold: 0.5, VelocityPenalty: 0.2, AimJitter: 0.01},
+	{Threshold: 0.7, VelocityPenalty: 0.3, AimJitter: 0.02, ShutdownChance: 0.1},
+	{Threshold: 0.85, VelocityPenalty: 0.4, AimJitter: 0.03, ShutdownChance: 0.2, AmmoExplosionChance: 0.1},
+}
+
+// HeatScaleBand returns the highest band of the heat scale the unit heat has reached, or nil if none
+func (e *UnitModel) HeatScaleBand() *HeatScaleBand {
+	if e.unitType != MechUnitType {
+		// only mechs use the heat scale, other units just shutdown when overheated
+		return nil
+	}
+
+	heatRatio := e.heat / e.MaxHeat()
+	var band *HeatScaleBand
+	for i := range HeatScale {
+		if heatRatio < HeatScale[i].Threshold {
+			break
+		}
+		band = &HeatScale[i]
+	}
+	return band
+}
+
+// CookOffAmmo explodes a random ammo bin from heat, dealing its damage to internal structure,
+// returning the resulting critical hit or nil if there is no ammo left to explode
+func (e *UnitModel) CookOffAmmo(rng *Rand) *CriticalHit {
+	if e.ammunition == nil || rng == nil || e.IsDestroyed() {
+		return nil
+	}
+
+	ammoBins := make([]*AmmoBin, 0, len(e.ammunition.AmmoBinList()))
+	for _, ammoBin := range e.ammunition.AmmoBinList() {
+		if ammoBin.AmmoCount() > 0 && ammoBin.ExplosionDamage() > 0 {
+			ammoBins = append(ammoBins, ammoBin)
+		}
+	}
+	if len(ammoBins) == 0 {
+		return nil
+	}
+
+	ammoBin := ammoBins[rng.Intn(len(ammoBins))]
+	hit := &CriticalHit{
+		Type:     CRITICAL_AMMO_EXPLOSION,
+		Location: ammoBin.Location(),
+		AmmoBin:  ammoBin,
+		Damage:   ammoBin.ExplosionDamage(),
+	}
+	ammoBin.ammoCount = 0
+
+	// ammo explosions deal damage directly to internal structure
+	e.applyLocationDamage(hit.Damage, hit.Location, true)
+
+	return hit
+}
@@ -25,6 +25,7 @@ func NewInfantry(r *ModelInfantryResource) *Infantry {
 		UnitModel: &UnitModel{
 			name:               r.Name,
 			variant:            r.Variant,
+			destroyEffects:     r.DestroyEffects,
 			unitType:           InfantryUnitType,
 			anchor:             raycaster.AnchorBottom,
 			armor:              r.Armor,
@@ -37,6 +38,7 @@ func NewInfantry(r *ModelInfantryResource) *Infantry {
 			jumpJets:           r.JumpJets,
 			maxJumpJetDuration: 1.0,
 			powered:            POWER_ON,
+			sensorRange:        sensorRangeUnits(r.SensorRange),
 		},
 	}
 
@@ -103,7 +105,7 @@ func (e *Infantry) HeatDissipation() float64 {
 }
 
 func (e *Infantry) TriggerWeapon(w Weapon) bool {
-	if w.Cooldown() > 0 {
+	if w.Destroyed() || w.Cooldown() > 0 {
 		return false
 	}
 	w.TriggerCooldown()
@@ -0,0 +1,226 @@
+package model
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/harbdog/raycaster-go/geom"
+)
+
+const (
+	FORMATION_LINE   = "line"
+	FORMATION_WEDGE  = "wedge"
+	FORMATION_COLUMN = "column"
+
+	// default distance in meters between units in formation
+	FORMATION_SPACING_METERS float64 = 60
+)
+
+// MissionLance is a group of units, such as a lance, star, or company, placed and moving together in formation
+// behind a leader, sharing orders to patrol or guard an area, and to power on
+type MissionLance struct {
+	Name      string     `yaml:"name" validate:"required"`
+	Team      int        `yaml:"team"`
+	Formation string     `yaml:"formation,omitempty" validate:"omitempty,oneof=line wedge column"`
+	Spacing   float64    `yaml:"spacing,omitempty" validate:"gte=0"`
+	Leader    string     `yaml:"leader,omitempty"`
+	Position  [2]float64 `yaml:"position" validate:"required"`
+	Heading   float64    `yaml:"heading"`
+
+	PatrolPath      [][2]float64        `yaml:"patrolPath"`
+	GuardArea       MissionGuardArea    `yaml:"guardArea"`
+	PowerConditions UnitPowerConditions `yaml:"powerConditions"`
+
+	Mechs    []MissionUnit `yaml:"mechs"`
+	Vehicles []MissionUnit `yaml:"vehicles"`
+	Infantry []MissionUnit `yaml:"infantry"`
+}
+
+// Units returns all member units of the lance, in formation order when the leader is the first
+func (l *MissionLance) Units() []*MissionUnit {
+	units := make([]*MissionUnit, 0, len(l.Mechs)+len(l.Vehicles)+len(l.Infantry))
+	for _, members := range [][]MissionUnit{l.Mechs, l.Vehicles, l.Infantry} {
+		for i := range members {
+			units = append(units, &members[i])
+		}
+	}
+	return units
+}
+
+// FormationSlots returns the formation slot of each member unit by ID, where the leader is slot 0
+func (l *MissionLance) FormationSlots() map[string]int {
+	slots := make(map[string]int)
+	slot := 1
+	for _, u := range l.Units() {
+		if u.ID == l.Leader {
+			slots[u.ID] = 0
+			continue
+		}
+		slots[u.ID] = slot
+		slot++
+	}
+	return slots
+}
+
+// SpacingUnits returns the distance in units between members in formation
+func (l *MissionLance) SpacingUnits() float64 {
+	if l.Spacing <= 0 {
+		return FORMATION_SPACING_METERS / METERS_PER_UNIT
+	}
+	return l.Spacing / METERS_PER_UNIT
+}
+
+// FormationSlotPosition returns the position of a formation slot relative to the leader position and heading,
+// with slots alternating to the right and left of the leader
+func FormationSlotPosition(formation string, slot int, spacing float64, leaderPos geom.Vector2, leaderHeading float64) geom.Vector2 {
+	if slot <= 0 {
+		return leaderPos
+	}
+
+	rank := float64((slot + 1) / 2)
+	side := 1.0
+	if slot%2 == 0 {
+		side = -1.0
+	}
+
+	var lateral, behind float64
+	switch formation {
+	case FORMATION_LINE:
+		lateral = side * rank * spacing
+	case FORMATION_COLUMN:
+		behind = float64(slot) * spacing
+	default:
+		lateral, behind = side*rank*spacing, rank*spacing
+	}
+
+	// right is a quarter turn clockwise from the leader heading, behind is opposite of it
+	rightX, rightY := math.Cos(leaderHeading-geom.HalfPi), math.Sin(leaderHeading-geom.HalfPi)
+	backX, backY := -math.Cos(leaderHeading), -math.Sin(leaderHeading)
+	return geom.Vector2{
+		X: leaderPos.X + lateral*rightX + behind*backX,
+		Y: leaderPos.Y + lateral*rightY + behind*backY,
+	}
+}
+
+// initLances applies lance wide settings to each lance member, assigning IDs to members without one so they
+// can be followed by formation, and placing members without a position in formation around the lance position
+func (m *Mission) initLances() error {
+	for _, lance := range m.Lances {
+		members := lance.Units()
+		if len(members) == 0 {
+			return fmt.Errorf("lance '%s' has no units", lance.Name)
+		}
+
+		for i, u := range members {
+			if len(u.ID) == 0 {
+				u.ID = fmt.Sprintf("%s_%d", lance.Name, i+1)
+			}
+		}
+
+		if len(lance.Leader) == 0 {
+			lance.Leader = members[0].ID
+		}
+		if len(lance.Formation) == 0 {
+			lance.Formation = FORMATION_WEDGE
+		}
+
+		hasLeader := false
+		for _, u := range members {
+			if u.ID == lance.Leader {
+				hasLeader = true
+				break
+			}
+		}
+		if !hasLeader {
+			return fmt.Errorf("lance '%s' leader '%s' is not one of its units", lance.Name, lance.Leader)
+		}
+
+		slots := lance.FormationSlots()
+		lancePos := geom.Vector2{X: lance.Position[0], Y: lance.Position[1]}
+		lanceHeading := geom.Radians(lance.Heading)
+		for _, u := range members {
+			u.Team = lance.Team
+			if u.Position == [2]float64{0, 0} {
+				slotPos := FormationSlotPosition(lance.Formation, slots[u.ID], lance.SpacingUnits(), lancePos, lanceHeading)
+				u.Position = [2]float64{slotPos.X, slotPos.Y}
+				u.Heading = lance.Heading
+			}
+			if u.PowerConditions == (UnitPowerConditions{}) {
+				u.PowerConditions = lance.PowerConditions
+			}
+
+			if u.ID == lance.Leader {
+				// the leader carries out the lance orders to patrol or guard
+				u.PatrolPath = lance.PatrolPath
+				u.GuardArea = lance.GuardArea
+				u.GuardUnit = ""
+			} else {
+				// other members follow the leader in formation
+				u.PatrolPath = nil
+				u.GuardArea = MissionGuardArea{}
+				u.GuardUnit = lance.Leader
+			}
+		}
+	}
+	return nil
+}
+
+// Lance returns the lance the unit ID is a member of, or nil if it is not in a lance
+func (m *Mission) Lance(id string) *MissionLance {
+	for _, lance := range m.Lances {
+		for _, u := range lance.Units() {
+			if u.ID == id {
+				return lance
+			}
+		}
+	}
+	return nil
+}
+
+// initLancemates assigns IDs to player lancemates without one so they can be given orders, and places
+// lancemates without a position in formation behind the player at the drop zone
+func (m *Mission) initLancemates() {
+	if m.DropZone == nil {
+		return
+	}
+
+	dzPos := geom.Vector2{X: m.DropZone.Position[0], Y: m.DropZone.Position[1]}
+	dzHeading := CardinalToAngle(m.DropZone.Heading)
+	for i := range m.Lancemates {
+		u := &m.Lancemates[i]
+		if len(u.ID) == 0 {
+			u.ID = fmt.Sprintf("lancemate_%d", i+1)
+		}
+		// lancemates are always on the player team
+		u.Team = -1
+		if u.Position == [2]float64{0, 0} {
+			slotPos := FormationSlotPosition(FORMATION_WEDGE, i+1, FORMATION_SPACING_METERS/METERS_PER_UNIT, dzPos, dzHeading)
+			u.Position = [2]float64{slotPos.X, slotPos.Y}
+			u.Heading = geom.Degrees(dzHeading)
+		}
+	}
+}
+
+// AddLancemates adds player lancemates of the mech units, placed in formation behind the player at the drop zone
+func (m *Mission) AddLancemates(mechs ...string) {
+	for _, mech := range mechs {
+		m.Lancemates = append(m.Lancemates, MissionUnit{Unit: mech})
+		m.addedLancemates = append(m.addedLancemates, mech)
+	}
+	m.initLancemates()
+}
+
+// AddedLancemates returns the mech units of lancemates added to the mission instead of loaded from the mission file
+func (m *Mission) AddedLancemates() []string {
+	return m.addedLancemates
+}
+
+// IsLancemate returns true if the unit ID is one of the player lancemates
+func (m *Mission) IsLancemate(id string) bool {
+	for _, u := range m.Lancemates {
+		if u.ID == id {
+			return true
+		}
+	}
+	return false
+}
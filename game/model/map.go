@@ -89,18 +89,27 @@ type Map struct {
 	Clutter          []MapClutter       `yaml:"clutter"`
 	Sprites          []MapSprite        `yaml:"sprites"`
 	SpriteFill       []MapSpriteFill    `yaml:"spriteFill"`
-	SpriteStamps     []MapSpriteStamp   `yaml:"spriteStamps"`
+	SpriteStamps     []MapSpriteStamp   `yaml:"spriteStamps,omitempty"`
+	PathingCosts     [][]float64        `yaml:"pathingCosts,omitempty"`
+	Ramps            []MapRamp          `yaml:"ramps,omitempty" validate:"dive"`
 	Seed             int64              `yaml:"seed"`
 	MusicPath        string             `yaml:"music"`
 
 	// Sprite ID mapping is initialized when map data is being loaded
 	spritesByID map[string]MapSprite `yaml:"-"`
+
+	// Terrain elevation is initialized after map levels are loaded
+	elevation    [][]float64 `yaml:"-"`
+	walkable     [][]bool    `yaml:"-"`
+	hasElevation bool        `yaml:"-"`
 }
 
 type MapTexture struct {
 	Image string `yaml:"image"`
-	SideX string `yaml:"sideX"`
-	SideY string `yaml:"sideY"`
+	SideX string `yaml:"sideX,omitempty"`
+	SideY string `yaml:"sideY,omitempty"`
+	// Walkable walls are elevated platforms that units can stand on top of
+	Walkable bool `yaml:"walkable,omitempty"`
 }
 
 func (m MapTexture) GetImage(side int) string {
@@ -116,13 +125,17 @@ func (m MapTexture) GetImage(side int) string {
 
 type MapFlooring struct {
 	Default string            `yaml:"default"`
-	Pathing []MapFloorPathing `yaml:"pathing"`
+	Pathing []MapFloorPathing `yaml:"pathing" validate:"dive"`
+	// Cost is the extra AI movement cost of the default flooring, if not set it costs more than flooring paths
+	Cost *float64 `yaml:"cost,omitempty" validate:"omitempty,gte=0"`
 }
 
 type MapFloorPathing struct {
 	Image string      `yaml:"image"`
 	Rects [][2][2]int `yaml:"rects"`
 	Lines [][][2]int  `yaml:"lines"`
+	// Cost is the extra AI movement cost of the flooring path, such as for rough terrain or water
+	Cost float64 `yaml:"cost,omitempty" validate:"gte=0"`
 }
 
 type MapClutter struct {
@@ -156,6 +169,18 @@ func (r *SpriteAnchor) UnmarshalText(b []byte) error {
 	return nil
 }
 
+// Marshals from raycaster.SpriteAnchor
+func (r SpriteAnchor) MarshalText() ([]byte, error) {
+	switch r.SpriteAnchor {
+	case raycaster.AnchorTop:
+		return []byte("top"), nil
+	case raycaster.AnchorCenter:
+		return []byte("center"), nil
+	default:
+		return []byte("bottom"), nil
+	}
+}
+
 type RegExp struct {
 	*regexp.Regexp
 }
@@ -175,13 +200,13 @@ type MapSprite struct {
 	ID                string       `yaml:"id"`
 	Image             string       `yaml:"image"`
 	Positions         [][2]float64 `yaml:"positions"`
-	ZPosition         float64      `yaml:"zPosition"`
+	ZPosition         float64      `yaml:"zPosition,omitempty"`
 	CollisionPxRadius int          `yaml:"collisionRadiusPx"`
 	CollisionPxHeight int          `yaml:"collisionHeightPx"`
 	HitPoints         float64      `yaml:"hitPoints"`
 	Height            float64      `yaml:"height"`
 	Anchor            SpriteAnchor `yaml:"anchor"`
-	Stamp             string       `yaml:"stamp"`
+	Stamp             string       `yaml:"stamp,omitempty"`
 }
 
 type MapSpriteFill struct {
@@ -223,7 +248,7 @@ type MapGenerateLevels struct {
 	MapSize      [2]int               `yaml:"mapSize"`
 	BoundaryWall MapTexture           `yaml:"boundaryWall"`
 	Prefabs      []MapGeneratePrefabs `yaml:"prefabs"`
-	Walls        []MapGenerateWalls   `yaml:"walls"`
+	Walls        []MapGenerateWalls   `yaml:"walls,omitempty"`
 }
 
 func (m MapGenerateLevels) HasBoundaryWall() bool {
@@ -268,7 +293,6 @@ func LoadMap(mapFile string) (*Map, error) {
 		mapFile += YAMLExtension
 	}
 
-	v := validator.New()
 	mapPath := path.Join("maps", mapFile)
 
 	mapYaml, err := resources.ReadFile(mapPath)
@@ -276,8 +300,15 @@ func LoadMap(mapFile string) (*Map, error) {
 		return nil, err
 	}
 
+	return parseMap(mapYaml, mapPath)
+}
+
+// parseMap unmarshals and validates map data, then generates the map levels and sprites from it
+func parseMap(mapYaml []byte, mapPath string) (*Map, error) {
+	v := validator.New()
+
 	m := &Map{}
-	err = yaml.Unmarshal(mapYaml, m)
+	err := yaml.Unmarshal(mapYaml, m)
 	if err != nil {
 		return nil, err
 	}
@@ -312,6 +343,24 @@ func LoadMap(mapFile string) (*Map, error) {
 		m.NumRaycastLevels = len(m.Levels)
 	}
 
+	// initialize terrain elevation from walkable walls and ramps
+	if err := m.initElevation(); err != nil {
+		return m, fmt.Errorf("[%s] %s", mapPath, err.Error())
+	}
+
+	// pathing costs override uses the same layout as levels, with negative values to keep the cost from flooring
+	if len(m.PathingCosts) > 0 {
+		width, height := m.Size()
+		if len(m.PathingCosts) != width {
+			return m, fmt.Errorf("[%s] pathingCosts must have the same size as the map (%dx%d)", mapPath, width, height)
+		}
+		for _, column := range m.PathingCosts {
+			if len(column) != height {
+				return m, fmt.Errorf("[%s] pathingCosts must have the same size as the map (%dx%d)", mapPath, width, height)
+			}
+		}
+	}
+
 	// map sprites by ID for use in sprite fill/stamps
 	m.spritesByID = make(map[string]MapSprite, len(m.Sprites))
 	for _, mSprite := range m.Sprites {
@@ -673,7 +722,8 @@ func (g *wallLineGenerator) initializeCellWalls() {
 					continue
 				}
 			}
-			if level[x][y] == 0 {
+			if level[x][y] == 0 || g.m.IsWalkableWall(x, y) {
+				// walkable walls are elevated terrain instead of wall collisions
 				continue
 			}
 			// for each wall cell, create directional line for each of its 4 borders
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"fmt"
+)
+
+const (
+	// maximum height in levels a ground unit can step up without jumping
+	ELEVATION_MAX_STEP float64 = 0.25
+)
+
+// MapRamp is sloped terrain that rises evenly across its cells up to its height, to walk up onto walkable walls
+type MapRamp struct {
+	// Rect is the first and last cells of the ramp, which must not have walls
+	Rect [2][2]int `yaml:"rect"`
+	// Direction is the cardinal direction the ramp rises towards: N, E, S or W
+	Direction string `yaml:"direction" validate:"oneof=N E S W"`
+	// Height is the number of levels the ramp rises to at its highest edge
+	Height float64 `yaml:"height" validate:"gt=0"`
+}
+
+// initElevation sets the height of each cell from the number of levels of walls stacked on it
+func (m *Map) initElevation() error {
+	width, height := m.Size()
+	m.elevation = make([][]float64, width)
+	m.walkable = make([][]bool, width)
+	m.hasElevation = len(m.Ramps) > 0
+
+	for x := range width {
+		m.elevation[x] = make([]float64, height)
+		m.walkable[x] = make([]bool, height)
+		for y := range height {
+			tex := m.Levels[0][x][y]
+			if tex == 0 {
+				continue
+			}
+			m.walkable[x][y] = m.Textures[tex].Walkable
+			if m.walkable[x][y] {
+				m.hasElevation = true
+			}
+
+			levels := 1
+			for levels < m.NumRaycastLevels && m.Level(levels)[x][y] != 0 {
+				levels++
+			}
+			m.elevation[x][y] = float64(levels)
+		}
+	}
+
+	for i, ramp := range m.Ramps {
+		x0, y0, x1, y1 := ramp.Rect[0][0], ramp.Rect[0][1], ramp.Rect[1][0], ramp.Rect[1][1]
+		if x0 > x1 || y0 > y1 || x0 < 0 || y0 < 0 || x1 >= width || y1 >= height {
+			return fmt.Errorf("ramp at index [%d] rect must be first and last cells within the map: %v", i, ramp.Rect)
+		}
+		for x := x0; x <= x1; x++ {
+			for y := y0; y <= y1; y++ {
+				if m.Levels[0][x][y] != 0 {
+					return fmt.Errorf("ramp at index [%d] cannot have a wall in cell [%d, %d]", i, x, y)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// HasElevation returns true if the map has any walkable walls or ramps
+func (m *Map) HasElevation() bool {
+	return m.hasElevation
+}
+
+// CanStepUp returns true if a ground unit standing at the height can move onto the position without jumping
+func (m *Map) CanStepUp(fromZ, x, y float64) bool {
+	return m.HeightAt(x, y) <= fromZ+ELEVATION_MAX_STEP
+}
+
+// IsWalkableWall returns true if the cell has a wall that units can stand on top of
+func (m *Map) IsWalkableWall(x, y int) bool {
+	if x < 0 || y < 0 || x >= len(m.walkable) || y >= len(m.walkable[x]) {
+		return false
+	}
+	return m.walkable[x][y]
+}
+
+// HeightAt returns the height in levels of the terrain or wall at the position
+func (m *Map) HeightAt(x, y float64) float64 {
+	ix, iy := int(x), int(y)
+	if x < 0 || y < 0 || ix >= len(m.elevation) || iy >= len(m.elevation[ix]) {
+		return 0
+	}
+
+	for _, ramp := range m.Ramps {
+		x0, y0, x1, y1 := ramp.Rect[0][0], ramp.Rect[0][1], ramp.Rect[1][0], ramp.Rect[1][1]
+		if ix < x0 || ix > x1 || iy < y0 || iy > y1 {
+			continue
+		}
+
+		// fraction of the distance across the ramp towards its highest edge
+		var rise float64
+		switch ramp.Direction {
+		case NORTH.String():
+			rise = (y - float64(y0)) / float64(y1+1-y0)
+		case EAST.String():
+			rise = (x - float64(x0)) / float64(x1+1-x0)
+		case SOUTH.String():
+			rise = (float64(y1+1) - y) / float64(y1+1-y0)
+		case WEST.String():
+			rise = (float64(x1+1) - x) / float64(x1+1-x0)
+		}
+		return ramp.Height * rise
+	}
+
+	return m.elevation[ix][iy]
+}
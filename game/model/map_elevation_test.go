@@ -0,0 +1,128 @@
+package model
+
+import (
+	"testing"
+)
+
+// newElevationTestMap creates an 8x8 map with a walkable platform one level high across x 5-6,
+// and a ramp rising east up to it along y 2
+func newElevationTestMap(t *testing.T) *Map {
+	t.Helper()
+
+	const size = 8
+	levels := make([][][]int, 2)
+	for i := range levels {
+		levels[i] = make([][]int, size)
+		for x := range size {
+			levels[i][x] = make([]int, size)
+		}
+	}
+	for y := range size {
+		levels[0][5][y] = 1
+		levels[0][6][y] = 1
+	}
+
+	m := &Map{
+		NumRaycastLevels: len(levels),
+		Levels:           levels,
+		Textures:         map[int]MapTexture{1: {Walkable: true}},
+		Ramps:            []MapRamp{{Rect: [2][2]int{{1, 2}, {4, 2}}, Direction: EAST.String(), Height: 1}},
+	}
+	if err := m.initElevation(); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestCanStepUp(t *testing.T) {
+	m := newElevationTestMap(t)
+
+	tests := []struct {
+		name     string
+		from, to [2]float64
+		want     bool
+	}{
+		{"flat ground", [2]float64{0.5, 0.5}, [2]float64{1.5, 0.5}, true},
+		{"onto platform from ground", [2]float64{4.9, 0.5}, [2]float64{5.1, 0.5}, false},
+		{"onto platform from top of ramp", [2]float64{4.9, 2.5}, [2]float64{5.1, 2.5}, true},
+		{"off platform to ground", [2]float64{5.1, 0.5}, [2]float64{4.9, 0.5}, true},
+		{"along platform", [2]float64{5.5, 0.5}, [2]float64{6.5, 0.5}, true},
+		{"onto ramp from ground", [2]float64{0.9, 2.5}, [2]float64{1.1, 2.5}, true},
+		{"onto top of ramp from ground beside it", [2]float64{4.5, 1.5}, [2]float64{4.5, 2.5}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fromZ := m.HeightAt(tt.from[0], tt.from[1])
+			got := m.CanStepUp(fromZ, tt.to[0], tt.to[1])
+			if got != tt.want {
+				t.Errorf("step from %v at %0.2f to %v at %0.2f: got %v, want %v",
+					tt.from, fromZ, tt.to, m.HeightAt(tt.to[0], tt.to[1]), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanStepUpWalk(t *testing.T) {
+	m := newElevationTestMap(t)
+
+	tests := []struct {
+		name       string
+		y          float64
+		wantStopX  float64
+		wantFinalZ float64
+	}{
+		// walking east straight at the platform stops at its edge
+		{"platform", 0.5, 5, 0},
+		// walking east up the ramp reaches the far edge of the platform
+		{"ramp", 2.5, 7, 1},
+	}
+
+	const step = 0.1
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, z := 0.5, m.HeightAt(0.5, tt.y)
+			for x+step < 7 && m.CanStepUp(z, x+step, tt.y) {
+				x += step
+				z = m.HeightAt(x, tt.y)
+			}
+
+			if x > tt.wantStopX || x < tt.wantStopX-step {
+				t.Errorf("stopped at x %0.2f, want just before %0.2f", x, tt.wantStopX)
+			}
+			if z != tt.wantFinalZ {
+				t.Errorf("stopped at height %0.2f, want %0.2f", z, tt.wantFinalZ)
+			}
+		})
+	}
+}
+
+func TestHasElevation(t *testing.T) {
+	tests := []struct {
+		name     string
+		walkable bool
+		ramps    []MapRamp
+		want     bool
+	}{
+		{"flat", false, nil, false},
+		{"walkable wall", true, nil, true},
+		{"ramp", false, []MapRamp{{Rect: [2][2]int{{0, 0}, {0, 1}}, Direction: NORTH.String(), Height: 1}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Map{
+				NumRaycastLevels: 1,
+				Levels:           [][][]int{{{0, 0}, {1, 0}}},
+				Textures:         map[int]MapTexture{1: {Walkable: tt.walkable}},
+				Ramps:            tt.ramps,
+			}
+			if err := m.initElevation(); err != nil {
+				t.Fatal(err)
+			}
+			if got := m.HasElevation(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
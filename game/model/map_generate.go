@@ -0,0 +1,621 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/harbdog/raycaster-go/geom"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	MAP_GENERATE_MIN_SIZE int = 50
+	MAP_GENERATE_MAX_SIZE int = 1000
+
+	// number of elevation levels wall clusters can be stacked to
+	MAP_GENERATE_LEVELS int = 2
+	// number of enemy spawn points placed around the map center
+	MAP_GENERATE_SPAWN_POINTS int = 4
+	// half size of the flooring pads under the drop zone and spawn points
+	MAP_GENERATE_PAD_SIZE int = 3
+)
+
+// MapGenerateOptions are the options used to procedurally generate a map, the same options always generate the same map
+type MapGenerateOptions struct {
+	Seed   int64
+	Width  int
+	Height int
+	Biome  string
+}
+
+type mapBiome struct {
+	name     string
+	floor    string
+	pathing  string
+	sky      string
+	boundary string
+	music    string
+	walls    []MapTexture
+	lighting MapLighting
+	clutter  []MapClutter
+
+	// fraction of map cells covered by wall clusters
+	wallDensity float64
+
+	// sprite IDs of trees used in forests
+	trees      []string
+	treeHeight [2]float64
+	// number of forests per square kilometer, and trees per cell within each forest
+	forestDensity float64
+	treeDensity   float64
+}
+
+var mapBiomes = map[string]*mapBiome{
+	"desert": {
+		name:     "Desert",
+		floor:    "floors/desert_rough.png",
+		pathing:  "floors/floor_tan.png",
+		sky:      "skies/sky_desert_pink.png",
+		boundary: "walls/boundary_teal.png",
+		music:    "icy_wastes.mp3",
+		walls: []MapTexture{
+			{Image: "walls/tech_0e.png"},
+			{Image: "walls/tech_3i.png"},
+		},
+		lighting: MapLighting{
+			Falloff: -100, Illumination: 500, MinLightRGB: [3]uint8{128, 128, 128}, MaxLightRGB: [3]uint8{255, 255, 255},
+		},
+		clutter: []MapClutter{
+			{Image: "rocks/rock_0.png", Height: 1.0, FloorPathMatch: floorPathMatch("desert"), Frequency: 0.3},
+		},
+		wallDensity:   0.04,
+		trees:         []string{"tree_0"},
+		treeHeight:    [2]float64{4, 16},
+		forestDensity: 0.5,
+		treeDensity:   0.05,
+	},
+	"grassland": {
+		name:     "Grassland",
+		floor:    "floors/grass.png",
+		pathing:  "floors/floor_1a.png",
+		sky:      "skies/sky_blue.png",
+		boundary: "walls/boundary_green.png",
+		music:    "bone_remains.mp3",
+		walls: []MapTexture{
+			{Image: "walls/tech_0e.png"},
+			{SideX: "walls/tech_3i.png", SideY: "walls/tech_0f.png"},
+			{Image: "walls/support_1a.png"},
+		},
+		lighting: MapLighting{
+			Falloff: -100, Illumination: 500, MinLightRGB: [3]uint8{100, 125, 150}, MaxLightRGB: [3]uint8{255, 255, 255},
+		},
+		clutter: []MapClutter{
+			{Image: "rocks/rock_0.png", Height: 1.0, FloorPathMatch: floorPathMatch("grass"), Frequency: 0.3},
+			{Image: "shrubbery/bush_0.png", Height: 1.0, FloorPathMatch: floorPathMatch("grass"), Frequency: 0.5},
+		},
+		wallDensity:   0.03,
+		trees:         []string{"tree_0", "tree_1"},
+		treeHeight:    [2]float64{8, 20},
+		forestDensity: 2,
+		treeDensity:   0.2,
+	},
+	"night": {
+		name:     "Night",
+		floor:    "floors/floor_green_night.png",
+		pathing:  "floors/floor_teal.png",
+		sky:      "skies/sky_blue_night.png",
+		boundary: "walls/boundary_green.png",
+		music:    "gi_i_dark_ambient.mp3",
+		walls: []MapTexture{
+			{Image: "walls/tech_0f.png"},
+			{Image: "walls/support_1a.png"},
+		},
+		lighting: MapLighting{
+			Falloff: -500, Illumination: 0, MinLightRGB: [3]uint8{16, 24, 30}, MaxLightRGB: [3]uint8{255, 255, 255},
+		},
+		clutter: []MapClutter{
+			{Image: "shrubbery/bush_0.png", Height: 1.0, FloorPathMatch: floorPathMatch("green"), Frequency: 0.3},
+		},
+		wallDensity:   0.03,
+		trees:         []string{"tree_1"},
+		treeHeight:    [2]float64{8, 20},
+		forestDensity: 1.5,
+		treeDensity:   0.15,
+	},
+}
+
+// sprite definitions of the trees that can be used by biome forests
+var mapGenerateTrees = map[string]MapSprite{
+	"tree_0": {
+		ID: "tree_0", Image: "shrubbery/tree_0.png", Height: 20, CollisionPxRadius: 56, CollisionPxHeight: 190, HitPoints: 0.1,
+	},
+	"tree_1": {
+		ID: "tree_1", Image: "shrubbery/tree_1.png", Height: 20, CollisionPxRadius: 60, CollisionPxHeight: 190, HitPoints: 0.1,
+	},
+}
+
+func floorPathMatch(expr string) *RegExp {
+	return &RegExp{Regexp: regexp.MustCompile(expr)}
+}
+
+// MapBiomes returns the names of biomes that can be used to generate maps
+func MapBiomes() []string {
+	biomes := make([]string, 0, len(mapBiomes))
+	for name := range mapBiomes {
+		biomes = append(biomes, name)
+	}
+	slices.Sort(biomes)
+	return biomes
+}
+
+type mapGenerator struct {
+	m     *Map
+	biome *mapBiome
+	rng   *Rand
+
+	width, height int
+
+	// cells of pads and roads that must be kept clear of walls
+	clear [][]bool
+	// cells taken by wall clusters, including the space kept around each
+	walls [][]bool
+	// positions of the drop zone and spawn points
+	pads [][2]int
+	// prefab index of each wall cluster in the order they were placed
+	placed []int
+}
+
+// GenerateMap procedurally generates a complete map definition, with the drop zone and
+// every spawn point guaranteed to be connected through pathing
+func GenerateMap(opts MapGenerateOptions) (*Map, error) {
+	biome, ok := mapBiomes[strings.ToLower(opts.Biome)]
+	if !ok {
+		return nil, fmt.Errorf("unknown biome '%s', must be one of: [%s]", opts.Biome, strings.Join(MapBiomes(), ", "))
+	}
+
+	for _, size := range []int{opts.Width, opts.Height} {
+		if size < MAP_GENERATE_MIN_SIZE || size > MAP_GENERATE_MAX_SIZE {
+			return nil, fmt.Errorf(
+				"map size %dx%d must be between %d and %d in each direction",
+				opts.Width, opts.Height, MAP_GENERATE_MIN_SIZE, MAP_GENERATE_MAX_SIZE,
+			)
+		}
+	}
+
+	gen := &mapGenerator{
+		biome:  biome,
+		rng:    NewSeededRNG(opts.Seed),
+		width:  opts.Width,
+		height: opts.Height,
+		clear:  newCellGrid(opts.Width, opts.Height),
+		walls:  newCellGrid(opts.Width, opts.Height),
+	}
+
+	gen.initMap(opts.Seed)
+	gen.placeDropZone()
+	gen.placeSpawnPoints()
+	gen.placeFlooring()
+	gen.placeWallClusters()
+	gen.placeForests()
+
+	if err := gen.connectPathing(); err != nil {
+		return nil, err
+	}
+
+	return gen.m, nil
+}
+
+// LoadGeneratedMap generates a map and loads it the same as it would be from its map file
+func LoadGeneratedMap(opts MapGenerateOptions) (*Map, error) {
+	m, err := GenerateMap(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	mapYaml, err := MarshalMapYAML(m)
+	if err != nil {
+		return nil, err
+	}
+	return parseMap(mapYaml, m.Name)
+}
+
+// MarshalMapYAML marshals the map definition in the same layout as map files, with short lists in flow style
+func MarshalMapYAML(m *Map) ([]byte, error) {
+	node := &yaml.Node{}
+	if err := node.Encode(m); err != nil {
+		return nil, err
+	}
+	setFlowStyle(node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// setFlowStyle sets lists of values, and short lists of coordinates, to flow style
+func setFlowStyle(node *yaml.Node) {
+	if node.Kind == yaml.SequenceNode && (isValueSequence(node, 0) || isCoordSequence(node, 4)) {
+		node.Style = yaml.FlowStyle
+		return
+	}
+	for _, child := range node.Content {
+		setFlowStyle(child)
+	}
+}
+
+// isValueSequence returns true if the node is a list of values, of the given length if not zero
+func isValueSequence(node *yaml.Node, length int) bool {
+	if node.Kind != yaml.SequenceNode || (length > 0 && len(node.Content) != length) {
+		return false
+	}
+	for _, child := range node.Content {
+		if child.Kind != yaml.ScalarNode {
+			return false
+		}
+	}
+	return true
+}
+
+// isCoordSequence returns true if the node is a list of X/Y coordinates no longer than the max length
+func isCoordSequence(node *yaml.Node, maxLength int) bool {
+	if len(node.Content) > maxLength {
+		return false
+	}
+	for _, child := range node.Content {
+		if !isValueSequence(child, 2) {
+			return false
+		}
+	}
+	return true
+}
+
+func newCellGrid(width, height int) [][]bool {
+	grid := make([][]bool, width)
+	for x := range grid {
+		grid[x] = make([]bool, height)
+	}
+	return grid
+}
+
+func (gen *mapGenerator) initMap(seed int64) {
+	b := gen.biome
+
+	textures := make(map[int]MapTexture, len(b.walls)+1)
+	textures[0] = MapTexture{}
+	for i, tex := range b.walls {
+		textures[i+1] = tex
+	}
+
+	clutter := make([]MapClutter, len(b.clutter))
+	copy(clutter, b.clutter)
+
+	gen.m = &Map{
+		Name:             fmt.Sprintf("%s %d", b.name, seed),
+		Seed:             seed,
+		MusicPath:        b.music,
+		NumRaycastLevels: MAP_GENERATE_LEVELS,
+		Levels:           [][][]int{},
+		GenerateLevels: MapGenerateLevels{
+			MapSize:      [2]int{gen.width, gen.height},
+			BoundaryWall: MapTexture{Image: b.boundary},
+		},
+		Lighting: b.lighting,
+		Textures: textures,
+		FloorBox: MapTexture{Image: b.floor},
+		SkyBox:   MapTexture{Image: b.sky},
+		Flooring: MapFlooring{Default: b.floor},
+		Clutter:  clutter,
+	}
+}
+
+// edgeInset is the distance from the map edge to keep the drop zone and spawn points
+func (gen *mapGenerator) edgeInset() int {
+	return max(2*MAP_GENERATE_PAD_SIZE, min(gen.width, gen.height)/10)
+}
+
+func (gen *mapGenerator) placeDropZone() {
+	w, h, inset := gen.width, gen.height, gen.edgeInset()
+
+	// drop zone along a random map edge
+	var x, y int
+	switch CardinalDirection(gen.rng.Intn(4)) {
+	case NORTH:
+		x, y = gen.rng.RandIntIn(w/4, 3*w/4), h-inset
+	case EAST:
+		x, y = w-inset, gen.rng.RandIntIn(h/4, 3*h/4)
+	case SOUTH:
+		x, y = gen.rng.RandIntIn(w/4, 3*w/4), inset
+	default:
+		x, y = inset, gen.rng.RandIntIn(h/4, 3*h/4)
+	}
+
+	// face towards the map center
+	cX, cY := float64(w)/2, float64(h)/2
+	pX, pY := float64(x)+0.5, float64(y)+0.5
+	heading := math.Round(AngleToCardinal(math.Atan2(cY-pY, cX-pX)))
+
+	gen.m.DropZone = DropZone{Position: [2]float64{pX, pY}, Heading: heading}
+	gen.pads = append(gen.pads, [2]int{x, y})
+}
+
+func (gen *mapGenerator) placeSpawnPoints() {
+	w, h, inset := gen.width, gen.height, gen.edgeInset()
+	cX, cY := float64(w)/2, float64(h)/2
+
+	// spawn points spread around the far side of the map center from the drop zone
+	dz := gen.m.DropZone.Position
+	farAngle := math.Atan2(dz[1]-cY, dz[0]-cX) + geom.Pi
+	spread := geom.Pi / 4
+
+	gen.m.SpawnPoints = make([][2]float64, 0, MAP_GENERATE_SPAWN_POINTS)
+	for i := range MAP_GENERATE_SPAWN_POINTS {
+		angle := farAngle + spread*(float64(i)-float64(MAP_GENERATE_SPAWN_POINTS-1)/2)
+		angle += gen.rng.RandFloat64In(-spread/4, spread/4)
+		dist := float64(min(w, h)) * gen.rng.RandFloat64In(0.25, 0.4)
+
+		line := geom.LineFromAngle(cX, cY, angle, dist)
+		x := geom.ClampInt(int(line.X2), inset, w-inset-1)
+		y := geom.ClampInt(int(line.Y2), inset, h-inset-1)
+
+		gen.m.SpawnPoints = append(gen.m.SpawnPoints, [2]float64{float64(x) + 0.5, float64(y) + 0.5})
+		gen.pads = append(gen.pads, [2]int{x, y})
+	}
+}
+
+// placeFlooring creates pads under the drop zone and spawn points, and roads from the drop zone to each spawn point
+func (gen *mapGenerator) placeFlooring() {
+	pathing := MapFloorPathing{Image: gen.biome.pathing}
+
+	for _, pad := range gen.pads {
+		x0, y0 := max(1, pad[0]-MAP_GENERATE_PAD_SIZE), max(1, pad[1]-MAP_GENERATE_PAD_SIZE)
+		x1, y1 := min(gen.width-2, pad[0]+MAP_GENERATE_PAD_SIZE), min(gen.height-2, pad[1]+MAP_GENERATE_PAD_SIZE)
+		pathing.Rects = append(pathing.Rects, [2][2]int{{x0, y0}, {x1, y1}})
+		gen.clearArea(float64(pad[0]), float64(pad[1]), float64(2*MAP_GENERATE_PAD_SIZE))
+	}
+
+	dz := gen.pads[0]
+	for _, spawn := range gen.pads[1:] {
+		// bend each road at a point offset from the middle of the straight line to the spawn point
+		line := geom.Line{X1: float64(dz[0]), Y1: float64(dz[1]), X2: float64(spawn[0]), Y2: float64(spawn[1])}
+		length := geom.Distance(line.X1, line.Y1, line.X2, line.Y2)
+		mid := geom.LineFromAngle(line.X1, line.Y1, line.Angle(), length/2)
+		bend := geom.LineFromAngle(mid.X2, mid.Y2, line.Angle()+geom.HalfPi, gen.rng.RandFloat64In(-length/4, length/4))
+		bX := geom.ClampInt(int(bend.X2), 2, gen.width-3)
+		bY := geom.ClampInt(int(bend.Y2), 2, gen.height-3)
+
+		road := [][2]int{dz, {bX, bY}, spawn}
+		pathing.Lines = append(pathing.Lines, road)
+
+		for i := 1; i < len(road); i++ {
+			gen.clearLine(road[i-1], road[i], 2)
+		}
+	}
+
+	gen.m.Flooring.Pathing = []MapFloorPathing{pathing}
+}
+
+// clearArea keeps cells within the radius of the position clear of walls
+func (gen *mapGenerator) clearArea(x, y, radius float64) {
+	x0, y0 := max(0, int(x-radius)), max(0, int(y-radius))
+	x1, y1 := min(gen.width-1, int(x+radius)), min(gen.height-1, int(y+radius))
+	for i := x0; i <= x1; i++ {
+		for j := y0; j <= y1; j++ {
+			if geom.Distance(x, y, float64(i), float64(j)) <= radius {
+				gen.clear[i][j] = true
+			}
+		}
+	}
+}
+
+// clearLine keeps cells within the radius of the line segment clear of walls
+func (gen *mapGenerator) clearLine(p1, p2 [2]int, radius float64) {
+	line := geom.Line{X1: float64(p1[0]), Y1: float64(p1[1]), X2: float64(p2[0]), Y2: float64(p2[1])}
+	angle := line.Angle()
+	dist := geom.Distance(line.X1, line.Y1, line.X2, line.Y2)
+	for d := 0.0; d <= dist; d += 0.5 {
+		nLine := geom.LineFromAngle(line.X1, line.Y1, angle, d)
+		gen.clearArea(nLine.X2, nLine.Y2, radius)
+	}
+}
+
+func (gen *mapGenerator) placeWallClusters() {
+	numTextures := len(gen.biome.walls)
+
+	// a handful of random cluster shapes are each placed in multiple positions
+	numShapes := gen.rng.RandIntIn(4, 8)
+	prefabs := make([]MapGeneratePrefabs, numShapes)
+	totalCells := 0
+	for i := range prefabs {
+		sizeX, sizeY := gen.rng.RandIntIn(2, 7), gen.rng.RandIntIn(2, 7)
+		baseTex := gen.rng.RandIntIn(1, numTextures+1)
+		topTex := gen.rng.RandIntIn(1, numTextures+1)
+
+		base := make([][]int, sizeY)
+		top := make([][]int, sizeY)
+		hasTop := false
+		for y := range sizeY {
+			base[y] = make([]int, sizeX)
+			top[y] = make([]int, sizeX)
+			for x := range sizeX {
+				// always fill the center so the cluster is never empty
+				center := x == sizeX/2 && y == sizeY/2
+				if !center && gen.rng.Float64() > 0.75 {
+					continue
+				}
+				base[y][x] = baseTex
+				totalCells++
+
+				if gen.rng.Float64() < 0.4 {
+					top[y][x] = topTex
+					hasTop = true
+				}
+			}
+		}
+
+		// prefab layers are listed from the top level down
+		layers := [][][]int{base}
+		if hasTop {
+			layers = [][][]int{top, base}
+		}
+		prefabs[i] = MapGeneratePrefabs{Name: fmt.Sprintf("Cluster%d", i), Layers: layers}
+	}
+
+	// place clusters until the biome wall density is reached, within a limited number of attempts
+	avgCells := float64(totalCells) / float64(numShapes)
+	numClusters := int(gen.biome.wallDensity * float64(gen.width*gen.height) / avgCells)
+	for attempt := 0; attempt < 4*numClusters && len(gen.placed) < numClusters; attempt++ {
+		i := gen.rng.Intn(numShapes)
+		layer := prefabs[i].Layers[0]
+		sizeX, sizeY := len(layer[0]), len(layer)
+		x, y := gen.rng.RandIntIn(3, gen.width-sizeX-3), gen.rng.RandIntIn(3, gen.height-sizeY-3)
+
+		// keep a gap around each cluster so that clusters never join to enclose an area
+		if !gen.canPlaceWall(x-2, y-2, x+sizeX+1, y+sizeY+1) {
+			continue
+		}
+		for cX := x; cX < x+sizeX; cX++ {
+			for cY := y; cY < y+sizeY; cY++ {
+				gen.walls[cX][cY] = true
+			}
+		}
+
+		prefabs[i].Positions = append(prefabs[i].Positions, [2]int{x, y})
+		gen.placed = append(gen.placed, i)
+	}
+
+	gen.m.GenerateLevels.Prefabs = prefabs
+}
+
+func (gen *mapGenerator) canPlaceWall(x0, y0, x1, y1 int) bool {
+	for x := max(0, x0); x <= min(gen.width-1, x1); x++ {
+		for y := max(0, y0); y <= min(gen.height-1, y1); y++ {
+			if gen.clear[x][y] || gen.walls[x][y] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (gen *mapGenerator) placeForests() {
+	b := gen.biome
+	areaKm := float64(gen.width*gen.height) * math.Pow(METERS_PER_UNIT/1000, 2)
+	numForests := int(math.Round(b.forestDensity * areaKm))
+
+	usedTrees := make(map[string]bool, len(b.trees))
+	for range numForests {
+		sizeX, sizeY := gen.rng.RandIntIn(8, 25), gen.rng.RandIntIn(8, 25)
+		sizeX, sizeY = min(sizeX, gen.width/4), min(sizeY, gen.height/4)
+
+		// try a few positions for each forest that keep it away from the drop zone and spawn points
+		for range 10 {
+			x, y := gen.rng.RandIntIn(2, gen.width-sizeX-2), gen.rng.RandIntIn(2, gen.height-sizeY-2)
+			if !gen.canPlaceForest(x, y, x+sizeX, y+sizeY) {
+				continue
+			}
+
+			treeID := b.trees[gen.rng.Intn(len(b.trees))]
+			usedTrees[treeID] = true
+
+			gen.m.SpriteFill = append(gen.m.SpriteFill, MapSpriteFill{
+				SpriteID:    treeID,
+				Quantity:    1 + int(b.treeDensity*float64(sizeX*sizeY)),
+				HeightRange: b.treeHeight,
+				Rect:        [2][2]int{{x, y}, {x + sizeX, y + sizeY}},
+			})
+			break
+		}
+	}
+
+	for _, treeID := range b.trees {
+		if usedTrees[treeID] {
+			gen.m.Sprites = append(gen.m.Sprites, mapGenerateTrees[treeID])
+		}
+	}
+}
+
+func (gen *mapGenerator) canPlaceForest(x0, y0, x1, y1 int) bool {
+	for _, pad := range gen.pads {
+		nearX := geom.ClampInt(pad[0], x0, x1)
+		nearY := geom.ClampInt(pad[1], y0, y1)
+		if geom.Distance(float64(pad[0]), float64(pad[1]), float64(nearX), float64(nearY)) < float64(3*MAP_GENERATE_PAD_SIZE) {
+			return false
+		}
+	}
+
+	for x := x0; x <= x1; x++ {
+		for y := y0; y <= y1; y++ {
+			if gen.walls[x][y] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// connectPathing removes the most recently placed wall clusters until every spawn point
+// can be reached from the drop zone
+func (gen *mapGenerator) connectPathing() error {
+	for {
+		connected, err := gen.spawnsConnected()
+		if err != nil {
+			return err
+		}
+		if connected {
+			break
+		}
+		if len(gen.placed) == 0 {
+			return fmt.Errorf("unable to connect spawn points to the drop zone")
+		}
+
+		last := gen.placed[len(gen.placed)-1]
+		gen.placed = gen.placed[:len(gen.placed)-1]
+
+		prefab := &gen.m.GenerateLevels.Prefabs[last]
+		prefab.Positions = prefab.Positions[:len(prefab.Positions)-1]
+	}
+
+	// prefabs must have at least one position
+	gen.m.GenerateLevels.Prefabs = slices.DeleteFunc(gen.m.GenerateLevels.Prefabs, func(p MapGeneratePrefabs) bool {
+		return len(p.Positions) == 0
+	})
+	return nil
+}
+
+func (gen *mapGenerator) spawnsConnected() (bool, error) {
+	// generate the map levels from a copy of the map definition the same as it would be loaded from its file
+	m := *gen.m
+	m.GenerateLevels.Prefabs = slices.DeleteFunc(slices.Clone(m.GenerateLevels.Prefabs), func(p MapGeneratePrefabs) bool {
+		return len(p.Positions) == 0
+	})
+
+	mapYaml, err := MarshalMapYAML(&m)
+	if err != nil {
+		return false, err
+	}
+	loaded, err := parseMap(mapYaml, m.Name)
+	if err != nil {
+		return false, err
+	}
+
+	pathing := newMapPathing(loaded)
+	dz := gen.m.DropZone.Position
+	for _, spawn := range gen.m.SpawnPoints {
+		start, finish := &geom.Vector2{X: dz[0], Y: dz[1]}, &geom.Vector2{X: spawn[0], Y: spawn[1]}
+		if _, err := pathing.FindPath(start, finish, UnitTypePathingCosts(MechUnitType)); err != nil {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -45,10 +45,12 @@ func NewMech(r *ModelMechResource) *Mech {
 		UnitModel: &UnitModel{
 			name:               r.Name,
 			variant:            r.Variant,
+			destroyEffects:     r.DestroyEffects,
 			unitType:           MechUnitType,
 			anchor:             raycaster.AnchorBottom,
 			armor:              r.Armor,
 			structure:          r.Structure,
+			locations:          NewUnitLocations(MechUnitType, r.Armor, r.Structure, r.Locations),
 			heatSinks:          r.HeatSinks.Quantity,
 			heatSinkType:       r.HeatSinks.Type.HeatSinkType,
 			armament:           make([]Weapon, 0),
@@ -61,6 +63,8 @@ func NewMech(r *ModelMechResource) *Mech {
 			jumpJets:           r.JumpJets,
 			maxJumpJetDuration: MECH_JUMP_JET_DURATION_SECONDS,
 			powered:            POWER_ON,
+			sensorRange:        sensorRangeUnits(r.SensorRange),
+			equipment:          r.Equipment,
 		},
 	}
 
@@ -98,6 +102,9 @@ func (e *Mech) CloneUnit() Unit {
 		eClone.AddArmament(weapon.Clone())
 	}
 
+	// locations need to be cloned so damage is not shared with the original
+	eClone.locations = CloneUnitLocations(e.locations)
+
 	return eClone
 }
 
@@ -223,7 +230,7 @@ func (e *Mech) Update() bool {
 		e.jumpJetDelay = MECH_JUMP_JET_DELAY_SECONDS
 	} else {
 		jVelocity := Line3dDistanceXY(e.jumpJetVector)
-		if e.positionZ > 0 {
+		if e.positionZ > e.groundZ {
 			if jVelocity != 0 {
 				// reduce jump jet velocity in air while jets inactive
 				// for simplicity, using gravity and unit tonnage as factor of resistance
@@ -293,12 +300,12 @@ func (e *Mech) Update() bool {
 		e.velocity = newV
 	}
 
-	if e.targetVelocityZ != e.velocityZ || e.positionZ > 0 {
+	if e.targetVelocityZ != e.velocityZ || e.positionZ > e.groundZ {
 		// TODO: move vertical velocity toward target by amount allowed by calculated vertical acceleration
 		var zDeltaV, zNewV float64
 		if e.targetVelocityZ > 0 {
 			zDeltaV = 0.005 // FIXME: testing
-		} else if e.positionZ > 0 {
+		} else if e.positionZ > e.groundZ {
 			zDeltaV = -GRAVITY_UNITS_PTT // TODO: model gravity multiplier into map yaml
 		}
 
@@ -308,12 +315,12 @@ func (e *Mech) Update() bool {
 			// bound velocity changes to target velocity (for jump jets, ascent only)
 			zNewV = e.targetVelocityZ
 		}
-		if e.positionZ <= 0 && zNewV < 0 {
+		if e.positionZ <= e.groundZ && zNewV < 0 {
 			// negative velocity returns to zero when back on the ground
 			zNewV = 0
 		}
 
-		if zNewV > 0 && e.positionZ >= CEILING_JUMP {
+		if zNewV > 0 && e.positionZ-e.groundZ >= CEILING_JUMP {
 			// restrict jump height
 			zNewV = 0
 		}
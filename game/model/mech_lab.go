@@ -0,0 +1,473 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jinzhu/copier"
+	"github.com/pixelmek-3d/pixelmek-3d/game/resources"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// MECH_ENGINE_HEAT_SINKS is the number of heat sinks built into the engine that need no tonnage or critical slots
+	MECH_ENGINE_HEAT_SINKS int = 10
+
+	MECH_HEAT_SINK_TONNAGE  float64 = 1.0
+	MECH_AMMO_SLOTS_PER_TON int     = 1
+)
+
+// critical slots in each mech location left for weapons and equipment after the
+// engine, gyro, cockpit and actuators have taken the rest
+var mechLocationSlots = map[Location]int{
+	HEAD:         1,
+	CENTER_TORSO: 2,
+	LEFT_TORSO:   12,
+	RIGHT_TORSO:  12,
+	LEFT_ARM:     8,
+	RIGHT_ARM:    8,
+	LEFT_LEG:     2,
+	RIGHT_LEG:    2,
+}
+
+// locations on the other side of the mech used to mirror weapon offsets
+var mechMirrorLocations = map[Location]Location{
+	LEFT_TORSO: RIGHT_TORSO, RIGHT_TORSO: LEFT_TORSO,
+	LEFT_ARM: RIGHT_ARM, RIGHT_ARM: LEFT_ARM,
+	LEFT_LEG: RIGHT_LEG, RIGHT_LEG: LEFT_LEG,
+}
+
+var variantFileRegexp = regexp.MustCompile(`[^a-z0-9_\-]+`)
+
+// MechLocationSlots returns the number of critical slots available for weapons and equipment in the mech location
+func MechLocationSlots(location Location) int {
+	return mechLocationSlots[location]
+}
+
+// MechLabWeapon is a weapon resource that can be mounted on a mech in the mech lab
+type MechLabWeapon struct {
+	Weapon     string
+	Type       WeaponType
+	Name       string
+	ShortName  string
+	Tech       TechBase
+	Tonnage    float64
+	Slots      int
+	AmmoPerTon int
+	// ammo needed by the weapon, nil for weapons that do not use ammo
+	Ammo *ModelResourceAmmo
+}
+
+// MechLoadout is a copy of a mech resource being customized in the mech lab
+type MechLoadout struct {
+	Resource  *ModelMechResource
+	resources *ModelResources
+
+	// tonnage of the mech not available for weapons, ammo, additional heat sinks and jump jets
+	chassisTonnage float64
+	// weapon offsets in pixels by location, taken from the variants of the same chassis
+	offsets map[Location][2]int
+}
+
+// GetMechLabWeapon gets the mech lab details for the weapon resource
+func (r *ModelResources) GetMechLabWeapon(weapon string, weaponType WeaponType) (*MechLabWeapon, error) {
+	switch weaponType {
+	case ENERGY:
+		if w, ok := r.EnergyWeapons[weapon]; ok {
+			return &MechLabWeapon{
+				Weapon: weapon, Type: weaponType, Name: w.Name, ShortName: w.ShortName, Tech: w.Tech.TechBase,
+				Tonnage: w.Tonnage, Slots: weaponSlots(w.Slots),
+			}, nil
+		}
+	case MISSILE:
+		if w, ok := r.MissileWeapons[weapon]; ok {
+			lw := &MechLabWeapon{
+				Weapon: weapon, Type: weaponType, Name: w.Name, ShortName: w.ShortName, Tech: w.Tech.TechBase,
+				Tonnage: w.Tonnage, Slots: weaponSlots(w.Slots), AmmoPerTon: w.AmmoPerTon,
+			}
+			// missile ammo is pooled by missile class, the same way as when the unit ammo is loaded
+			short := strings.ToLower(w.ShortName)
+			switch {
+			case w.AmmoPerTon == 0:
+				// missile weapon that does not use ammo
+			case strings.Contains(short, "lrm"):
+				lw.Ammo = &ModelResourceAmmo{Type: ModelAmmoType{AMMO_LRM}}
+			case strings.Contains(short, "srm") && w.LockOn != nil && w.LockOn.LockRequired:
+				lw.Ammo = &ModelResourceAmmo{Type: ModelAmmoType{AMMO_STREAK_SRM}}
+			case strings.Contains(short, "srm"):
+				lw.Ammo = &ModelResourceAmmo{Type: ModelAmmoType{AMMO_SRM}}
+			}
+			return lw, nil
+		}
+	case BALLISTIC:
+		if w, ok := r.BallisticWeapons[weapon]; ok {
+			lw := &MechLabWeapon{
+				Weapon: weapon, Type: weaponType, Name: w.Name, ShortName: w.ShortName, Tech: w.Tech.TechBase,
+				Tonnage: w.Tonnage, Slots: weaponSlots(w.Slots), AmmoPerTon: w.AmmoPerTon,
+			}
+			if w.AmmoPerTon > 0 {
+				lw.Ammo = &ModelResourceAmmo{Type: ModelAmmoType{AMMO_BALLISTIC}, ForWeapon: weapon}
+			}
+			return lw, nil
+		}
+	}
+	return nil, fmt.Errorf("%s weapon resource does not exist %s", weaponType, weapon)
+}
+
+// GetMechLabWeaponList gets the weapons of the tech base that can be mounted in the mech lab, sorted by type and name
+func (r *ModelResources) GetMechLabWeaponList(tech TechBase) []*MechLabWeapon {
+	weapons := make([]string, 0, len(r.EnergyWeapons)+len(r.MissileWeapons)+len(r.BallisticWeapons))
+	weaponTypes := make(map[string]WeaponType, cap(weapons))
+	for k := range r.EnergyWeapons {
+		weapons = append(weapons, k)
+		weaponTypes[k] = ENERGY
+	}
+	for k := range r.MissileWeapons {
+		weapons = append(weapons, k)
+		weaponTypes[k] = MISSILE
+	}
+	for k := range r.BallisticWeapons {
+		weapons = append(weapons, k)
+		weaponTypes[k] = BALLISTIC
+	}
+
+	weaponList := make([]*MechLabWeapon, 0, len(weapons))
+	for _, k := range weapons {
+		if strings.HasPrefix(k, "_") {
+			// special weapons (like the ejection pod) cannot be mounted
+			continue
+		}
+		w, err := r.GetMechLabWeapon(k, weaponTypes[k])
+		if err != nil || (w.Tech != tech && w.Tech != COMMON) {
+			continue
+		}
+		weaponList = append(weaponList, w)
+	}
+
+	sort.Slice(weaponList, func(i, j int) bool {
+		wI, wJ := weaponList[i], weaponList[j]
+		return wI.Type < wJ.Type || (wI.Type == wJ.Type && wI.Name < wJ.Name)
+	})
+	return weaponList
+}
+
+// NewMechLoadout creates an editable copy of the mech resource
+func NewMechLoadout(r *ModelResources, m *ModelMechResource) (*MechLoadout, error) {
+	resource := &ModelMechResource{}
+	if err := copier.CopyWithOption(resource, m, copier.Option{DeepCopy: true}); err != nil {
+		return nil, err
+	}
+	if resource.HeatSinks == nil {
+		resource.HeatSinks = &ModelResourceHeatSinks{Type: ModelHeatSinkType{SINGLE}}
+	}
+
+	l := &MechLoadout{
+		Resource:  resource,
+		resources: r,
+		offsets:   make(map[Location][2]int),
+	}
+
+	// omnimech variants of the same chassis share the same pod space, so the variant with the
+	// most equipment tonnage determines how much of the mech tonnage belongs to the chassis
+	var podSpace float64
+	for _, v := range r.GetMechResourceList() {
+		if v.Name != m.Name || v.Tonnage != m.Tonnage {
+			continue
+		}
+		vTonnage, err := equipmentTonnage(r, v)
+		if err != nil {
+			return nil, err
+		}
+		podSpace = math.Max(podSpace, vTonnage)
+
+		for _, a := range v.Armament {
+			if _, ok := l.offsets[a.Location.Location]; !ok {
+				l.offsets[a.Location.Location] = a.Offset
+			}
+		}
+	}
+	l.chassisTonnage = m.Tonnage - podSpace
+
+	return l, nil
+}
+
+// equipmentTonnage is the tonnage used by weapons, ammo, additional heat sinks and jump jets
+func equipmentTonnage(r *ModelResources, m *ModelMechResource) (float64, error) {
+	var tonnage float64
+	for _, a := range m.Armament {
+		w, err := r.GetMechLabWeapon(a.Weapon, a.Type.WeaponType)
+		if err != nil {
+			return 0, err
+		}
+		tonnage += w.Tonnage
+	}
+	for _, a := range m.Ammo {
+		tonnage += a.Tons
+	}
+	if m.HeatSinks != nil && m.HeatSinks.Quantity > MECH_ENGINE_HEAT_SINKS {
+		tonnage += float64(m.HeatSinks.Quantity-MECH_ENGINE_HEAT_SINKS) * MECH_HEAT_SINK_TONNAGE
+	}
+	tonnage += float64(m.JumpJets) * jumpJetTonnage(m.Tonnage)
+	return tonnage, nil
+}
+
+func jumpJetTonnage(mechTonnage float64) float64 {
+	switch {
+	case mechTonnage < 60:
+		return 0.5
+	case mechTonnage < 90:
+		return 1.0
+	default:
+		return 2.0
+	}
+}
+
+func weaponSlots(slots int) int {
+	// weapon resources without slots defined take up a single slot
+	return max(slots, 1)
+}
+
+func heatSinkSlots(heatSinkType HeatSinkType) int {
+	if heatSinkType == DOUBLE {
+		return 2
+	}
+	return 1
+}
+
+// Tonnage returns the total tonnage of the chassis with the current loadout
+func (l *MechLoadout) Tonnage() float64 {
+	tonnage, err := equipmentTonnage(l.resources, l.Resource)
+	if err != nil {
+		return math.Inf(1)
+	}
+	return l.chassisTonnage + tonnage
+}
+
+// MaxTonnage returns the tonnage the loadout cannot exceed
+func (l *MechLoadout) MaxTonnage() float64 {
+	return l.Resource.Tonnage
+}
+
+// LocationSlots returns the critical slots used by weapons mounted in the location
+func (l *MechLoadout) LocationSlots(location Location) int {
+	var slots int
+	for _, a := range l.Resource.Armament {
+		if a.Location.Location != location {
+			continue
+		}
+		if w, err := l.resources.GetMechLabWeapon(a.Weapon, a.Type.WeaponType); err == nil {
+			slots += w.Slots
+		}
+	}
+	return slots
+}
+
+// Slots returns the total critical slots used by weapons, ammo and additional heat sinks
+func (l *MechLoadout) Slots() int {
+	var slots int
+	for _, location := range UnitTypeLocations(MechUnitType) {
+		slots += l.LocationSlots(location)
+	}
+	for _, a := range l.Resource.Ammo {
+		slots += int(math.Ceil(a.Tons)) * MECH_AMMO_SLOTS_PER_TON
+	}
+	if extra := l.Resource.HeatSinks.Quantity - MECH_ENGINE_HEAT_SINKS; extra > 0 {
+		slots += extra * heatSinkSlots(l.Resource.HeatSinks.Type.HeatSinkType)
+	}
+	return slots
+}
+
+// MaxSlots returns the total critical slots available for weapons and equipment
+func (l *MechLoadout) MaxSlots() int {
+	var slots int
+	for _, location := range UnitTypeLocations(MechUnitType) {
+		slots += MechLocationSlots(location)
+	}
+	return slots
+}
+
+// Validate checks the loadout tonnage and critical slots, returning all problems found
+func (l *MechLoadout) Validate() error {
+	errs := make([]error, 0)
+
+	if tonnage := l.Tonnage(); tonnage > l.MaxTonnage()+1e-9 {
+		errs = append(errs, fmt.Errorf("loadout is %0.1f tons over the %0.0f ton limit", tonnage-l.MaxTonnage(), l.MaxTonnage()))
+	}
+
+	for _, a := range l.Resource.Armament {
+		if _, err := l.resources.GetMechLabWeapon(a.Weapon, a.Type.WeaponType); err != nil {
+			errs = append(errs, err)
+		}
+		if !slices.Contains(UnitTypeLocations(MechUnitType), a.Location.Location) {
+			errs = append(errs, fmt.Errorf("%s cannot be mounted in location %s", a.Weapon, a.Location.ShortName()))
+		}
+	}
+	for _, location := range UnitTypeLocations(MechUnitType) {
+		if slots, maxSlots := l.LocationSlots(location), MechLocationSlots(location); slots > maxSlots {
+			errs = append(errs, fmt.Errorf("location %s uses %d of %d critical slots", location.ShortName(), slots, maxSlots))
+		}
+	}
+	if slots, maxSlots := l.Slots(), l.MaxSlots(); slots > maxSlots {
+		errs = append(errs, fmt.Errorf("loadout uses %d of %d critical slots", slots, maxSlots))
+	}
+
+	for _, a := range l.Resource.Ammo {
+		if !l.ammoNeeded(a) {
+			errs = append(errs, fmt.Errorf("%s ammo has no weapon to use it", ammoLabel(a)))
+		}
+	}
+	if l.Resource.HeatSinks.Quantity < MECH_ENGINE_HEAT_SINKS {
+		errs = append(errs, fmt.Errorf("at least %d heat sinks are required", MECH_ENGINE_HEAT_SINKS))
+	}
+
+	return errors.Join(errs...)
+}
+
+func ammoLabel(a *ModelResourceAmmo) string {
+	if a.ForWeapon != "" {
+		return a.ForWeapon
+	}
+	return a.Type.ShortName()
+}
+
+func ammoMatches(a, b *ModelResourceAmmo) bool {
+	return a.Type.AmmoType == b.Type.AmmoType && a.ForWeapon == b.ForWeapon
+}
+
+// ammoNeeded returns true if a mounted weapon uses the ammo
+func (l *MechLoadout) ammoNeeded(ammo *ModelResourceAmmo) bool {
+	for _, a := range l.Resource.Armament {
+		w, err := l.resources.GetMechLabWeapon(a.Weapon, a.Type.WeaponType)
+		if err == nil && w.Ammo != nil && ammoMatches(w.Ammo, ammo) {
+			return true
+		}
+	}
+	return false
+}
+
+// AmmoList returns the ammo used by the mounted weapons along with any other ammo already in the loadout,
+// with the tons of each currently loaded
+func (l *MechLoadout) AmmoList() []*ModelResourceAmmo {
+	ammoList := make([]*ModelResourceAmmo, 0, len(l.Resource.Ammo))
+	addAmmo := func(ammo *ModelResourceAmmo) {
+		for _, a := range ammoList {
+			if ammoMatches(a, ammo) {
+				a.Tons += ammo.Tons
+				return
+			}
+		}
+		ammoList = append(ammoList, &ModelResourceAmmo{Type: ammo.Type, ForWeapon: ammo.ForWeapon, Tons: ammo.Tons})
+	}
+
+	for _, a := range l.Resource.Ammo {
+		addAmmo(a)
+	}
+	for _, a := range l.Resource.Armament {
+		w, err := l.resources.GetMechLabWeapon(a.Weapon, a.Type.WeaponType)
+		if err == nil && w.Ammo != nil {
+			addAmmo(&ModelResourceAmmo{Type: w.Ammo.Type, ForWeapon: w.Ammo.ForWeapon})
+		}
+	}
+	return ammoList
+}
+
+// SetAmmoTons sets the tons of the ammo type for the weapon, removing it from the loadout if zero
+func (l *MechLoadout) SetAmmoTons(ammo *ModelResourceAmmo, tons float64) {
+	tons = math.Max(0, tons)
+
+	ammoList := make([]*ModelResourceAmmo, 0, len(l.Resource.Ammo)+1)
+	for _, a := range l.Resource.Ammo {
+		if !ammoMatches(a, ammo) {
+			ammoList = append(ammoList, a)
+		}
+	}
+	if tons > 0 {
+		ammoList = append(ammoList, &ModelResourceAmmo{Type: ammo.Type, ForWeapon: ammo.ForWeapon, Tons: tons})
+	}
+	l.Resource.Ammo = ammoList
+}
+
+// SetHeatSinks sets the number of heat sinks, no less than those built into the engine
+func (l *MechLoadout) SetHeatSinks(quantity int) {
+	l.Resource.HeatSinks.Quantity = max(quantity, MECH_ENGINE_HEAT_SINKS)
+}
+
+// AddWeapon mounts the weapon in the location
+func (l *MechLoadout) AddWeapon(w *MechLabWeapon, location Location) {
+	l.Resource.Armament = append(l.Resource.Armament, &ModelResourceArmament{
+		Weapon:   w.Weapon,
+		Type:     ModelWeaponType{w.Type},
+		Location: ModelLocation{location},
+		Offset:   l.locationOffset(location),
+	})
+}
+
+// RemoveWeapon removes the weapon at the index of the armament
+func (l *MechLoadout) RemoveWeapon(index int) {
+	if index < 0 || index >= len(l.Resource.Armament) {
+		return
+	}
+	l.Resource.Armament = slices.Delete(l.Resource.Armament, index, index+1)
+}
+
+// MoveWeapon moves the weapon at the index of the armament to another location
+func (l *MechLoadout) MoveWeapon(index int, location Location) {
+	if index < 0 || index >= len(l.Resource.Armament) {
+		return
+	}
+	a := l.Resource.Armament[index]
+	if a.Location.Location == location {
+		return
+	}
+	a.Location = ModelLocation{location}
+	a.Offset = l.locationOffset(location)
+}
+
+// locationOffset finds the pixel offset for the weapon projectiles to spawn from in the location
+func (l *MechLoadout) locationOffset(location Location) [2]int {
+	if offset, ok := l.offsets[location]; ok {
+		return offset
+	}
+	if mirror, ok := mechMirrorLocations[location]; ok {
+		if offset, ok := l.offsets[mirror]; ok {
+			return [2]int{-offset[0], offset[1]}
+		}
+	}
+	return [2]int{0, l.Resource.CockpitPxOffset[1]}
+}
+
+// Save validates and writes the loadout as a new mech unit in the mods directory, then loads it into the resources
+func (l *MechLoadout) Save() (string, error) {
+	if strings.TrimSpace(l.Resource.Variant) == "" {
+		return "", fmt.Errorf("variant name is required")
+	}
+	if err := l.Validate(); err != nil {
+		return "", err
+	}
+
+	unit := variantFileRegexp.ReplaceAllString(strings.ToLower(l.Resource.Name+"_"+l.Resource.Variant), "_")
+	fileName := unit + YAMLExtension
+	filePath := path.Join(UnitsResourceType, MechResourceType, fileName)
+	if _, ok := l.resources.Mechs[unit]; ok && !resources.IsModFile(filePath) {
+		return "", fmt.Errorf("cannot replace stock unit %s", unit)
+	}
+
+	unitYaml, err := yaml.Marshal(l.Resource)
+	if err != nil {
+		return "", err
+	}
+	if _, err := resources.WriteModFile(filePath, unitYaml); err != nil {
+		return "", err
+	}
+
+	if _, err := l.resources.LoadMechResource(fileName); err != nil {
+		return "", err
+	}
+	return unit, nil
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"math"
 	"path"
 
 	"github.com/pixelmek-3d/pixelmek-3d/game/resources"
@@ -18,6 +19,13 @@ import (
 type Mission struct {
 	missionMap   *Map
 	missionTimer *stopwatch.Stopwatch
+	tickTimer    bool
+	timerTicks   uint
+	timerOffset  float64
+
+	// mech units of lancemates added when launched, which are not from the mission file
+	addedLancemates []string
+
 	Title        string              `yaml:"title" validate:"required"`
 	Briefing     string              `yaml:"briefing" validate:"required"`
 	MapPath      string              `yaml:"map" validate:"required"`
@@ -33,12 +41,21 @@ type Mission struct {
 	Infantry     []MissionUnit       `yaml:"infantry"`
 	VTOLs        []MissionFlyingUnit `yaml:"vtols"`
 	Emplacements []MissionStaticUnit `yaml:"emplacements"`
+	Lances       []*MissionLance     `yaml:"lances" validate:"omitempty,dive"`
+	Lancemates   []MissionUnit       `yaml:"lancemates"`
+	Triggers     []*MissionTrigger   `yaml:"triggers" validate:"omitempty,dive"`
+
+	// Script is a Starlark file, relative to the scripts resources folder, with callbacks for mission events
+	Script string `yaml:"script"`
 
 	// AI Pathing is initialized after map is loaded
 	Pathing *Pathing `yaml:"-"`
 
 	// spawnPoints used only in Instant Action for now
 	SpawnPoints []*SpawnPoint `yaml:"-"`
+
+	// File is the mission file name it was loaded from, empty for generated missions
+	File string `yaml:"-"`
 }
 
 func NewMissionFromMapPath(mapPath string) (*Mission, error) {
@@ -80,7 +97,27 @@ func (m *Mission) TimerStart() {
 }
 
 func (m *Mission) TimerSeconds() float64 {
-	return m.missionTimer.Seconds()
+	if m.tickTimer {
+		return m.timerOffset + float64(m.timerTicks)*SECONDS_PER_TICK
+	}
+	return m.timerOffset + m.missionTimer.Seconds()
+}
+
+// SetTimerSeconds sets the current mission time, such as when restoring a saved game
+func (m *Mission) SetTimerSeconds(seconds float64) {
+	m.timerOffset += seconds - m.TimerSeconds()
+}
+
+// SetTickTimer sets the mission timer to count game ticks instead of real time, such as for headless simulation
+func (m *Mission) SetTickTimer(tickTimer bool) {
+	m.tickTimer = tickTimer
+}
+
+// TimerTick advances the mission timer by one game tick, only when counting game ticks
+func (m *Mission) TimerTick() {
+	if m.tickTimer {
+		m.timerTicks++
+	}
 }
 
 type DropZone struct {
@@ -96,12 +133,18 @@ type SpawnPoint struct {
 type MissionObjectives struct {
 	Destroy []*MissionDestroyObjectives `yaml:"destroy"`
 	Protect []*MissionProtectObjectives `yaml:"protect"`
+	Survive []*MissionSurviveObjectives `yaml:"survive" validate:"omitempty,dive"`
+	Escort  []*MissionEscortObjectives  `yaml:"escort" validate:"omitempty,dive"`
 	Nav     *MissionNavObjectives       `yaml:"nav"`
 }
 
+// Bonus objectives are optional, they are not needed to complete the mission and do not fail it
+
 type MissionDestroyObjectives struct {
-	All  bool   `yaml:"all,omitempty"`
-	Unit string `yaml:"unit,omitempty"`
+	ID    string `yaml:"id,omitempty"`
+	All   bool   `yaml:"all,omitempty"`
+	Unit  string `yaml:"unit,omitempty"`
+	Bonus bool   `yaml:"bonus,omitempty"`
 
 	// Enemy waves objective used only in Instant Action for now
 	Waves *UnitWaves `yaml:"-"`
@@ -112,20 +155,62 @@ type UnitWaves struct {
 }
 
 type MissionProtectObjectives struct {
-	Unit string `yaml:"unit,omitempty"`
+	ID    string `yaml:"id,omitempty"`
+	Unit  string `yaml:"unit,omitempty"`
+	Bonus bool   `yaml:"bonus,omitempty"`
+}
+
+// MissionSurviveObjectives is to survive, or hold out, for a number of seconds since the objective was given
+type MissionSurviveObjectives struct {
+	ID      string  `yaml:"id,omitempty"`
+	Seconds float64 `yaml:"seconds" validate:"gt=0"`
+	Bonus   bool    `yaml:"bonus,omitempty"`
+}
+
+// MissionEscortObjectives is to keep a unit alive while it moves along an optional path to a nav point
+type MissionEscortObjectives struct {
+	ID    string       `yaml:"id,omitempty"`
+	Unit  string       `yaml:"unit" validate:"required"`
+	Nav   string       `yaml:"nav" validate:"required"`
+	Path  [][2]float64 `yaml:"path,omitempty"`
+	Bonus bool         `yaml:"bonus,omitempty"`
 }
 
 type MissionNavObjectives struct {
 	Visit   []*MissionNavVisit   `yaml:"visit,omitempty"`
 	Dustoff []*MissionNavDustoff `yaml:"dustoff,omitempty"`
+	Defend  []*MissionNavDefend  `yaml:"defend,omitempty" validate:"omitempty,dive"`
+	Capture []*MissionNavCapture `yaml:"capture,omitempty" validate:"omitempty,dive"`
 }
 
 type MissionNavVisit struct {
-	Name string `yaml:"name" validate:"required"`
+	ID    string `yaml:"id,omitempty"`
+	Name  string `yaml:"name" validate:"required"`
+	Bonus bool   `yaml:"bonus,omitempty"`
 }
 
 type MissionNavDustoff struct {
-	Name string `yaml:"name" validate:"required"`
+	ID    string `yaml:"id,omitempty"`
+	Name  string `yaml:"name" validate:"required"`
+	Bonus bool   `yaml:"bonus,omitempty"`
+}
+
+// MissionNavDefend is to keep all enemies out of the area around a nav point for a number of seconds
+type MissionNavDefend struct {
+	ID      string  `yaml:"id,omitempty"`
+	Name    string  `yaml:"name" validate:"required"`
+	Radius  float64 `yaml:"radius" validate:"gt=0"`
+	Seconds float64 `yaml:"seconds" validate:"gt=0"`
+	Bonus   bool    `yaml:"bonus,omitempty"`
+}
+
+// MissionNavCapture is to occupy the area around a nav point, without any enemies inside, for a number of seconds
+type MissionNavCapture struct {
+	ID      string  `yaml:"id,omitempty"`
+	Name    string  `yaml:"name" validate:"required"`
+	Radius  float64 `yaml:"radius" validate:"gt=0"`
+	Seconds float64 `yaml:"seconds" validate:"gt=0"`
+	Bonus   bool    `yaml:"bonus,omitempty"`
 }
 
 type MissionGuardArea struct {
@@ -151,6 +236,7 @@ type MissionUnit struct {
 	PatrolPath [][2]float64     `yaml:"patrolPath"`
 	GuardArea  MissionGuardArea `yaml:"guardArea"`
 	GuardUnit  string           `yaml:"guardUnit"`
+	Pilot      *MissionPilot    `yaml:"pilot,omitempty"`
 
 	PowerConditions UnitPowerConditions `yaml:"powerConditions"`
 }
@@ -177,6 +263,7 @@ type MissionFlyingUnit struct {
 	PatrolPath [][2]float64     `yaml:"patrolPath"`
 	GuardArea  MissionGuardArea `yaml:"guardArea"`
 	GuardUnit  string           `yaml:"guardUnit"`
+	Pilot      *MissionPilot    `yaml:"pilot,omitempty"`
 
 	PowerConditions UnitPowerConditions `yaml:"powerConditions"`
 }
@@ -194,11 +281,12 @@ type MissionStaticUnitModels interface {
 }
 
 type MissionStaticUnit struct {
-	ID       string     `yaml:"id"`
-	Team     int        `yaml:"team"`
-	Unit     string     `yaml:"unit" validate:"required"`
-	Position [2]float64 `yaml:"position" validate:"required"`
-	Heading  float64    `yaml:"heading"`
+	ID       string        `yaml:"id"`
+	Team     int           `yaml:"team"`
+	Unit     string        `yaml:"unit" validate:"required"`
+	Position [2]float64    `yaml:"position" validate:"required"`
+	Heading  float64       `yaml:"heading"`
+	Pilot    *MissionPilot `yaml:"pilot,omitempty"`
 }
 
 func (m MissionStaticUnit) GetUnit() string {
@@ -215,6 +303,8 @@ const (
 	NavNonObjective NavObjective = iota
 	NavVisitObjective
 	NavDustoffObjective
+	NavDefendObjective
+	NavCaptureObjective
 )
 
 type NavPoint struct {
@@ -264,6 +354,7 @@ func LoadMission(missionFile string) (*Mission, error) {
 	}
 
 	m := newMission()
+	m.File = missionFile
 	err = yaml.Unmarshal(missionYaml, m)
 	if err != nil {
 		return nil, err
@@ -274,8 +365,21 @@ func LoadMission(missionFile string) (*Mission, error) {
 		return nil, fmt.Errorf("[%s] %s", missionPath, err.Error())
 	}
 
-	// TODO: verify things that reference id/names of other things in the mission yaml, such as:
-	//       navPointVisited is defined as a navPoint name, and unitDestroyed defined as a unit id
+	err = m.initLances()
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %s", missionPath, err.Error())
+	}
+
+	// mission unit lists are not validated with dive, so validate unit pilots separately
+	for _, pilot := range m.missionPilots() {
+		err = v.Struct(pilot)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] %s", missionPath, err.Error())
+		}
+	}
+
+	// references to id/names of other things in the mission yaml, such as navPointVisited
+	// and unitDestroyed, are verified separately by Validate since they require model resources
 
 	// load mission map
 	err = m.loadMissionMap()
@@ -283,9 +387,47 @@ func LoadMission(missionFile string) (*Mission, error) {
 		return nil, err
 	}
 
+	// lancemates are placed around the drop zone, which may only be known from the map
+	m.initLancemates()
+
 	return m, nil
 }
 
+// missionPilots returns the pilots given for any of the mission units
+func (m *Mission) missionPilots() []*MissionPilot {
+	pilots := make([]*MissionPilot, 0)
+	for _, units := range [][]MissionUnit{m.Mechs, m.Vehicles, m.Infantry} {
+		for _, u := range units {
+			if u.Pilot != nil {
+				pilots = append(pilots, u.Pilot)
+			}
+		}
+	}
+	for _, lance := range m.Lances {
+		for _, u := range lance.Units() {
+			if u.Pilot != nil {
+				pilots = append(pilots, u.Pilot)
+			}
+		}
+	}
+	for _, u := range m.Lancemates {
+		if u.Pilot != nil {
+			pilots = append(pilots, u.Pilot)
+		}
+	}
+	for _, u := range m.VTOLs {
+		if u.Pilot != nil {
+			pilots = append(pilots, u.Pilot)
+		}
+	}
+	for _, u := range m.Emplacements {
+		if u.Pilot != nil {
+			pilots = append(pilots, u.Pilot)
+		}
+	}
+	return pilots
+}
+
 func (m *Mission) loadMissionMap() error {
 	if m.missionMap == nil {
 		var err error
@@ -381,31 +523,59 @@ func (o *MissionObjectives) Text() string {
 	if len(o.Destroy) > 0 {
 		for _, destroy := range o.Destroy {
 			if destroy.All {
-				oText += "Destroy All Enemies\n"
+				oText += "Destroy All Enemies" + ObjectiveBonusText(destroy.Bonus) + "\n"
 				break
 			}
-			oText += "Destroy " + destroy.Unit + "\n"
+			oText += "Destroy " + destroy.Unit + ObjectiveBonusText(destroy.Bonus) + "\n"
 		}
 	}
 
 	if len(o.Protect) > 0 {
 		for _, protect := range o.Protect {
-			oText += "Protect " + protect.Unit + "\n"
+			oText += "Protect " + protect.Unit + ObjectiveBonusText(protect.Bonus) + "\n"
 		}
 	}
 
+	for _, escort := range o.Escort {
+		oText += "Escort " + escort.Unit + " to Nav " + escort.Nav + ObjectiveBonusText(escort.Bonus) + "\n"
+	}
+
 	if o.Nav != nil {
 		if len(o.Nav.Visit) > 0 {
 			for _, visit := range o.Nav.Visit {
-				oText += "Visit Nav " + visit.Name + "\n"
+				oText += "Visit Nav " + visit.Name + ObjectiveBonusText(visit.Bonus) + "\n"
 			}
 		}
+		for _, defend := range o.Nav.Defend {
+			oText += "Defend Nav " + defend.Name + " for " + ObjectiveTimeText(defend.Seconds) + ObjectiveBonusText(defend.Bonus) + "\n"
+		}
+		for _, capture := range o.Nav.Capture {
+			oText += "Capture Nav " + capture.Name + ObjectiveBonusText(capture.Bonus) + "\n"
+		}
 		if len(o.Nav.Dustoff) > 0 {
 			for _, dustoff := range o.Nav.Dustoff {
-				oText += "Dustoff Nav " + dustoff.Name + "\n"
+				oText += "Dustoff Nav " + dustoff.Name + ObjectiveBonusText(dustoff.Bonus) + "\n"
 			}
 		}
 	}
 
+	for _, survive := range o.Survive {
+		oText += "Survive for " + ObjectiveTimeText(survive.Seconds) + ObjectiveBonusText(survive.Bonus) + "\n"
+	}
+
 	return oText
 }
+
+// ObjectiveBonusText returns the text appended to the text of optional bonus objectives
+func ObjectiveBonusText(bonus bool) string {
+	if bonus {
+		return " (Bonus)"
+	}
+	return ""
+}
+
+// ObjectiveTimeText returns the text of a number of seconds for objectives, in minutes:seconds (e.g. `2:30`)
+func ObjectiveTimeText(seconds float64) string {
+	s := int(math.Ceil(seconds))
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
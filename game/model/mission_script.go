@@ -0,0 +1,32 @@
+package model
+
+import (
+	"path"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game/resources"
+	"go.starlark.net/syntax"
+)
+
+// ScriptFileOptions are the Starlark language options allowed in mission scripts
+var ScriptFileOptions = &syntax.FileOptions{
+	Set:             true,
+	While:           true,
+	TopLevelControl: true,
+	GlobalReassign:  true,
+}
+
+// LoadMissionScript returns the source of a mission script, relative to the scripts resources folder,
+// after checking that it can be parsed
+func LoadMissionScript(scriptFile string) ([]byte, error) {
+	scriptPath := path.Join("scripts", scriptFile)
+	src, err := resources.ReadFile(scriptPath)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = ScriptFileOptions.Parse(scriptPath, src, 0)
+	if err != nil {
+		return nil, err
+	}
+	return src, nil
+}
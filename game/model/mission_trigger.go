@@ -0,0 +1,66 @@
+package model
+
+// MissionTrigger performs its actions once when all of its set conditions are met
+type MissionTrigger struct {
+	ID         string                   `yaml:"id"`
+	Conditions MissionTriggerConditions `yaml:"conditions"`
+	Actions    []*MissionTriggerAction  `yaml:"actions" validate:"required,dive"`
+}
+
+// MissionTriggerConditions defines the conditions of a trigger, all of which that are set need to be met
+type MissionTriggerConditions struct {
+	// TimeElapsed is the number of seconds since the mission started
+	TimeElapsed float64 `yaml:"timeElapsed" validate:"gte=0"`
+	// UnitDestroyed is the ID of the Unit that needs to be destroyed
+	UnitDestroyed string `yaml:"unitDestroyed"`
+	// NavPointVisited is the name of the Nav Point the player needs to have visited
+	NavPointVisited string `yaml:"navPointVisited"`
+	// AreaEntered is the area a unit (or the player, if no unit ID) needs to be inside of
+	AreaEntered *MissionTriggerArea `yaml:"areaEntered"`
+	// UnitHealth is the health percent a unit (or the player, if no unit ID) needs to be below
+	UnitHealth *MissionTriggerUnitHealth `yaml:"unitHealth"`
+	// ObjectiveCompleted is the ID of the objective that needs to be completed
+	ObjectiveCompleted string `yaml:"objectiveCompleted"`
+}
+
+type MissionTriggerArea struct {
+	Unit     string     `yaml:"unit"`
+	Position [2]float64 `yaml:"position" validate:"required"`
+	Radius   float64    `yaml:"radius" validate:"gt=0"`
+}
+
+type MissionTriggerUnitHealth struct {
+	Unit  string  `yaml:"unit"`
+	Below float64 `yaml:"below" validate:"gt=0,lte=100"`
+}
+
+// MissionTriggerAction defines actions to perform when a trigger fires, all of which that are set are performed
+type MissionTriggerAction struct {
+	// Spawn are reinforcement units to add to the mission
+	Spawn *MissionReinforcements `yaml:"spawn"`
+	// AI changes the behavior tree of a unit
+	AI *MissionTriggerAI `yaml:"ai"`
+	// AddObjectives are new objectives to add to the mission
+	AddObjectives *MissionObjectives `yaml:"addObjectives"`
+	// CompleteObjective is the ID of an objective to mark as completed
+	CompleteObjective string `yaml:"completeObjective"`
+	// Message is text to show in the HUD banner for MessageSeconds (or a default duration)
+	Message        string  `yaml:"message"`
+	MessageSeconds float64 `yaml:"messageSeconds" validate:"gte=0"`
+	// Audio is a sound effect file to play, relative to the audio/sfx resources folder
+	Audio string `yaml:"audio"`
+	// Lighting changes the mission lighting
+	Lighting *MapLighting `yaml:"lighting"`
+}
+
+type MissionReinforcements struct {
+	Mechs    []MissionUnit       `yaml:"mechs"`
+	Vehicles []MissionUnit       `yaml:"vehicles"`
+	Infantry []MissionUnit       `yaml:"infantry"`
+	VTOLs    []MissionFlyingUnit `yaml:"vtols"`
+}
+
+type MissionTriggerAI struct {
+	Unit string `yaml:"unit" validate:"required"`
+	Tree string `yaml:"tree" validate:"required"`
+}
@@ -0,0 +1,382 @@
+package model
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game/resources"
+)
+
+// MissionValidationError describes a problem found in a mission file, such as a dangling reference
+type MissionValidationError struct {
+	File    string
+	Field   string
+	Message string
+}
+
+func (e *MissionValidationError) Error() string {
+	return fmt.Sprintf("[%s] %s: %s", e.File, e.Field, e.Message)
+}
+
+// missionUnitRef is the common view of any mission unit used for validation
+type missionUnitRef struct {
+	field      string
+	id         string
+	unit       string
+	hasUnit    bool
+	position   [2]float64
+	patrolPath [][2]float64
+	guardArea  *MissionGuardArea
+	guardUnit  string
+	power      *UnitPowerConditions
+}
+
+// Validate checks the mission for references to units, nav points and objectives that do not exist, duplicate IDs,
+// unit files not found in model resources, positions outside of the map, and patrol points inside of walls
+func (m *Mission) Validate(missionFile string, res *ModelResources) []*MissionValidationError {
+	missionPath := path.Join("missions", missionFile)
+	errs := make([]*MissionValidationError, 0)
+	addErr := func(field, format string, a ...any) {
+		errs = append(errs, &MissionValidationError{File: missionPath, Field: field, Message: fmt.Sprintf(format, a...)})
+	}
+
+	units := m.missionUnitRefs(res)
+
+	// unit IDs must be unique to be referenced
+	unitIDs := make(map[string]string, len(units))
+	for _, u := range units {
+		if len(u.id) == 0 {
+			continue
+		}
+		if prevField, ok := unitIDs[u.id]; ok {
+			addErr(u.field+".id", "duplicate unit ID '%s' also used by %s", u.id, prevField)
+			continue
+		}
+		unitIDs[u.id] = u.field
+	}
+
+	navNames := make(map[string]string, len(m.NavPoints))
+	for i, nav := range m.NavPoints {
+		field := fmt.Sprintf("navPoints[%d]", i)
+		if prevField, ok := navNames[nav.Name]; ok {
+			addErr(field+".name", "duplicate nav point name '%s' also used by %s", nav.Name, prevField)
+		} else {
+			navNames[nav.Name] = field
+		}
+		if !m.inMapBounds(nav.Position) {
+			addErr(field+".position", "position %v is outside of map bounds", nav.Position)
+		}
+	}
+
+	if m.DropZone != nil && !m.inMapBounds(m.DropZone.Position) {
+		addErr("dropZone.position", "position %v is outside of map bounds", m.DropZone.Position)
+	}
+
+	for _, u := range units {
+		if !u.hasUnit {
+			addErr(u.field+".unit", "unit file '%s' not found in model resources", u.unit)
+		}
+
+		if !m.inMapBounds(u.position) {
+			addErr(u.field+".position", "position %v is outside of map bounds", u.position)
+		}
+
+		for i, p := range u.patrolPath {
+			field := fmt.Sprintf("%s.patrolPath[%d]", u.field, i)
+			switch {
+			case !m.inMapBounds(p):
+				addErr(field, "patrol point %v is outside of map bounds", p)
+			case m.missionMap.IsWallAt(0, int(p[0]), int(p[1])):
+				addErr(field, "patrol point %v is inside of a wall", p)
+			}
+		}
+
+		if u.guardArea != nil && u.guardArea.Radius > 0 && !m.inMapBounds(u.guardArea.Position) {
+			addErr(u.field+".guardArea.position", "position %v is outside of map bounds", u.guardArea.Position)
+		}
+
+		if len(u.guardUnit) > 0 {
+			if _, ok := unitIDs[u.guardUnit]; !ok {
+				addErr(u.field+".guardUnit", "unit ID '%s' not found", u.guardUnit)
+			} else if u.guardUnit == u.id {
+				addErr(u.field+".guardUnit", "unit cannot guard itself '%s'", u.guardUnit)
+			}
+		}
+
+		if u.power != nil {
+			if len(u.power.NavPointVisited) > 0 {
+				if _, ok := navNames[u.power.NavPointVisited]; !ok {
+					addErr(u.field+".powerConditions.navPointVisited", "nav point '%s' not found", u.power.NavPointVisited)
+				}
+			}
+			if len(u.power.MissionUnitDestroyed) > 0 {
+				if _, ok := unitIDs[u.power.MissionUnitDestroyed]; !ok {
+					addErr(u.field+".powerConditions.unitDestroyed", "unit ID '%s' not found", u.power.MissionUnitDestroyed)
+				}
+			}
+		}
+	}
+
+	// objective IDs must be unique to be referenced by triggers
+	objectiveIDs := make(map[string]string)
+	addObjectiveID := func(field, id string) {
+		if len(id) == 0 {
+			return
+		}
+		if prevField, ok := objectiveIDs[id]; ok {
+			addErr(field+".id", "duplicate objective ID '%s' also used by %s", id, prevField)
+			return
+		}
+		objectiveIDs[id] = field
+	}
+
+	// number of objectives that are not optional bonus objectives
+	required := 0
+	addRequired := func(bonus bool) {
+		if !bonus {
+			required++
+		}
+	}
+
+	validateObjectives := func(field string, objectives *MissionObjectives) {
+		if objectives == nil {
+			return
+		}
+		validateNav := func(field, name string) {
+			if _, ok := navNames[name]; !ok {
+				addErr(field, "nav point '%s' not found", name)
+			}
+		}
+		for i, o := range objectives.Destroy {
+			oField := fmt.Sprintf("%s.destroy[%d]", field, i)
+			addObjectiveID(oField, o.ID)
+			addRequired(o.Bonus)
+			if len(o.Unit) > 0 {
+				if _, ok := unitIDs[o.Unit]; !ok {
+					addErr(oField+".unit", "unit ID '%s' not found", o.Unit)
+				}
+			}
+		}
+		for i, o := range objectives.Protect {
+			oField := fmt.Sprintf("%s.protect[%d]", field, i)
+			addObjectiveID(oField, o.ID)
+			addRequired(o.Bonus)
+			if len(o.Unit) > 0 {
+				if _, ok := unitIDs[o.Unit]; !ok {
+					addErr(oField+".unit", "unit ID '%s' not found", o.Unit)
+				}
+			}
+		}
+		for i, o := range objectives.Survive {
+			oField := fmt.Sprintf("%s.survive[%d]", field, i)
+			addObjectiveID(oField, o.ID)
+			addRequired(o.Bonus)
+		}
+		for i, o := range objectives.Escort {
+			oField := fmt.Sprintf("%s.escort[%d]", field, i)
+			addObjectiveID(oField, o.ID)
+			addRequired(o.Bonus)
+			if _, ok := unitIDs[o.Unit]; !ok {
+				addErr(oField+".unit", "unit ID '%s' not found", o.Unit)
+			}
+			validateNav(oField+".nav", o.Nav)
+			for j, p := range o.Path {
+				pField := fmt.Sprintf("%s.path[%d]", oField, j)
+				switch {
+				case !m.inMapBounds(p):
+					addErr(pField, "path point %v is outside of map bounds", p)
+				case m.missionMap.IsWallAt(0, int(p[0]), int(p[1])):
+					addErr(pField, "path point %v is inside of a wall", p)
+				}
+			}
+		}
+		if objectives.Nav != nil {
+			for i, o := range objectives.Nav.Visit {
+				oField := fmt.Sprintf("%s.nav.visit[%d]", field, i)
+				addObjectiveID(oField, o.ID)
+				addRequired(o.Bonus)
+				validateNav(oField+".name", o.Name)
+			}
+			for i, o := range objectives.Nav.Dustoff {
+				oField := fmt.Sprintf("%s.nav.dustoff[%d]", field, i)
+				addObjectiveID(oField, o.ID)
+				addRequired(o.Bonus)
+				validateNav(oField+".name", o.Name)
+			}
+			for i, o := range objectives.Nav.Defend {
+				oField := fmt.Sprintf("%s.nav.defend[%d]", field, i)
+				addObjectiveID(oField, o.ID)
+				addRequired(o.Bonus)
+				validateNav(oField+".name", o.Name)
+			}
+			for i, o := range objectives.Nav.Capture {
+				oField := fmt.Sprintf("%s.nav.capture[%d]", field, i)
+				addObjectiveID(oField, o.ID)
+				addRequired(o.Bonus)
+				validateNav(oField+".name", o.Name)
+			}
+		}
+	}
+
+	validateObjectives("objectives", m.Objectives)
+	for i, t := range m.Triggers {
+		for j, a := range t.Actions {
+			validateObjectives(fmt.Sprintf("triggers[%d].actions[%d].addObjectives", i, j), a.AddObjectives)
+		}
+	}
+	// missions without objectives default to destroying all enemy units, otherwise objectives added by triggers also count
+	if m.Objectives != nil && required == 0 {
+		addErr("objectives", "at least one objective must not be a bonus objective")
+	}
+
+	validateUnitID := func(field, id string) {
+		if len(id) > 0 {
+			if _, ok := unitIDs[id]; !ok {
+				addErr(field, "unit ID '%s' not found", id)
+			}
+		}
+	}
+
+	for i, t := range m.Triggers {
+		field := fmt.Sprintf("triggers[%d]", i)
+
+		c := t.Conditions
+		validateUnitID(field+".conditions.unitDestroyed", c.UnitDestroyed)
+		if len(c.NavPointVisited) > 0 {
+			if _, ok := navNames[c.NavPointVisited]; !ok {
+				addErr(field+".conditions.navPointVisited", "nav point '%s' not found", c.NavPointVisited)
+			}
+		}
+		if c.AreaEntered != nil {
+			validateUnitID(field+".conditions.areaEntered.unit", c.AreaEntered.Unit)
+			if !m.inMapBounds(c.AreaEntered.Position) {
+				addErr(field+".conditions.areaEntered.position", "position %v is outside of map bounds", c.AreaEntered.Position)
+			}
+		}
+		if c.UnitHealth != nil {
+			validateUnitID(field+".conditions.unitHealth.unit", c.UnitHealth.Unit)
+		}
+		if len(c.ObjectiveCompleted) > 0 {
+			if _, ok := objectiveIDs[c.ObjectiveCompleted]; !ok {
+				addErr(field+".conditions.objectiveCompleted", "objective ID '%s' not found", c.ObjectiveCompleted)
+			}
+		}
+
+		for j, a := range t.Actions {
+			aField := fmt.Sprintf("%s.actions[%d]", field, j)
+			if a.AI != nil {
+				validateUnitID(aField+".ai.unit", a.AI.Unit)
+				if _, err := resources.ReadFile(path.Join("ai", a.AI.Tree+".json")); err != nil {
+					addErr(aField+".ai.tree", "behavior tree '%s' not found", a.AI.Tree)
+				}
+			}
+			if len(a.CompleteObjective) > 0 {
+				if _, ok := objectiveIDs[a.CompleteObjective]; !ok {
+					addErr(aField+".completeObjective", "objective ID '%s' not found", a.CompleteObjective)
+				}
+			}
+			if len(a.Audio) > 0 {
+				if _, err := resources.ReadFile(path.Join("audio", "sfx", a.Audio)); err != nil {
+					addErr(aField+".audio", "audio file '%s' not found", a.Audio)
+				}
+			}
+		}
+	}
+
+	if len(m.Script) > 0 {
+		if _, err := LoadMissionScript(m.Script); err != nil {
+			addErr("script", "%s", err.Error())
+		}
+	}
+
+	return errs
+}
+
+// missionUnitRefs gathers all mission units with their field path in the mission file
+func (m *Mission) missionUnitRefs(res *ModelResources) []*missionUnitRef {
+	units := make([]*missionUnitRef, 0, len(m.Mechs)+len(m.Vehicles)+len(m.Infantry)+len(m.VTOLs)+len(m.Emplacements))
+
+	addUnits := func(field string, missionUnits []MissionUnit, hasUnit func(string) bool) {
+		for i := range missionUnits {
+			u := &missionUnits[i]
+			units = append(units, &missionUnitRef{
+				field:      fmt.Sprintf("%s[%d]", field, i),
+				id:         u.ID,
+				unit:       u.Unit,
+				hasUnit:    hasUnit(u.Unit),
+				position:   u.Position,
+				patrolPath: u.PatrolPath,
+				guardArea:  &u.GuardArea,
+				guardUnit:  u.GuardUnit,
+				power:      &u.PowerConditions,
+			})
+		}
+	}
+
+	addUnits("mechs", m.Mechs, func(unit string) bool { _, ok := res.Mechs[unit]; return ok })
+	addUnits("vehicles", m.Vehicles, func(unit string) bool { _, ok := res.Vehicles[unit]; return ok })
+	addUnits("infantry", m.Infantry, func(unit string) bool { _, ok := res.Infantry[unit]; return ok })
+
+	addFlyingUnits := func(field string, missionUnits []MissionFlyingUnit) {
+		for i := range missionUnits {
+			u := &missionUnits[i]
+			_, hasUnit := res.VTOLs[u.Unit]
+			units = append(units, &missionUnitRef{
+				field:      fmt.Sprintf("%s[%d]", field, i),
+				id:         u.ID,
+				unit:       u.Unit,
+				hasUnit:    hasUnit,
+				position:   u.Position,
+				patrolPath: u.PatrolPath,
+				guardArea:  &u.GuardArea,
+				guardUnit:  u.GuardUnit,
+				power:      &u.PowerConditions,
+			})
+		}
+	}
+
+	addFlyingUnits("vtols", m.VTOLs)
+
+	for i, lance := range m.Lances {
+		field := fmt.Sprintf("lances[%d]", i)
+		addUnits(field+".mechs", lance.Mechs, func(unit string) bool { _, ok := res.Mechs[unit]; return ok })
+		addUnits(field+".vehicles", lance.Vehicles, func(unit string) bool { _, ok := res.Vehicles[unit]; return ok })
+		addUnits(field+".infantry", lance.Infantry, func(unit string) bool { _, ok := res.Infantry[unit]; return ok })
+	}
+
+	addUnits("lancemates", m.Lancemates, func(unit string) bool { _, ok := res.Mechs[unit]; return ok })
+
+	for i := range m.Emplacements {
+		u := &m.Emplacements[i]
+		_, hasUnit := res.Emplacements[u.Unit]
+		units = append(units, &missionUnitRef{
+			field:    fmt.Sprintf("emplacements[%d]", i),
+			id:       u.ID,
+			unit:     u.Unit,
+			hasUnit:  hasUnit,
+			position: u.Position,
+		})
+	}
+
+	// reinforcements spawned by triggers can also be referenced by ID
+	for i, t := range m.Triggers {
+		for j, a := range t.Actions {
+			if a.Spawn == nil {
+				continue
+			}
+			field := fmt.Sprintf("triggers[%d].actions[%d].spawn", i, j)
+			addUnits(field+".mechs", a.Spawn.Mechs, func(unit string) bool { _, ok := res.Mechs[unit]; return ok })
+			addUnits(field+".vehicles", a.Spawn.Vehicles, func(unit string) bool { _, ok := res.Vehicles[unit]; return ok })
+			addUnits(field+".infantry", a.Spawn.Infantry, func(unit string) bool { _, ok := res.Infantry[unit]; return ok })
+			addFlyingUnits(field+".vtols", a.Spawn.VTOLs)
+		}
+	}
+
+	return units
+}
+
+// inMapBounds returns true if the position is within the bounds of the mission map
+func (m *Mission) inMapBounds(pos [2]float64) bool {
+	w, h := m.missionMap.Size()
+	return pos[0] >= 0 && pos[1] >= 0 && pos[0] < float64(w) && pos[1] < float64(h)
+}
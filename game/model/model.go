@@ -63,6 +63,8 @@ const (
 	LEFT
 	RIGHT
 	TURRET
+	REAR
+	ROTOR
 )
 
 var locationNames = map[Location]string{
@@ -78,6 +80,8 @@ var locationNames = map[Location]string{
 	LEFT:         "left",
 	RIGHT:        "right",
 	TURRET:       "turret",
+	REAR:         "rear",
+	ROTOR:        "rotor",
 }
 
 type ModelLocation struct {
@@ -305,6 +309,30 @@ func (a *AmmoBin) ForWeapon() Weapon {
 	return a.forWeapon
 }
 
+// Location returns the location the ammo bin is stored, same as the weapon it was added for
+func (a *AmmoBin) Location() Location {
+	if a.forWeapon == nil {
+		return 0
+	}
+	return a.forWeapon.Location()
+}
+
+// ExplosionDamage returns the damage caused by all remaining ammo exploding
+func (a *AmmoBin) ExplosionDamage() float64 {
+	if a.forWeapon == nil || a.ammoCount <= 0 {
+		return 0
+	}
+
+	damagePerAmmo := a.forWeapon.Damage()
+	switch a.ammoType {
+	case AMMO_BALLISTIC:
+		// ballistic consume one ammo count per burst
+	default:
+		damagePerAmmo /= float64(a.forWeapon.ProjectileCount())
+	}
+	return damagePerAmmo * float64(a.ammoCount)
+}
+
 // ConsumeAmmo consumes the ammo count of weapon fired N times
 func (a *AmmoBin) ConsumeAmmo(forWeapon Weapon, consumeN int) {
 	if forWeapon == nil {
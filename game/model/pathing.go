@@ -2,17 +2,65 @@ package model
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/harbdog/go-astar"
 	"github.com/harbdog/raycaster-go/geom"
 	"github.com/pixelmek-3d/pixelmek-3d/game/common/bezier"
 )
 
+const (
+	// extra movement cost of the default flooring when not set by the map, so that flooring paths are preferred
+	PATHING_FLOOR_COST float64 = 0.2
+	// extra movement cost of a cell where clutter always appears
+	PATHING_CLUTTER_COST float64 = 0.5
+	// maximum number of trees in a cell counted towards its forest movement cost
+	PATHING_MAX_FOREST float64 = 4
+	// height in levels between neighboring cells that ground units can climb, such as up ramps
+	PATHING_MAX_CLIMB float64 = 0.5
+	// height in levels that units with jump jets can jump up onto, and the extra movement cost to do so
+	PATHING_JUMP_CLIMB float64 = 1.0
+	PATHING_JUMP_COST  float64 = 2.0
+)
+
 type Pathing struct {
 	// TODO: refactor Map/Mission to use Tile/astar.Pather interface
 	world TileWorld
 }
 
+// PathingCosts are the multipliers a unit type applies to the extra movement costs of terrain and forests,
+// and the height it can climb between neighboring cells
+type PathingCosts struct {
+	Terrain float64
+	Forest  float64
+	Climb   float64
+}
+
+var unitPathingCosts = map[UnitType]PathingCosts{
+	MechUnitType:     {Terrain: 1, Forest: 1, Climb: PATHING_MAX_CLIMB},
+	VehicleUnitType:  {Terrain: 2, Forest: 4, Climb: PATHING_MAX_CLIMB},
+	InfantryUnitType: {Terrain: 0.5, Forest: 0.1, Climb: PATHING_MAX_CLIMB},
+	// flying units are not slowed down by the ground they pass over
+	VTOLUnitType: {Terrain: 0, Forest: 0, Climb: math.Inf(1)},
+}
+
+// UnitTypePathingCosts returns the terrain movement cost multipliers for the unit type
+func UnitTypePathingCosts(unitType UnitType) PathingCosts {
+	if costs, ok := unitPathingCosts[unitType]; ok {
+		return costs
+	}
+	return unitPathingCosts[MechUnitType]
+}
+
+// UnitPathingCosts returns the terrain movement cost multipliers for the unit, which can climb higher with jump jets
+func UnitPathingCosts(u Unit) PathingCosts {
+	costs := UnitTypePathingCosts(u.UnitType())
+	if u.JumpJets() > 0 {
+		costs.Climb = math.Max(costs.Climb, PATHING_JUMP_CLIMB)
+	}
+	return costs
+}
+
 // TileWorld is a two dimensional map of Tiles.
 type TileWorld map[int]map[int]*Tile
 
@@ -30,6 +78,12 @@ type Tile struct {
 	X, Y int
 	// W is a reference to the World that the tile is a part of.
 	W TileWorld
+	// Terrain is the extra movement cost of the tile from flooring and clutter, or the map override.
+	Terrain float64
+	// Forest is the number of trees and other map sprites on the tile.
+	Forest float64
+	// Height is the elevation of the tile in levels, from walkable walls and ramps.
+	Height float64
 }
 
 // Tile gets the tile at the given coordinates in the world.
@@ -51,63 +105,202 @@ func (w TileWorld) SetTile(t *Tile, x, y int) {
 	t.W = w
 }
 
-// PathNeighbors returns the neighbors of the tile, excluding blockers and
-// tiles off the edge of the board.
-func (t *Tile) PathNeighbors() []astar.Pather {
-	neighbors := []astar.Pather{}
-	for _, offset := range [][]int{
-		{-1, 0},
-		{1, 0},
-		{0, -1},
-		{0, 1},
+// passable returns true if the tile exists and is not a blocker.
+func (t *Tile) passable() bool {
+	return t != nil && t.Kind != TileKindBlocker
+}
+
+// unitTile is a Tile as pathed by a unit type, so each unit type can have different movement costs.
+type unitTile struct {
+	*Tile
+	costs *PathingCosts
+}
+
+// PathNeighbors returns the neighbors of the tile in all 8 directions, excluding blockers, tiles too
+// high to climb, tiles off the edge of the board, and diagonals that would cut the corner of a blocker.
+func (t unitTile) PathNeighbors() []astar.Pather {
+	neighbors := make([]astar.Pather, 0, 8)
+	for _, offset := range [][2]int{
+		{-1, 0}, {1, 0}, {0, -1}, {0, 1},
+		{-1, -1}, {-1, 1}, {1, -1}, {1, 1},
 	} {
-		if n := t.W.Tile(t.X+offset[0], t.Y+offset[1]); n != nil && n.Kind != TileKindBlocker {
-			neighbors = append(neighbors, n)
+		n := t.W.Tile(t.X+offset[0], t.Y+offset[1])
+		if !t.canMoveTo(n) {
+			continue
+		}
+		if offset[0] != 0 && offset[1] != 0 {
+			// only move diagonally if both tiles beside the diagonal can also be moved to
+			if !t.canMoveTo(t.W.Tile(t.X+offset[0], t.Y)) || !t.canMoveTo(t.W.Tile(t.X, t.Y+offset[1])) {
+				continue
+			}
 		}
+		neighbors = append(neighbors, unitTile{Tile: n, costs: t.costs})
 	}
 	return neighbors
 }
 
-// PathNeighborCost returns the movement cost of the directly neighboring tile.
-func (t *Tile) PathNeighborCost(to astar.Pather) float64 {
-	return 1.0 // TODO: implement differing costs for non-plain tile kinds (i.e. water, rough terrain)
+// canMoveTo returns true if the tile is passable and not too high to climb up to from this tile.
+func (t unitTile) canMoveTo(to *Tile) bool {
+	return to.passable() && to.Height-t.Height <= t.costs.Climb
 }
 
-// PathEstimatedCost uses Manhattan distance to estimate orthogonal distance
-// between non-adjacent nodes.
-func (t *Tile) PathEstimatedCost(to astar.Pather) float64 {
-	toT := to.(*Tile)
-	absX := toT.X - t.X
-	if absX < 0 {
-		absX = -absX
+// PathNeighborCost returns the movement cost of the directly neighboring tile,
+// based on the distance to it, its terrain and forest costs for the unit type,
+// and whether it needs to be jumped up onto.
+func (t unitTile) PathNeighborCost(to astar.Pather) float64 {
+	toT := to.(unitTile)
+	dist := 1.0
+	if toT.X != t.X && toT.Y != t.Y {
+		dist = math.Sqrt2
 	}
-	absY := toT.Y - t.Y
-	if absY < 0 {
-		absY = -absY
+	forest := math.Min(toT.Forest, PATHING_MAX_FOREST)
+	cost := dist * (1 + toT.Terrain*t.costs.Terrain + forest*t.costs.Forest)
+	if toT.Height-t.Height > PATHING_MAX_CLIMB {
+		cost += PATHING_JUMP_COST
 	}
-	return float64(absX + absY)
+	return cost
+}
+
+// PathEstimatedCost uses octile distance to estimate the distance
+// between non-adjacent nodes with diagonal movement.
+func (t unitTile) PathEstimatedCost(to astar.Pather) float64 {
+	toT := to.(unitTile)
+	absX := math.Abs(float64(toT.X - t.X))
+	absY := math.Abs(float64(toT.Y - t.Y))
+	return absX + absY + (math.Sqrt2-2)*math.Min(absX, absY)
 }
 
 func initPathing(m *Mission) *Pathing {
-	width, height := m.missionMap.Size()
+	return newMapPathing(m.missionMap)
+}
+
+// newMapPathing creates pathing from the walls of the first elevation level of the map, with terrain
+// movement costs from the map flooring, clutter and sprites, and heights from walkable walls and ramps
+func newMapPathing(m *Map) *Pathing {
+	width, height := m.Size()
 	w := TileWorld{}
 
-	level := m.missionMap.Level(0)
+	terrain := m.terrainCosts()
+	forest := m.forestDensity()
+
+	level := m.Level(0)
 	for x := 0; x < width; x++ {
 		for y := 0; y < height; y++ {
 			cell := level[x][y]
 
 			kind := TileKindPlain
-			if cell != 0 {
+			if cell != 0 && !m.IsWalkableWall(x, y) {
 				kind = TileKindBlocker
 			}
-			w.SetTile(&Tile{Kind: kind}, x, y)
+			tile := &Tile{Kind: kind, Terrain: terrain[x][y], Forest: forest[x][y], Height: m.HeightAt(float64(x)+0.5, float64(y)+0.5)}
+			w.SetTile(tile, x, y)
 		}
 	}
 
 	return &Pathing{world: w}
 }
 
+// terrainCosts returns the extra movement cost of each cell from its flooring and the clutter that
+// appears on it, unless set by the map pathing costs override
+func (m *Map) terrainCosts() [][]float64 {
+	width, height := m.Size()
+
+	// index of flooring path of each cell, in the same order the flooring textures are applied
+	floorIndex := make([][]int, width)
+	for x := range floorIndex {
+		floorIndex[x] = make([]int, height)
+		for y := range floorIndex[x] {
+			floorIndex[x][y] = -1
+		}
+	}
+	setFloor := func(x, y, index int) {
+		if x >= 0 && y >= 0 && x < width && y < height {
+			floorIndex[x][y] = index
+		}
+	}
+	for i, pathing := range m.Flooring.Pathing {
+		for _, rect := range pathing.Rects {
+			for x := rect[0][0]; x <= rect[1][0]; x++ {
+				for y := rect[0][1]; y <= rect[1][1]; y++ {
+					setFloor(x, y, i)
+				}
+			}
+		}
+		for _, segments := range pathing.Lines {
+			for j := 1; j < len(segments); j++ {
+				line := geom.Line{
+					X1: float64(segments[j-1][0]), Y1: float64(segments[j-1][1]),
+					X2: float64(segments[j][0]), Y2: float64(segments[j][1]),
+				}
+				angle, dist := line.Angle(), line.Distance()
+				for d := 0.0; d <= dist; d += 0.1 {
+					nLine := geom.LineFromAngle(line.X1, line.Y1, angle, d)
+					setFloor(int(nLine.X2), int(nLine.Y2), i)
+				}
+			}
+		}
+	}
+
+	// extra cost of each flooring, the last being the default flooring
+	numFloors := len(m.Flooring.Pathing)
+	floorCosts := make([]float64, numFloors+1)
+	floorImages := make([]string, numFloors+1)
+	for i, pathing := range m.Flooring.Pathing {
+		floorCosts[i], floorImages[i] = pathing.Cost, pathing.Image
+	}
+	floorCosts[numFloors], floorImages[numFloors] = PATHING_FLOOR_COST, m.Flooring.Default
+	if m.Flooring.Cost != nil {
+		floorCosts[numFloors] = *m.Flooring.Cost
+	}
+
+	// expected clutter on each flooring based on the clutter frequency
+	for i, image := range floorImages {
+		for _, clutter := range m.Clutter {
+			if clutter.FloorPathMatch == nil || clutter.FloorPathMatch.MatchString(image) {
+				floorCosts[i] += math.Min(clutter.Frequency, 1) * PATHING_CLUTTER_COST
+			}
+		}
+	}
+
+	costs := make([][]float64, width)
+	for x := range costs {
+		costs[x] = make([]float64, height)
+		for y := range costs[x] {
+			switch {
+			case len(m.PathingCosts) > 0 && m.PathingCosts[x][y] >= 0:
+				costs[x][y] = m.PathingCosts[x][y]
+			case floorIndex[x][y] < 0:
+				costs[x][y] = floorCosts[numFloors]
+			default:
+				costs[x][y] = floorCosts[floorIndex[x][y]]
+			}
+		}
+	}
+	return costs
+}
+
+// forestDensity returns the number of trees and other destructible or collidable map sprites in each cell
+func (m *Map) forestDensity() [][]float64 {
+	width, height := m.Size()
+	density := make([][]float64, width)
+	for x := range density {
+		density[x] = make([]float64, height)
+	}
+
+	for _, sprite := range m.Sprites {
+		if sprite.CollisionPxRadius <= 0 && sprite.HitPoints <= 0 {
+			continue
+		}
+		for _, pos := range sprite.Positions {
+			x, y := int(pos[0]), int(pos[1])
+			if x >= 0 && y >= 0 && x < width && y < height {
+				density[x][y]++
+			}
+		}
+	}
+	return density
+}
+
 func PathToString(path []*geom.Vector2) string {
 	var pathStr string
 	pathCount := len(path)
@@ -120,10 +313,16 @@ func PathToString(path []*geom.Vector2) string {
 	return "[" + pathStr + "]"
 }
 
-func (p *Pathing) FindPath(startPos, finishPos *geom.Vector2) ([]*geom.Vector2, error) {
+// FindPath finds the path with the lowest movement cost between two positions using the unit pathing costs
+func (p *Pathing) FindPath(startPos, finishPos *geom.Vector2, costs PathingCosts) ([]*geom.Vector2, error) {
 	startTile := p.world.Tile(int(startPos.X), int(startPos.Y))
 	finishTile := p.world.Tile(int(finishPos.X), int(finishPos.Y))
-	path, _, found := astar.Path(startTile, finishTile)
+	if startTile == nil || finishTile == nil {
+		err := fmt.Errorf("unable to find path outside of map for (%0.0f,%0.0f) -> (%0.0f,%0.0f)", startPos.X, startPos.Y, finishPos.X, finishPos.Y)
+		return []*geom.Vector2{}, err
+	}
+
+	path, _, found := astar.Path(unitTile{Tile: startTile, costs: &costs}, unitTile{Tile: finishTile, costs: &costs})
 
 	steps := make([]*geom.Vector2, 0, len(path))
 	if !found {
@@ -133,7 +332,7 @@ func (p *Pathing) FindPath(startPos, finishPos *geom.Vector2) ([]*geom.Vector2,
 
 	// astar path returned in reverse order
 	for i := len(path) - 1; i >= 0; i-- {
-		t := path[i].(*Tile)
+		t := path[i].(unitTile)
 		x, y := float64(t.X)+0.5, float64(t.Y)+0.5
 		steps = append(steps, &geom.Vector2{X: x, Y: y})
 	}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"fmt"
+)
+
+const (
+	// pilot skills range from best (0) to worst (8), where lower is better
+	PILOT_SKILL_BEST  int = 0
+	PILOT_SKILL_WORST int = 8
+
+	// default pilot skills of a regular pilot
+	PILOT_GUNNERY_DEFAULT  int = 4
+	PILOT_PILOTING_DEFAULT int = 5
+)
+
+// Pilot is the warrior controlling a unit, whose skills affect how well the AI fights and maneuvers
+type Pilot struct {
+	Name     string
+	Gunnery  int
+	Piloting int
+}
+
+func NewPilot(name string, gunnery, piloting int) *Pilot {
+	return &Pilot{
+		Name:     name,
+		Gunnery:  clampSkill(gunnery),
+		Piloting: clampSkill(piloting),
+	}
+}
+
+// GunneryRating returns the gunnery skill as a ratio from 0 (worst) to 1 (best)
+func (p *Pilot) GunneryRating() float64 {
+	return skillRating(p.Gunnery)
+}
+
+// PilotingRating returns the piloting skill as a ratio from 0 (worst) to 1 (best)
+func (p *Pilot) PilotingRating() float64 {
+	return skillRating(p.Piloting)
+}
+
+func (p *Pilot) String() string {
+	if p.Name == "" {
+		return fmt.Sprintf("%d/%d", p.Gunnery, p.Piloting)
+	}
+	return fmt.Sprintf("%s (%d/%d)", p.Name, p.Gunnery, p.Piloting)
+}
+
+func skillRating(skill int) float64 {
+	return float64(PILOT_SKILL_WORST-clampSkill(skill)) / float64(PILOT_SKILL_WORST)
+}
+
+func clampSkill(skill int) int {
+	return max(PILOT_SKILL_BEST, min(skill, PILOT_SKILL_WORST))
+}
+
+// MissionPilot is the optional pilot of a mission unit, where skills not given use the difficulty level default
+type MissionPilot struct {
+	Name     string `yaml:"name,omitempty"`
+	Gunnery  *int   `yaml:"gunnery,omitempty" validate:"omitempty,gte=0,lte=8"`
+	Piloting *int   `yaml:"piloting,omitempty" validate:"omitempty,gte=0,lte=8"`
+}
+
+func (e *UnitModel) Pilot() *Pilot {
+	return e.pilot
+}
+
+func (e *UnitModel) SetPilot(pilot *Pilot) {
+	e.pilot = pilot
+}
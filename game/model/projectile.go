@@ -277,3 +277,38 @@ func (e *Projectile) Parent() Entity {
 func (e *Projectile) SetParent(parent Entity) {
 	e.parent = parent
 }
+
+// ProjectileState is the in-flight state of a projectile, used to save and restore a mission in progress
+type ProjectileState struct {
+	Position       [2]float64 `json:"position"`
+	PositionZ      float64    `json:"position_z"`
+	Heading        float64    `json:"heading"`
+	Pitch          float64    `json:"pitch"`
+	Velocity       float64    `json:"velocity"`
+	VelocityZ      float64    `json:"velocity_z"`
+	Lifespan       float64    `json:"lifespan"`
+	InExtremeRange bool       `json:"in_extreme_range"`
+}
+
+// ProjectileState returns the current in-flight state of the projectile
+func (e *Projectile) ProjectileState() *ProjectileState {
+	return &ProjectileState{
+		Position:       [2]float64{e.position.X, e.position.Y},
+		PositionZ:      e.positionZ,
+		Heading:        e.angle,
+		Pitch:          e.pitch,
+		Velocity:       e.velocity,
+		VelocityZ:      e.velocityZ,
+		Lifespan:       e.lifespan,
+		InExtremeRange: e.inExtremeRange,
+	}
+}
+
+// SetProjectileState restores the in-flight state of a projectile newly spawned from the same weapon
+func (e *Projectile) SetProjectileState(s *ProjectileState) {
+	e.position = &geom.Vector2{X: s.Position[0], Y: s.Position[1]}
+	e.positionZ = s.PositionZ
+	e.angle, e.pitch = s.Heading, s.Pitch
+	e.velocity, e.velocityZ = s.Velocity, s.VelocityZ
+	e.lifespan, e.inExtremeRange = s.Lifespan, s.InExtremeRange
+}
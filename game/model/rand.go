@@ -1,8 +1,14 @@
 package model
 
 import (
+	"cmp"
+	"maps"
 	"math/rand"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/harbdog/raycaster-go/geom"
 )
 
@@ -10,8 +16,80 @@ type Rand struct {
 	*rand.Rand
 }
 
+// sharedRand is the random source used for gameplay decisions and seeding new generators,
+// it can be reseeded so that the same seed reproduces the same game simulation
+type sharedRand struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+var (
+	_sharedRand = &sharedRand{rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+	// effects use a separate source so that cosmetic randomness does not change gameplay results
+	_effectsRand = &sharedRand{rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+)
+
+func (s *sharedRand) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Int63()
+}
+
+func (s *sharedRand) Float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64()
+}
+
+func (s *sharedRand) Intn(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Intn(n)
+}
+
+func (s *sharedRand) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Read(p)
+}
+
+// SetRandomSeed reseeds the shared gameplay and effects random sources for reproducible results
+func SetRandomSeed(seed int64) {
+	_sharedRand.mu.Lock()
+	_sharedRand.rng = rand.New(rand.NewSource(seed))
+	_sharedRand.mu.Unlock()
+
+	_effectsRand.mu.Lock()
+	_effectsRand.rng = rand.New(rand.NewSource(^seed))
+	_effectsRand.mu.Unlock()
+
+	// generated unit IDs also need to be reproducible since they are used for ordering
+	uuid.SetRand(_sharedRand)
+}
+
+// RandFloat64 returns a random number in [0.0,1.0) from the shared gameplay random source
+func RandFloat64() float64 {
+	return _sharedRand.Float64()
+}
+
+// RandIntn returns a random number in [0,n) from the shared gameplay random source
+func RandIntn(n int) int {
+	return _sharedRand.Intn(n)
+}
+
+// EffectsRandIntn returns a random number in [0,n) from the shared effects random source
+func EffectsRandIntn(n int) int {
+	return _effectsRand.Intn(n)
+}
+
 func NewRNG() *Rand {
-	return &Rand{Rand: rand.New(rand.NewSource(rand.Int63()))}
+	return &Rand{Rand: rand.New(rand.NewSource(_sharedRand.Int63()))}
+}
+
+// NewSeededRNG creates a random number generator from a fixed seed for reproducible results
+func NewSeededRNG(seed int64) *Rand {
+	return &Rand{Rand: rand.New(rand.NewSource(seed))}
 }
 
 func (rng *Rand) RandRelativeLocation(x, y, minDist, maxDist, xMax, yMax int) (int, int) {
@@ -29,38 +107,37 @@ func (rng *Rand) RandIntIn(lo, hi int) int {
 	return RandIntIn(lo, hi, rng.Rand)
 }
 
+// RandFloat64In returns a random number in [lo,hi) from the given generator,
+// or from the shared effects random source if rng is nil
 func RandFloat64In(lo, hi float64, rng *rand.Rand) float64 {
 	var randFloat float64
 	if rng == nil {
-		randFloat = rand.Float64()
+		randFloat = _effectsRand.Float64()
 	} else {
 		randFloat = rng.Float64()
 	}
 	return lo + (hi-lo)*randFloat
 }
 
+// RandIntIn returns a random number in [lo,hi) from the given generator,
+// or from the shared effects random source if rng is nil
 func RandIntIn(lo, hi int, rng *rand.Rand) int {
 	var randFloat float64
 	if rng == nil {
-		randFloat = rand.Float64()
+		randFloat = _effectsRand.Float64()
 	} else {
 		randFloat = rng.Float64()
 	}
 	return int(float64(lo) + float64(hi-lo)*randFloat)
 }
 
-func RandomMapKey[K comparable, V any](m map[K]V) K {
+func RandomMapKey[K cmp.Ordered, V any](m map[K]V) K {
 	var k K
 	if len(m) == 0 {
 		return k
 	}
 
-	r := rand.Intn(len(m))
-	for k = range m {
-		if r == 0 {
-			return k
-		}
-		r--
-	}
-	return k
+	// sort keys since map iteration order is not consistent
+	keys := slices.Sorted(maps.Keys(m))
+	return keys[RandIntn(len(keys))]
 }
@@ -31,6 +31,14 @@ const (
 	BallisticResourceType   string = "ballistic"
 )
 
+const (
+	BloodEffectType     string = "blood"
+	ExplosionEffectType string = "explosion"
+	FireEffectType      string = "fire"
+	SmokeEffectType     string = "smoke"
+	JumpJetEffectType   string = "jump_jet"
+)
+
 type ModelResources struct {
 	Mechs            map[string]*ModelMechResource
 	Vehicles         map[string]*ModelVehicleResource
@@ -40,69 +48,84 @@ type ModelResources struct {
 	EnergyWeapons    map[string]*ModelEnergyWeaponResource
 	MissileWeapons   map[string]*ModelMissileWeaponResource
 	BallisticWeapons map[string]*ModelBallisticWeaponResource
+	// effects are keyed by effect type folder and file name without extension, such as "explosion/01"
+	Effects    map[string]*ModelEffectResource
+	effectKeys map[string][]string
 }
 
 type ModelMechResource struct {
-	File              string                   `yaml:"-"`
-	Name              string                   `yaml:"name" validate:"required"`
-	Variant           string                   `yaml:"variant" validate:"required"`
-	Image             string                   `yaml:"image" validate:"required"`
-	Tech              ModelTech                `yaml:"tech" validate:"required"`
-	Tonnage           float64                  `yaml:"tonnage" validate:"gt=0,lte=200"`
-	Height            float64                  `yaml:"height" validate:"gt=0"`
-	HeightPxGap       int                      `yaml:"heightPixelGap" validate:"gte=0"`
-	Speed             float64                  `yaml:"speed" validate:"gt=0,lte=250"`
-	JumpJets          int                      `yaml:"jumpJets" validate:"gte=0,lte=20"`
-	Armor             float64                  `yaml:"armor" validate:"gte=0"`
-	Structure         float64                  `yaml:"structure" validate:"gt=0"`
-	CollisionPxRadius int                      `yaml:"collisionRadiusPx" validate:"gt=0"`
-	CollisionPxHeight int                      `yaml:"collisionHeightPx" validate:"gt=0"`
-	CockpitPxOffset   [2]int                   `yaml:"cockpitOffsetPx" validate:"required"`
-	HeatSinks         *ModelResourceHeatSinks  `yaml:"heatSinks"`
-	Armament          []*ModelResourceArmament `yaml:"armament"`
-	Ammo              []*ModelResourceAmmo     `yaml:"ammo"`
+	File              string                    `yaml:"-"`
+	Name              string                    `yaml:"name" validate:"required"`
+	Variant           string                    `yaml:"variant" validate:"required"`
+	Image             string                    `yaml:"image" validate:"required"`
+	Tech              ModelTech                 `yaml:"tech" validate:"required"`
+	Tonnage           float64                   `yaml:"tonnage" validate:"gt=0,lte=200"`
+	Height            float64                   `yaml:"height" validate:"gt=0"`
+	HeightPxGap       int                       `yaml:"heightPixelGap" validate:"gte=0"`
+	Speed             float64                   `yaml:"speed" validate:"gt=0,lte=250"`
+	JumpJets          int                       `yaml:"jumpJets" validate:"gte=0,lte=20"`
+	Armor             float64                   `yaml:"armor" validate:"gte=0"`
+	Structure         float64                   `yaml:"structure" validate:"gt=0"`
+	Locations         []*ModelResourceLocation  `yaml:"locations" validate:"omitempty,dive"`
+	CollisionPxRadius int                       `yaml:"collisionRadiusPx" validate:"gt=0"`
+	CollisionPxHeight int                       `yaml:"collisionHeightPx" validate:"gt=0"`
+	CockpitPxOffset   [2]int                    `yaml:"cockpitOffsetPx" validate:"required"`
+	SensorRange       float64                   `yaml:"sensorRange,omitempty" validate:"gte=0"`
+	HeatSinks         *ModelResourceHeatSinks   `yaml:"heatSinks"`
+	Armament          []*ModelResourceArmament  `yaml:"armament"`
+	Ammo              []*ModelResourceAmmo      `yaml:"ammo"`
+	Equipment         []*ModelResourceEquipment `yaml:"equipment,omitempty" validate:"omitempty,dive"`
+	DestroyEffects    []string                  `yaml:"destroyEffects,omitempty" validate:"omitempty,dive,required"`
 }
 
 type ModelVehicleResource struct {
-	File              string                   `yaml:"-"`
-	Name              string                   `yaml:"name" validate:"required"`
-	Variant           string                   `yaml:"variant" validate:"required"`
-	Image             string                   `yaml:"image" validate:"required"`
-	ImageSheet        *ModelResourceImageSheet `yaml:"imageSheet"`
-	Tech              ModelTech                `yaml:"tech" validate:"required"`
-	Tonnage           float64                  `yaml:"tonnage" validate:"gt=0,lte=200"`
-	Height            float64                  `yaml:"height" validate:"gt=0"`
-	HeightPxGap       int                      `yaml:"heightPixelGap" validate:"gte=0"`
-	Speed             float64                  `yaml:"speed" validate:"gt=0,lte=250"`
-	Armor             float64                  `yaml:"armor" validate:"gte=0"`
-	Structure         float64                  `yaml:"structure" validate:"gt=0"`
-	CollisionPxRadius int                      `yaml:"collisionRadiusPx" validate:"gt=0"`
-	CollisionPxHeight int                      `yaml:"collisionHeightPx" validate:"gt=0"`
-	CockpitPxOffset   [2]int                   `yaml:"cockpitOffsetPx" validate:"required"`
-	HeatSinks         *ModelResourceHeatSinks  `yaml:"heatSinks"`
-	Armament          []*ModelResourceArmament `yaml:"armament"`
-	Ammo              []*ModelResourceAmmo     `yaml:"ammo"`
+	File              string                    `yaml:"-"`
+	Name              string                    `yaml:"name" validate:"required"`
+	Variant           string                    `yaml:"variant" validate:"required"`
+	Image             string                    `yaml:"image" validate:"required"`
+	ImageSheet        *ModelResourceImageSheet  `yaml:"imageSheet"`
+	Tech              ModelTech                 `yaml:"tech" validate:"required"`
+	Tonnage           float64                   `yaml:"tonnage" validate:"gt=0,lte=200"`
+	Height            float64                   `yaml:"height" validate:"gt=0"`
+	HeightPxGap       int                       `yaml:"heightPixelGap" validate:"gte=0"`
+	Speed             float64                   `yaml:"speed" validate:"gt=0,lte=250"`
+	Armor             float64                   `yaml:"armor" validate:"gte=0"`
+	Structure         float64                   `yaml:"structure" validate:"gt=0"`
+	Locations         []*ModelResourceLocation  `yaml:"locations" validate:"omitempty,dive"`
+	CollisionPxRadius int                       `yaml:"collisionRadiusPx" validate:"gt=0"`
+	CollisionPxHeight int                       `yaml:"collisionHeightPx" validate:"gt=0"`
+	CockpitPxOffset   [2]int                    `yaml:"cockpitOffsetPx" validate:"required"`
+	SensorRange       float64                   `yaml:"sensorRange,omitempty" validate:"gte=0"`
+	HeatSinks         *ModelResourceHeatSinks   `yaml:"heatSinks"`
+	Armament          []*ModelResourceArmament  `yaml:"armament"`
+	Ammo              []*ModelResourceAmmo      `yaml:"ammo"`
+	Equipment         []*ModelResourceEquipment `yaml:"equipment,omitempty" validate:"omitempty,dive"`
+	DestroyEffects    []string                  `yaml:"destroyEffects,omitempty" validate:"omitempty,dive,required"`
 }
 
 type ModelVTOLResource struct {
-	File              string                   `yaml:"-"`
-	Name              string                   `yaml:"name" validate:"required"`
-	Variant           string                   `yaml:"variant" validate:"required"`
-	Image             string                   `yaml:"image" validate:"required"`
-	ImageSheet        *ModelResourceImageSheet `yaml:"imageSheet"`
-	Tech              ModelTech                `yaml:"tech" validate:"required"`
-	Tonnage           float64                  `yaml:"tonnage" validate:"gt=0,lte=100"`
-	Height            float64                  `yaml:"height" validate:"gt=0"`
-	HeightPxGap       int                      `yaml:"heightPixelGap" validate:"gte=0"`
-	Speed             float64                  `yaml:"speed" validate:"gt=0,lte=250"`
-	Armor             float64                  `yaml:"armor" validate:"gte=0"`
-	Structure         float64                  `yaml:"structure" validate:"gt=0"`
-	CollisionPxRadius int                      `yaml:"collisionRadiusPx" validate:"gt=0"`
-	CollisionPxHeight int                      `yaml:"collisionHeightPx" validate:"gt=0"`
-	CockpitPxOffset   [2]int                   `yaml:"cockpitOffsetPx" validate:"required"`
-	HeatSinks         *ModelResourceHeatSinks  `yaml:"heatSinks"`
-	Armament          []*ModelResourceArmament `yaml:"armament"`
-	Ammo              []*ModelResourceAmmo     `yaml:"ammo"`
+	File              string                    `yaml:"-"`
+	Name              string                    `yaml:"name" validate:"required"`
+	Variant           string                    `yaml:"variant" validate:"required"`
+	Image             string                    `yaml:"image" validate:"required"`
+	ImageSheet        *ModelResourceImageSheet  `yaml:"imageSheet"`
+	Tech              ModelTech                 `yaml:"tech" validate:"required"`
+	Tonnage           float64                   `yaml:"tonnage" validate:"gt=0,lte=100"`
+	Height            float64                   `yaml:"height" validate:"gt=0"`
+	HeightPxGap       int                       `yaml:"heightPixelGap" validate:"gte=0"`
+	Speed             float64                   `yaml:"speed" validate:"gt=0,lte=250"`
+	Armor             float64                   `yaml:"armor" validate:"gte=0"`
+	Structure         float64                   `yaml:"structure" validate:"gt=0"`
+	Locations         []*ModelResourceLocation  `yaml:"locations" validate:"omitempty,dive"`
+	CollisionPxRadius int                       `yaml:"collisionRadiusPx" validate:"gt=0"`
+	CollisionPxHeight int                       `yaml:"collisionHeightPx" validate:"gt=0"`
+	CockpitPxOffset   [2]int                    `yaml:"cockpitOffsetPx" validate:"required"`
+	SensorRange       float64                   `yaml:"sensorRange,omitempty" validate:"gte=0"`
+	HeatSinks         *ModelResourceHeatSinks   `yaml:"heatSinks"`
+	Armament          []*ModelResourceArmament  `yaml:"armament"`
+	Ammo              []*ModelResourceAmmo      `yaml:"ammo"`
+	Equipment         []*ModelResourceEquipment `yaml:"equipment,omitempty" validate:"omitempty,dive"`
+	DestroyEffects    []string                  `yaml:"destroyEffects,omitempty" validate:"omitempty,dive,required"`
 }
 
 type ModelInfantryResource struct {
@@ -121,8 +144,10 @@ type ModelInfantryResource struct {
 	CollisionPxRadius int                      `yaml:"collisionRadiusPx" validate:"gt=0"`
 	CollisionPxHeight int                      `yaml:"collisionHeightPx" validate:"gt=0"`
 	CockpitPxOffset   [2]int                   `yaml:"cockpitOffsetPx" validate:"required"`
+	SensorRange       float64                  `yaml:"sensorRange,omitempty" validate:"gte=0"`
 	Armament          []*ModelResourceArmament `yaml:"armament"`
 	Ammo              []*ModelResourceAmmo     `yaml:"ammo"`
+	DestroyEffects    []string                 `yaml:"destroyEffects,omitempty" validate:"omitempty,dive,required"`
 }
 
 type ModelEmplacementResource struct {
@@ -139,8 +164,10 @@ type ModelEmplacementResource struct {
 	CollisionPxRadius int                      `yaml:"collisionRadiusPx" validate:"gt=0"`
 	CollisionPxHeight int                      `yaml:"collisionHeightPx" validate:"gt=0"`
 	CockpitPxOffset   [2]int                   `yaml:"cockpitOffsetPx" validate:"required"`
+	SensorRange       float64                  `yaml:"sensorRange,omitempty" validate:"gte=0"`
 	Armament          []*ModelResourceArmament `yaml:"armament"`
 	Ammo              []*ModelResourceAmmo     `yaml:"ammo"`
+	DestroyEffects    []string                 `yaml:"destroyEffects,omitempty" validate:"omitempty,dive,required"`
 }
 
 type ModelEnergyWeaponResource struct {
@@ -149,6 +176,7 @@ type ModelEnergyWeaponResource struct {
 	ShortName       string                   `yaml:"short" validate:"required"`
 	Tech            ModelTech                `yaml:"tech" validate:"required"`
 	Tonnage         float64                  `yaml:"tonnage" validate:"gt=0,lte=100"`
+	Slots           int                      `yaml:"slots" validate:"gte=0"`
 	Damage          float64                  `yaml:"damage" validate:"gt=0"`
 	Heat            float64                  `yaml:"heat" validate:"gte=0"`
 	Distance        float64                  `yaml:"distance" validate:"gt=0"`
@@ -159,6 +187,7 @@ type ModelEnergyWeaponResource struct {
 	ProjectileDelay float64                  `yaml:"projectileDelay" validate:"gte=0"`
 	Projectile      *ModelProjectileResource `yaml:"projectile"`
 	Audio           string                   `yaml:"audio" validate:"required"`
+	MuzzleEffect    string                   `yaml:"muzzleEffect,omitempty"`
 }
 
 type ModelMissileWeaponResource struct {
@@ -167,6 +196,7 @@ type ModelMissileWeaponResource struct {
 	ShortName       string                    `yaml:"short" validate:"required"`
 	Tech            ModelTech                 `yaml:"tech" validate:"required"`
 	Tonnage         float64                   `yaml:"tonnage" validate:"gt=0,lte=100"`
+	Slots           int                       `yaml:"slots" validate:"gte=0"`
 	Damage          float64                   `yaml:"damage" validate:"gt=0"`
 	Heat            float64                   `yaml:"heat" validate:"gte=0"`
 	Distance        float64                   `yaml:"distance" validate:"gt=0"`
@@ -179,6 +209,7 @@ type ModelMissileWeaponResource struct {
 	Projectile      *ModelProjectileResource  `yaml:"projectile"`
 	LockOn          *ModelMissileWeaponLockOn `yaml:"lockOn,omitempty"`
 	Audio           string                    `yaml:"audio" validate:"required"`
+	MuzzleEffect    string                    `yaml:"muzzleEffect,omitempty"`
 }
 
 type ModelBallisticWeaponResource struct {
@@ -187,6 +218,7 @@ type ModelBallisticWeaponResource struct {
 	ShortName        string                   `yaml:"short" validate:"required"`
 	Tech             ModelTech                `yaml:"tech" validate:"required"`
 	Tonnage          float64                  `yaml:"tonnage" validate:"gt=0,lte=100"`
+	Slots            int                      `yaml:"slots" validate:"gte=0"`
 	Damage           float64                  `yaml:"damage" validate:"gt=0"`
 	Heat             float64                  `yaml:"heat" validate:"gte=0"`
 	Distance         float64                  `yaml:"distance" validate:"gt=0"`
@@ -199,6 +231,7 @@ type ModelBallisticWeaponResource struct {
 	ProjectileSpread float64                  `yaml:"projectileSpread" validate:"gte=0"`
 	Projectile       *ModelProjectileResource `yaml:"projectile"`
 	Audio            string                   `yaml:"audio" validate:"required"`
+	MuzzleEffect     string                   `yaml:"muzzleEffect,omitempty"`
 }
 
 type ModelProjectileResource struct {
@@ -218,9 +251,9 @@ type ModelMissileWeaponLockOn struct {
 
 type ModelEffectResource struct {
 	Image      string                   `yaml:"image" validate:"required"`
-	ImageSheet *ModelResourceImageSheet `yaml:"imageSheet"`
+	ImageSheet *ModelResourceImageSheet `yaml:"imageSheet" validate:"required"`
 	Diameter   float64                  `yaml:"diameter" validate:"gt=0"`
-	Audio      string                   `yaml:"audio" validate:"required"`
+	Audio      string                   `yaml:"audio"`
 	RandAudio  []string                 `yaml:"randAudio"`
 }
 
@@ -244,9 +277,15 @@ type ModelResourceArmament struct {
 	Offset   [2]int          `yaml:"offsetPx" validate:"required"`
 }
 
+type ModelResourceLocation struct {
+	Location  ModelLocation `yaml:"location" validate:"required"`
+	Armor     float64       `yaml:"armor" validate:"gte=0"`
+	Structure float64       `yaml:"structure" validate:"gt=0"`
+}
+
 type ModelResourceAmmo struct {
 	Type      ModelAmmoType `yaml:"type" validate:"required"`
-	ForWeapon string        `yaml:"forWeapon"`
+	ForWeapon string        `yaml:"forWeapon,omitempty"`
 	Tons      float64       `yaml:"tons" validate:"gt=0"`
 }
 
@@ -268,6 +307,11 @@ func (t *ModelTech) UnmarshalText(b []byte) error {
 	return nil
 }
 
+// Marshals from TechBase
+func (t ModelTech) MarshalText() ([]byte, error) {
+	return []byte(t.TechBase.String()), nil
+}
+
 // Unmarshals into HeatSinkType
 func (t *ModelHeatSinkType) UnmarshalText(b []byte) error {
 	str := strings.Trim(string(b), `"`)
@@ -286,6 +330,17 @@ func (t *ModelHeatSinkType) UnmarshalText(b []byte) error {
 	return nil
 }
 
+// Marshals from HeatSinkType
+func (t ModelHeatSinkType) MarshalText() ([]byte, error) {
+	switch t.HeatSinkType {
+	case SINGLE:
+		return []byte("single"), nil
+	case DOUBLE:
+		return []byte("double"), nil
+	}
+	return nil, fmt.Errorf("unknown heat sink type %d", t.HeatSinkType)
+}
+
 // Unmarshals into WeaponType
 func (t *ModelWeaponType) UnmarshalText(b []byte) error {
 	str := strings.Trim(string(b), `"`)
@@ -306,6 +361,11 @@ func (t *ModelWeaponType) UnmarshalText(b []byte) error {
 	return nil
 }
 
+// Marshals from WeaponType
+func (t ModelWeaponType) MarshalText() ([]byte, error) {
+	return []byte(t.WeaponType.String()), nil
+}
+
 // Unmarshals into Location
 func (t *ModelLocation) UnmarshalText(b []byte) error {
 	str := strings.Trim(string(b), `"`)
@@ -324,6 +384,14 @@ func (t *ModelLocation) UnmarshalText(b []byte) error {
 	return nil
 }
 
+// Marshals from Location
+func (t ModelLocation) MarshalText() ([]byte, error) {
+	if name, ok := locationNames[t.Location]; ok {
+		return []byte(name), nil
+	}
+	return nil, fmt.Errorf("unknown location %d", t.Location)
+}
+
 // Unmarshals into AmmoType
 func (t *ModelAmmoType) UnmarshalText(b []byte) error {
 	str := strings.Trim(string(b), `"`)
@@ -348,10 +416,31 @@ func (t *ModelAmmoType) UnmarshalText(b []byte) error {
 	return nil
 }
 
+// Marshals from AmmoType
+func (t ModelAmmoType) MarshalText() ([]byte, error) {
+	switch t.AmmoType {
+	case AMMO_BALLISTIC:
+		return []byte("ballistic"), nil
+	case AMMO_LRM:
+		return []byte("lrm"), nil
+	case AMMO_SRM:
+		return []byte("srm"), nil
+	case AMMO_STREAK_SRM:
+		return []byte("streak_srm"), nil
+	}
+	return nil, fmt.Errorf("unknown ammo type %d", t.AmmoType)
+}
+
 func LoadModelResources() (*ModelResources, error) {
 	resources := &ModelResources{}
 
-	err := resources.loadWeaponResources()
+	err := resources.loadEffectResources()
+	if err != nil {
+		log.Fatal(err)
+		return nil, err
+	}
+
+	err = resources.loadWeaponResources()
 	if err != nil {
 		log.Fatal(err)
 		return nil, err
@@ -424,7 +513,16 @@ func (r *ModelResources) loadUnitResources() error {
 
 			switch unitType {
 			case MechResourceType:
-				m := &ModelMechResource{}
+				m, err := r.parseMechResource(v, filePath, unitYaml)
+				if err != nil {
+					return err
+				}
+
+				m.File = fileName
+				r.Mechs[TrimExtension(fileName)] = m
+
+			case VehicleResourceType:
+				m := &ModelVehicleResource{}
 				err = yaml.Unmarshal(unitYaml, m)
 				if err != nil {
 					return fmt.Errorf("[%s] %s", filePath, err.Error())
@@ -435,17 +533,17 @@ func (r *ModelResources) loadUnitResources() error {
 					return fmt.Errorf("[%s] %s", filePath, err.Error())
 				}
 
-				m.File = fileName
-				r.Mechs[TrimExtension(fileName)] = m
+				err = ValidateResourceLocations(VehicleUnitType, m.Armor, m.Structure, m.Locations)
+				if err != nil {
+					return fmt.Errorf("[%s] %s", filePath, err.Error())
+				}
 
-			case VehicleResourceType:
-				m := &ModelVehicleResource{}
-				err = yaml.Unmarshal(unitYaml, m)
+				err = ValidateResourceEquipment(VehicleUnitType, m.Equipment)
 				if err != nil {
 					return fmt.Errorf("[%s] %s", filePath, err.Error())
 				}
 
-				err = v.Struct(m)
+				err = r.validateEffectKeys(m.DestroyEffects)
 				if err != nil {
 					return fmt.Errorf("[%s] %s", filePath, err.Error())
 				}
@@ -465,6 +563,21 @@ func (r *ModelResources) loadUnitResources() error {
 					return fmt.Errorf("[%s] %s", filePath, err.Error())
 				}
 
+				err = ValidateResourceLocations(VTOLUnitType, m.Armor, m.Structure, m.Locations)
+				if err != nil {
+					return fmt.Errorf("[%s] %s", filePath, err.Error())
+				}
+
+				err = ValidateResourceEquipment(VTOLUnitType, m.Equipment)
+				if err != nil {
+					return fmt.Errorf("[%s] %s", filePath, err.Error())
+				}
+
+				err = r.validateEffectKeys(m.DestroyEffects)
+				if err != nil {
+					return fmt.Errorf("[%s] %s", filePath, err.Error())
+				}
+
 				m.File = fileName
 				r.VTOLs[TrimExtension(fileName)] = m
 
@@ -480,6 +593,11 @@ func (r *ModelResources) loadUnitResources() error {
 					return fmt.Errorf("[%s] %s", filePath, err.Error())
 				}
 
+				err = r.validateEffectKeys(m.DestroyEffects)
+				if err != nil {
+					return fmt.Errorf("[%s] %s", filePath, err.Error())
+				}
+
 				m.File = fileName
 				r.Infantry[TrimExtension(fileName)] = m
 
@@ -495,6 +613,11 @@ func (r *ModelResources) loadUnitResources() error {
 					return fmt.Errorf("[%s] %s", filePath, err.Error())
 				}
 
+				err = r.validateEffectKeys(m.DestroyEffects)
+				if err != nil {
+					return fmt.Errorf("[%s] %s", filePath, err.Error())
+				}
+
 				m.File = fileName
 				r.Emplacements[TrimExtension(fileName)] = m
 			}
@@ -504,6 +627,53 @@ func (r *ModelResources) loadUnitResources() error {
 	return nil
 }
 
+func (r *ModelResources) parseMechResource(v *validator.Validate, filePath string, unitYaml []byte) (*ModelMechResource, error) {
+	m := &ModelMechResource{}
+	err := yaml.Unmarshal(unitYaml, m)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %s", filePath, err.Error())
+	}
+
+	err = v.Struct(m)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %s", filePath, err.Error())
+	}
+
+	err = ValidateResourceLocations(MechUnitType, m.Armor, m.Structure, m.Locations)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %s", filePath, err.Error())
+	}
+
+	err = ValidateResourceEquipment(MechUnitType, m.Equipment)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %s", filePath, err.Error())
+	}
+
+	err = r.validateEffectKeys(m.DestroyEffects)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %s", filePath, err.Error())
+	}
+	return m, nil
+}
+
+// LoadMechResource loads a single mech resource file, such as one saved from the mech lab after all resources were loaded
+func (r *ModelResources) LoadMechResource(fileName string) (*ModelMechResource, error) {
+	filePath := path.Join(UnitsResourceType, MechResourceType, fileName)
+	unitYaml, err := resources.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := r.parseMechResource(validator.New(), filePath, unitYaml)
+	if err != nil {
+		return nil, err
+	}
+
+	m.File = fileName
+	r.Mechs[TrimExtension(fileName)] = m
+	return m, nil
+}
+
 func (r *ModelResources) loadWeaponResources() error {
 	// load and validate all weapons, projectiles and impact efffects
 	v := validator.New()
@@ -561,6 +731,11 @@ func (r *ModelResources) loadWeaponResources() error {
 					return fmt.Errorf("[%s] %s", weaponFilePath, err.Error())
 				}
 
+				err = r.validateEffectKeys([]string{m.MuzzleEffect})
+				if err != nil {
+					return fmt.Errorf("[%s] %s", weaponFilePath, err.Error())
+				}
+
 				m.File = fileName
 				r.EnergyWeapons[TrimExtension(fileName)] = m
 
@@ -576,6 +751,11 @@ func (r *ModelResources) loadWeaponResources() error {
 					return fmt.Errorf("[%s] %s", weaponFilePath, err.Error())
 				}
 
+				err = r.validateEffectKeys([]string{m.MuzzleEffect})
+				if err != nil {
+					return fmt.Errorf("[%s] %s", weaponFilePath, err.Error())
+				}
+
 				m.File = fileName
 				r.MissileWeapons[TrimExtension(fileName)] = m
 
@@ -591,6 +771,11 @@ func (r *ModelResources) loadWeaponResources() error {
 					return fmt.Errorf("[%s] %s", weaponFilePath, err.Error())
 				}
 
+				err = r.validateEffectKeys([]string{m.MuzzleEffect})
+				if err != nil {
+					return fmt.Errorf("[%s] %s", weaponFilePath, err.Error())
+				}
+
 				m.File = fileName
 				r.BallisticWeapons[TrimExtension(fileName)] = m
 
@@ -601,6 +786,90 @@ func (r *ModelResources) loadWeaponResources() error {
 	return nil
 }
 
+func (r *ModelResources) loadEffectResources() error {
+	// load and validate all special effects, with subfolder name as the effect type
+	v := validator.New()
+
+	effectFiles, err := resources.ReadDir(EffectsResourceType, true)
+	if err != nil {
+		return err
+	}
+
+	r.Effects = make(map[string]*ModelEffectResource, len(effectFiles))
+	r.effectKeys = make(map[string][]string)
+
+	for _, f := range effectFiles {
+		if f.IsDir() || f.Parent() == "" {
+			// only files in folders with effect type name expected
+			continue
+		}
+
+		fileName := f.Name()
+		effectFilePath := path.Join(EffectsResourceType, fileName)
+		effectYaml, err := resources.ReadFile(effectFilePath)
+		if err != nil {
+			return err
+		}
+
+		m := &ModelEffectResource{}
+		err = yaml.Unmarshal(effectYaml, m)
+		if err != nil {
+			return fmt.Errorf("[%s] %s", effectFilePath, err.Error())
+		}
+
+		err = v.Struct(m)
+		if err != nil {
+			return fmt.Errorf("[%s] %s", effectFilePath, err.Error())
+		}
+
+		key := TrimExtension(fileName)
+		r.Effects[key] = m
+		r.effectKeys[f.Parent()] = append(r.effectKeys[f.Parent()], key)
+	}
+
+	// sort keys so the same random seed picks the same effects
+	for _, keys := range r.effectKeys {
+		sort.Strings(keys)
+	}
+
+	return nil
+}
+
+// validateEffectKeys checks that any non-empty effect keys referenced by a resource exist
+func (r *ModelResources) validateEffectKeys(keys []string) error {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if _, ok := r.Effects[key]; !ok {
+			return fmt.Errorf("effect resource does not exist %s", key)
+		}
+	}
+	return nil
+}
+
+// GetEffectResource gets the effect resource by its key, such as "explosion/01"
+func (r *ModelResources) GetEffectResource(key string) (*ModelEffectResource, error) {
+	if m, ok := r.Effects[key]; ok {
+		return m, nil
+	}
+	return nil, fmt.Errorf("effect resource does not exist %s", key)
+}
+
+// GetEffectKeys gets the sorted effect resource keys of the effect type
+func (r *ModelResources) GetEffectKeys(effectType string) []string {
+	return r.effectKeys[effectType]
+}
+
+// RandEffectKey returns a random effect resource key of the effect type from the shared effects random source
+func (r *ModelResources) RandEffectKey(effectType string) string {
+	keys := r.effectKeys[effectType]
+	if len(keys) == 0 {
+		return ""
+	}
+	return keys[EffectsRandIntn(len(keys))]
+}
+
 func (r *ModelResources) GetMechResource(unit string) (*ModelMechResource, error) {
 	if m, ok := r.Mechs[unit]; ok {
 		return m, nil
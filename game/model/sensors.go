@@ -0,0 +1,121 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// default sensor range for units that do not specify their own
+	SENSOR_RANGE_METERS float64 = 1000
+	// sensor range against units that are powered down
+	SENSOR_POWERED_DOWN_RANGE_METERS float64 = 200
+	// units this close are detected even without line of sight
+	SENSOR_PROXIMITY_METERS float64 = 200
+	// seconds a unit must stay detected before it shows on radar and can be targeted
+	SENSOR_DETECT_SECONDS float64 = 1.5
+
+	// range of the ECM bubble which hides friendly units from enemy radar
+	ECM_RANGE_METERS float64 = 180
+	// range that an active probe can see through the ECM of enemy units
+	BAP_RANGE_METERS float64 = 360
+)
+
+type ElectronicsType int
+
+const (
+	_   ElectronicsType = iota
+	ECM                 // electronic countermeasures
+	BAP                 // beagle active probe
+)
+
+func (t ElectronicsType) String() string {
+	switch t {
+	case ECM:
+		return "ecm"
+	case BAP:
+		return "bap"
+	}
+	return "unknown"
+}
+
+type ModelElectronicsType struct {
+	ElectronicsType
+}
+
+// ModelResourceEquipment is electronic warfare equipment mounted on a unit
+type ModelResourceEquipment struct {
+	Type     ModelElectronicsType `yaml:"type" validate:"required"`
+	Location ModelLocation        `yaml:"location,omitempty"`
+}
+
+// Unmarshals into ElectronicsType
+func (t *ModelElectronicsType) UnmarshalText(b []byte) error {
+	str := strings.Trim(string(b), `"`)
+
+	switch str {
+	case ECM.String():
+		t.ElectronicsType = ECM
+	case BAP.String():
+		t.ElectronicsType = BAP
+	default:
+		return fmt.Errorf("unknown equipment type value '%s', must be one of: [%s, %s]", str, ECM.String(), BAP.String())
+	}
+
+	return nil
+}
+
+// Marshals from ElectronicsType
+func (t ModelElectronicsType) MarshalText() ([]byte, error) {
+	switch t.ElectronicsType {
+	case ECM, BAP:
+		return []byte(t.ElectronicsType.String()), nil
+	}
+	return nil, fmt.Errorf("unknown equipment type %d", t.ElectronicsType)
+}
+
+// sensorRangeUnits converts the resource sensor range in meters to units, using the default if not set
+func sensorRangeUnits(rangeMeters float64) float64 {
+	if rangeMeters <= 0 {
+		rangeMeters = SENSOR_RANGE_METERS
+	}
+	return rangeMeters / METERS_PER_UNIT
+}
+
+// SensorRange returns the distance in units the unit can detect other powered on units
+func (e *UnitModel) SensorRange() float64 {
+	if e.sensorRange <= 0 {
+		return sensorRangeUnits(0)
+	}
+	return e.sensorRange
+}
+
+// HasElectronics returns true if the unit has the equipment mounted in a location that is not destroyed
+func (e *UnitModel) HasElectronics(electronicsType ElectronicsType) bool {
+	if e.powered != POWER_ON {
+		return false
+	}
+	for _, equip := range e.equipment {
+		if equip.Type.ElectronicsType != electronicsType {
+			continue
+		}
+		if equip.Location.Location > 0 {
+			if l := e.GetLocation(equip.Location.Location); l != nil && l.IsDestroyed() {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// ValidateResourceEquipment checks that equipment locations are valid for the unit type
+func ValidateResourceEquipment(unitType UnitType, equipment []*ModelResourceEquipment) error {
+	for _, equip := range equipment {
+		l := equip.Location.Location
+		if l > 0 && !InArray(UnitTypeLocations(unitType), l) {
+			return fmt.Errorf("equipment '%s' location '%s' is not valid for unit type", equip.Type.String(), l.ShortName())
+		}
+	}
+	return nil
+}
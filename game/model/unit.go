@@ -75,6 +75,8 @@ type Unit interface {
 	Firepower() float64
 	Heat() float64
 	MaxHeat() float64
+	HeatScaleBand() *HeatScaleBand
+	CookOffAmmo(*Rand) *CriticalHit
 	HeatDissipation() float64
 	OverHeated() bool
 	Powered() UnitPowerStatus
@@ -84,6 +86,8 @@ type Unit interface {
 	SetPowerConditions(UnitPowerConditions)
 
 	TriggerWeapon(Weapon) bool
+	SensorRange() float64
+	HasElectronics(ElectronicsType) bool
 	Target() Entity
 	SetTarget(Entity)
 	TargetLock() float64
@@ -99,6 +103,8 @@ type Unit interface {
 	SetTargetVelocity(float64)
 	TargetVelocityZ() float64
 	SetTargetVelocityZ(float64)
+	GroundZ() float64
+	SetGroundZ(float64)
 	Update() bool
 
 	HasTurret() bool
@@ -117,6 +123,16 @@ type Unit interface {
 	Armament() []Weapon
 	AddArmament(Weapon)
 
+	Locations() []*UnitLocation
+	GetLocation(Location) *UnitLocation
+	ApplyLocationDamage(float64, Location) []Location
+	ApplyCriticalHits(Location, *Rand) []*CriticalHit
+	HitLocation(*geom.Vector2, float64) Location
+	UnitState() *UnitState
+	SetUnitState(*UnitState) error
+	UnitMissionState() *UnitMissionState
+	SetUnitMissionState(*UnitMissionState) error
+
 	JumpJets() int
 	JumpJetsActive() bool
 	SetJumpJetsActive(bool)
@@ -134,11 +150,15 @@ type Unit interface {
 	SetGuardArea(x, y, radius float64)
 	GuardUnit() string
 	SetGuardUnit(string)
+	Pilot() *Pilot
+	SetPilot(*Pilot)
 	PathStack() *common.FIFOStack[geom.Vector2]
 	SetPatrolPath([]geom.Vector2)
 	WithdrawArea() *Rect
 	SetWithdrawArea(*Rect)
 
+	DestroyEffects() []string
+
 	Objective() UnitObjective
 	SetObjective(UnitObjective)
 	SetAsPlayer(bool)
@@ -151,10 +171,12 @@ type UnitModel struct {
 	id                  string
 	name                string
 	variant             string
+	destroyEffects      []string
 	team                int
 	unitType            UnitType
 	position            *geom.Vector2
 	positionZ           float64
+	groundZ             float64
 	anchor              raycaster.SpriteAnchor
 	heading             float64
 	targetHeading       float64
@@ -179,6 +201,7 @@ type UnitModel struct {
 	pxScale             float64
 	armor               float64
 	structure           float64
+	locations           []*UnitLocation
 	hasDamage           bool
 	heat                float64
 	heatDissipation     float64
@@ -188,6 +211,8 @@ type UnitModel struct {
 	powerConditions     *UnitPowerConditions
 	armament            []Weapon
 	ammunition          *Ammo
+	sensorRange         float64
+	equipment           []*ModelResourceEquipment
 	jumpJets            int
 	jumpJetsActive      bool
 	jumpJetsDirectional bool
@@ -202,6 +227,7 @@ type UnitModel struct {
 	objective           UnitObjective
 	guardArea           *geom.Circle
 	guardUnit           string
+	pilot               *Pilot
 	pathStack           *common.FIFOStack[geom.Vector2]
 	withdrawArea        *Rect
 	parent              Entity
@@ -269,6 +295,11 @@ func (e *UnitModel) Variant() string {
 	return e.variant
 }
 
+// DestroyEffects returns the effect resource keys used when the unit is destroyed, if unit specific
+func (e *UnitModel) DestroyEffects() []string {
+	return e.destroyEffects
+}
+
 func (e *UnitModel) Team() int {
 	return e.team
 }
@@ -352,7 +383,7 @@ func (e *UnitModel) SetPowerConditions(powerConditions UnitPowerConditions) {
 }
 
 func (e *UnitModel) TriggerWeapon(w Weapon) bool {
-	if e.powered != POWER_ON || w.Cooldown() > 0 {
+	if e.powered != POWER_ON || w.Destroyed() || w.Cooldown() > 0 {
 		return false
 	}
 
@@ -483,6 +514,15 @@ func (e *UnitModel) SetPosZ(posZ float64) {
 	e.positionZ = posZ
 }
 
+// GroundZ is the height of the terrain the unit is above
+func (e *UnitModel) GroundZ() float64 {
+	return e.groundZ
+}
+
+func (e *UnitModel) SetGroundZ(groundZ float64) {
+	e.groundZ = groundZ
+}
+
 func (e *UnitModel) Anchor() raycaster.SpriteAnchor {
 	return e.anchor
 }
@@ -542,6 +582,10 @@ func (e *UnitModel) SetVelocityZ(velocityZ float64) {
 }
 
 func (e *UnitModel) MaxVelocity() float64 {
+	if band := e.HeatScaleBand(); band != nil {
+		// heat slows down movement
+		return e.maxVelocity * (1 - band.VelocityPenalty)
+	}
 	return e.maxVelocity
 }
 
@@ -603,6 +647,16 @@ func (e *UnitModel) ApplyDamage(damage float64) {
 	if damage <= 0 {
 		return
 	}
+	if layout, ok := unitLocationLayouts[e.unitType]; ok && len(e.locations) > 0 {
+		// damage without a specific hit location goes to the default location
+		e.ApplyLocationDamage(damage, layout.defaultHit)
+		return
+	}
+	e.applyTotalDamage(damage)
+}
+
+// applyTotalDamage applies damage to armor then structure of the unit as a whole
+func (e *UnitModel) applyTotalDamage(damage float64) {
 	e.hasDamage = true
 	if e.armor > 0 {
 		e.armor -= damage
@@ -629,6 +683,19 @@ func (e *UnitModel) ArmorPoints() float64 {
 
 func (e *UnitModel) SetArmorPoints(armor float64) {
 	e.armor = armor
+
+	if len(e.locations) > 0 {
+		// distribute armor to each location proportionally to its max
+		var maxArmor float64
+		for _, l := range e.locations {
+			maxArmor += l.maxArmor
+		}
+		for _, l := range e.locations {
+			if maxArmor > 0 {
+				l.SetArmorPoints(armor * l.maxArmor / maxArmor)
+			}
+		}
+	}
 }
 
 func (e *UnitModel) StructurePoints() float64 {
@@ -637,6 +704,19 @@ func (e *UnitModel) StructurePoints() float64 {
 
 func (e *UnitModel) SetStructurePoints(structure float64) {
 	e.structure = structure
+
+	if len(e.locations) > 0 {
+		// distribute structure to each location proportionally to its max
+		var maxStructure float64
+		for _, l := range e.locations {
+			maxStructure += l.maxStructure
+		}
+		for _, l := range e.locations {
+			if maxStructure > 0 {
+				l.SetStructurePoints(structure * l.maxStructure / maxStructure)
+			}
+		}
+	}
 }
 
 func (e *UnitModel) IsDestroyed() bool {
@@ -779,7 +859,7 @@ func (e *UnitModel) needsUpdate() bool {
 		e.targetHeading != e.heading || e.targetPitch != e.pitch ||
 		e.targetTurretAngle != e.turretAngle ||
 		e.targetVelocity != 0 || e.velocity != 0 ||
-		e.targetVelocityZ != 0 || e.velocityZ != 0 || e.positionZ != 0 {
+		e.targetVelocityZ != 0 || e.velocityZ != 0 || e.positionZ != e.groundZ {
 		return true
 	}
 	return false
@@ -803,6 +883,11 @@ func (e *UnitModel) update() {
 		return
 	}
 
+	if maxV := e.MaxVelocity(); e.targetVelocity > maxV {
+		// max velocity may have been reduced since the target velocity was set
+		e.targetVelocity = maxV
+	}
+
 	turnRate := e.TurnRate()
 	turretRate := e.TurretRate()
 
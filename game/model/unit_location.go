@@ -0,0 +1,415 @@
+package model
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/harbdog/raycaster-go"
+	"github.com/harbdog/raycaster-go/geom"
+)
+
+// UnitLocation tracks the armor and internal structure of a single location on a unit
+type UnitLocation struct {
+	location     Location
+	armor        float64
+	maxArmor     float64
+	structure    float64
+	maxStructure float64
+}
+
+// unitLocationLayout defines which locations a unit type has and how damage flows between them
+type unitLocationLayout struct {
+	// locations in order of appearance
+	locations []Location
+	// default location to apply damage that does not have a specific hit location
+	defaultHit Location
+	// relative weights used to distribute total armor/structure when not specified per location
+	armorWeights     map[Location]float64
+	structureWeights map[Location]float64
+	// location that receives excess damage after a location is destroyed
+	transfer map[Location]Location
+	// locations which are also destroyed along with another location
+	dependent map[Location]Location
+	// locations which destroy the unit when destroyed
+	vital map[Location]bool
+}
+
+var unitLocationLayouts = map[UnitType]*unitLocationLayout{
+	MechUnitType: {
+		locations: []Location{
+			HEAD, CENTER_TORSO, LEFT_TORSO, RIGHT_TORSO, LEFT_ARM, RIGHT_ARM, LEFT_LEG, RIGHT_LEG,
+		},
+		defaultHit: CENTER_TORSO,
+		armorWeights: map[Location]float64{
+			HEAD: 9, CENTER_TORSO: 46, LEFT_TORSO: 32, RIGHT_TORSO: 32,
+			LEFT_ARM: 24, RIGHT_ARM: 24, LEFT_LEG: 32, RIGHT_LEG: 32,
+		},
+		structureWeights: map[Location]float64{
+			HEAD: 3, CENTER_TORSO: 23, LEFT_TORSO: 16, RIGHT_TORSO: 16,
+			LEFT_ARM: 12, RIGHT_ARM: 12, LEFT_LEG: 16, RIGHT_LEG: 16,
+		},
+		transfer: map[Location]Location{
+			LEFT_ARM: LEFT_TORSO, RIGHT_ARM: RIGHT_TORSO,
+			LEFT_LEG: LEFT_TORSO, RIGHT_LEG: RIGHT_TORSO,
+			LEFT_TORSO: CENTER_TORSO, RIGHT_TORSO: CENTER_TORSO,
+		},
+		dependent: map[Location]Location{
+			LEFT_TORSO: LEFT_ARM, RIGHT_TORSO: RIGHT_ARM,
+		},
+		vital: map[Location]bool{
+			HEAD: true, CENTER_TORSO: true,
+		},
+	},
+	VehicleUnitType: {
+		locations:  []Location{FRONT, LEFT, RIGHT, REAR, TURRET},
+		defaultHit: FRONT,
+		armorWeights: map[Location]float64{
+			FRONT: 30, LEFT: 20, RIGHT: 20, REAR: 15, TURRET: 15,
+		},
+		structureWeights: map[Location]float64{
+			FRONT: 1, LEFT: 1, RIGHT: 1, REAR: 1, TURRET: 1,
+		},
+		vital: map[Location]bool{
+			FRONT: true, LEFT: true, RIGHT: true, REAR: true,
+		},
+	},
+	VTOLUnitType: {
+		locations:  []Location{FRONT, LEFT, RIGHT, REAR, ROTOR},
+		defaultHit: FRONT,
+		armorWeights: map[Location]float64{
+			FRONT: 30, LEFT: 25, RIGHT: 25, REAR: 15, ROTOR: 5,
+		},
+		structureWeights: map[Location]float64{
+			FRONT: 1, LEFT: 1, RIGHT: 1, REAR: 1, ROTOR: 1,
+		},
+		vital: map[Location]bool{
+			FRONT: true, LEFT: true, RIGHT: true, REAR: true, ROTOR: true,
+		},
+	},
+}
+
+// UnitTypeLocations returns the locations available to the unit type, or nil if it does not use locations
+func UnitTypeLocations(unitType UnitType) []Location {
+	if layout, ok := unitLocationLayouts[unitType]; ok {
+		return layout.locations
+	}
+	return nil
+}
+
+// NewUnitLocations creates the locations for a unit type from the resource locations,
+// or by distributing the total armor and structure when resource locations are not provided
+func NewUnitLocations(unitType UnitType, armor, structure float64, rLocations []*ModelResourceLocation) []*UnitLocation {
+	layout, ok := unitLocationLayouts[unitType]
+	if !ok {
+		return nil
+	}
+
+	locations := make([]*UnitLocation, 0, len(layout.locations))
+	if len(rLocations) > 0 {
+		for _, l := range layout.locations {
+			for _, rLocation := range rLocations {
+				if rLocation.Location.Location == l {
+					locations = append(locations, NewUnitLocation(l, rLocation.Armor, rLocation.Structure))
+					break
+				}
+			}
+		}
+		return locations
+	}
+
+	var armorTotal, structureTotal float64
+	for _, l := range layout.locations {
+		armorTotal += layout.armorWeights[l]
+		structureTotal += layout.structureWeights[l]
+	}
+	for _, l := range layout.locations {
+		lArmor := armor * layout.armorWeights[l] / armorTotal
+		lStructure := structure * layout.structureWeights[l] / structureTotal
+		locations = append(locations, NewUnitLocation(l, lArmor, lStructure))
+	}
+	return locations
+}
+
+func NewUnitLocation(location Location, armor, structure float64) *UnitLocation {
+	return &UnitLocation{
+		location:     location,
+		armor:        armor,
+		maxArmor:     armor,
+		structure:    structure,
+		maxStructure: structure,
+	}
+}
+
+// CloneUnitLocations creates a copy of unit locations with their current damage
+func CloneUnitLocations(locations []*UnitLocation) []*UnitLocation {
+	if locations == nil {
+		return nil
+	}
+	lClones := make([]*UnitLocation, 0, len(locations))
+	for _, l := range locations {
+		lClone := *l
+		lClones = append(lClones, &lClone)
+	}
+	return lClones
+}
+
+// ValidateResourceLocations checks that resource locations are all present for the unit type
+// and that they add up to the unit total armor and structure
+func ValidateResourceLocations(unitType UnitType, armor, structure float64, rLocations []*ModelResourceLocation) error {
+	if len(rLocations) == 0 {
+		return nil
+	}
+
+	layout, ok := unitLocationLayouts[unitType]
+	if !ok {
+		return fmt.Errorf("unit type does not support locations")
+	}
+
+	found := make(map[Location]bool, len(rLocations))
+	var armorSum, structureSum float64
+	for _, rLocation := range rLocations {
+		l := rLocation.Location.Location
+		if !InArray(layout.locations, l) {
+			return fmt.Errorf("location '%s' is not valid for unit type", l.ShortName())
+		}
+		if found[l] {
+			return fmt.Errorf("location '%s' is listed more than once", l.ShortName())
+		}
+		found[l] = true
+		armorSum += rLocation.Armor
+		structureSum += rLocation.Structure
+	}
+
+	for _, l := range layout.locations {
+		if !found[l] {
+			return fmt.Errorf("location '%s' is missing", l.ShortName())
+		}
+	}
+
+	if !geom.NearlyEqual(armorSum, armor, 0.01) {
+		return fmt.Errorf("location armor total (%0.1f) does not match unit armor (%0.1f)", armorSum, armor)
+	}
+	if !geom.NearlyEqual(structureSum, structure, 0.01) {
+		return fmt.Errorf("location structure total (%0.1f) does not match unit structure (%0.1f)", structureSum, structure)
+	}
+	return nil
+}
+
+func (l *UnitLocation) Location() Location {
+	return l.location
+}
+
+func (l *UnitLocation) ArmorPoints() float64 {
+	return l.armor
+}
+
+func (l *UnitLocation) MaxArmorPoints() float64 {
+	return l.maxArmor
+}
+
+func (l *UnitLocation) SetArmorPoints(armor float64) {
+	l.armor = geom.Clamp(armor, 0, l.maxArmor)
+}
+
+func (l *UnitLocation) StructurePoints() float64 {
+	return l.structure
+}
+
+func (l *UnitLocation) MaxStructurePoints() float64 {
+	return l.maxStructure
+}
+
+func (l *UnitLocation) SetStructurePoints(structure float64) {
+	l.structure = geom.Clamp(structure, 0, l.maxStructure)
+}
+
+func (l *UnitLocation) IsDestroyed() bool {
+	return l.structure <= 0
+}
+
+// applyDamage applies damage to armor first (unless internal) then structure,
+// returns the amount taken by each and any excess beyond the remaining structure
+func (l *UnitLocation) applyDamage(damage float64, internal bool) (armorDamage, structureDamage, excess float64) {
+	if l.IsDestroyed() {
+		return 0, 0, damage
+	}
+
+	if !internal {
+		armorDamage = math.Min(damage, l.armor)
+		l.armor -= armorDamage
+		damage -= armorDamage
+	}
+
+	structureDamage = math.Min(damage, l.structure)
+	l.structure -= structureDamage
+	excess = damage - structureDamage
+	return
+}
+
+func (e *UnitModel) Locations() []*UnitLocation {
+	return e.locations
+}
+
+func (e *UnitModel) GetLocation(location Location) *UnitLocation {
+	for _, l := range e.locations {
+		if l.location == location {
+			return l
+		}
+	}
+	return nil
+}
+
+// ApplyLocationDamage applies damage to a specific location, transferring any excess
+// inward after the location is destroyed. Returns the locations that took structure damage.
+func (e *UnitModel) ApplyLocationDamage(damage float64, location Location) []Location {
+	return e.applyLocationDamage(damage, location, false)
+}
+
+func (e *UnitModel) applyLocationDamage(damage float64, location Location, internal bool) []Location {
+	if damage <= 0 {
+		return nil
+	}
+
+	layout, ok := unitLocationLayouts[e.unitType]
+	l := e.GetLocation(location)
+	if !ok || l == nil {
+		// unit does not use locations
+		e.applyTotalDamage(damage)
+		return nil
+	}
+
+	e.hasDamage = true
+	var damaged []Location
+	for l != nil && damage > 0 && !e.IsDestroyed() {
+		wasDestroyed := l.IsDestroyed()
+		armorDamage, structureDamage, excess := l.applyDamage(damage, internal)
+		e.armor = math.Max(e.armor-armorDamage, 0)
+		e.structure = math.Max(e.structure-structureDamage, 0)
+
+		if structureDamage > 0 {
+			damaged = append(damaged, l.location)
+		}
+		if !wasDestroyed && l.IsDestroyed() {
+			e.destroyLocation(layout, l)
+		}
+
+		damage = excess
+		transfer, ok := layout.transfer[l.location]
+		if !ok {
+			break
+		}
+		l = e.GetLocation(transfer)
+	}
+	return damaged
+}
+
+// destroyLocation handles the loss of a location and the equipment mounted in it
+func (e *UnitModel) destroyLocation(layout *unitLocationLayout, l *UnitLocation) {
+	for _, w := range e.armament {
+		if w.Location() == l.location {
+			w.SetDestroyed(true)
+		}
+	}
+
+	if layout.vital[l.location] {
+		// losing a vital location destroys the unit
+		e.structure = 0
+		return
+	}
+
+	if dLocation, ok := layout.dependent[l.location]; ok {
+		// dependent location (e.g. arm of a side torso) is lost along with it
+		d := e.GetLocation(dLocation)
+		if d != nil && !d.IsDestroyed() {
+			e.armor = math.Max(e.armor-d.armor, 0)
+			e.structure = math.Max(e.structure-d.structure, 0)
+			d.armor, d.structure = 0, 0
+			e.destroyLocation(layout, d)
+		}
+	}
+}
+
+// HitLocation determines the location hit on the unit based on the impact point and height
+func (e *UnitModel) HitLocation(hitPos *geom.Vector2, hitZ float64) Location {
+	layout, ok := unitLocationLayouts[e.unitType]
+	if !ok || len(e.locations) == 0 {
+		return 0
+	}
+	if hitPos == nil {
+		return layout.defaultHit
+	}
+
+	// relative height of impact on the unit from 0 (bottom) to 1 (top)
+	var hitHeight float64
+	minZ := e.positionZ
+	switch e.anchor {
+	case raycaster.AnchorCenter:
+		minZ -= e.collisionHeight / 2
+	case raycaster.AnchorTop:
+		minZ -= e.collisionHeight
+	}
+	if e.collisionHeight > 0 {
+		hitHeight = geom.Clamp((hitZ-minZ)/e.collisionHeight, 0, 1)
+	}
+
+	// relative angle of impact from the direction the unit is facing (positive is to the left)
+	hitAngle := math.Atan2(hitPos.Y-e.position.Y, hitPos.X-e.position.X)
+	relAngle := AngleDistance(e.TurretAngle(), hitAngle)
+	relLegs := AngleDistance(e.heading, hitAngle)
+
+	// lateral offset of impact from center line from -1 (right) to 1 (left)
+	lateral := math.Sin(relAngle)
+
+	switch e.unitType {
+	case MechUnitType:
+		switch {
+		case hitHeight < 0.45:
+			if math.Sin(relLegs) >= 0 {
+				return LEFT_LEG
+			}
+			return RIGHT_LEG
+		case hitHeight >= 0.85 && math.Abs(lateral) < 0.35:
+			return HEAD
+		case math.Abs(lateral) < 0.3:
+			return CENTER_TORSO
+		case math.Abs(lateral) < 0.7 || hitHeight >= 0.85:
+			if lateral > 0 {
+				return LEFT_TORSO
+			}
+			return RIGHT_TORSO
+		default:
+			if lateral > 0 {
+				return LEFT_ARM
+			}
+			return RIGHT_ARM
+		}
+
+	case VehicleUnitType:
+		if hitHeight >= 0.6 {
+			return TURRET
+		}
+		return hitSideLocation(relLegs)
+
+	case VTOLUnitType:
+		if hitHeight >= 0.8 {
+			return ROTOR
+		}
+		return hitSideLocation(relLegs)
+	}
+
+	return layout.defaultHit
+}
+
+// hitSideLocation determines front, rear, or side location from the relative angle of impact
+func hitSideLocation(relAngle float64) Location {
+	switch {
+	case math.Abs(relAngle) <= geom.Pi/4:
+		return FRONT
+	case math.Abs(relAngle) >= 3*geom.Pi/4:
+		return REAR
+	case relAngle > 0:
+		return LEFT
+	default:
+		return RIGHT
+	}
+}
@@ -0,0 +1,247 @@
+package model
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/harbdog/raycaster-go/geom"
+	"github.com/harbdog/raycaster-go/geom3d"
+)
+
+// UnitState is the persistent damage and resource state of a unit that carries over between missions
+type UnitState struct {
+	Armor            float64              `json:"armor"`
+	Structure        float64              `json:"structure"`
+	Locations        []*UnitLocationState `json:"locations,omitempty"`
+	DestroyedWeapons []int                `json:"destroyed_weapons,omitempty"`
+	Ammo             []int                `json:"ammo,omitempty"`
+	HeatSinks        int                  `json:"heat_sinks"`
+	JumpJets         int                  `json:"jump_jets"`
+}
+
+// UnitLocationState is the armor and structure remaining in a single unit location
+type UnitLocationState struct {
+	Location  Location `json:"location"`
+	Armor     float64  `json:"armor"`
+	Structure float64  `json:"structure"`
+}
+
+// UnitState returns the current damage and resource state of the unit
+func (e *UnitModel) UnitState() *UnitState {
+	s := &UnitState{
+		Armor:     e.armor,
+		Structure: e.structure,
+		HeatSinks: e.heatSinks,
+		JumpJets:  e.jumpJets,
+	}
+
+	for _, l := range e.locations {
+		s.Locations = append(s.Locations, &UnitLocationState{
+			Location:  l.location,
+			Armor:     l.armor,
+			Structure: l.structure,
+		})
+	}
+
+	// weapons are referenced by index in the armament list since the same weapon can be mounted more than once
+	for i, w := range e.armament {
+		if w.Destroyed() {
+			s.DestroyedWeapons = append(s.DestroyedWeapons, i)
+		}
+	}
+
+	if e.ammunition != nil {
+		for _, ammoBin := range e.ammunition.AmmoBinList() {
+			s.Ammo = append(s.Ammo, ammoBin.ammoCount)
+		}
+	}
+
+	return s
+}
+
+// SetUnitState applies a previously saved damage and resource state to the unit, which needs
+// to have been newly created from the same resource the state was saved from
+func (e *UnitModel) SetUnitState(s *UnitState) error {
+	if s == nil {
+		return nil
+	}
+
+	if len(s.Locations) != len(e.locations) {
+		return fmt.Errorf("unit state has %d locations, expected %d", len(s.Locations), len(e.locations))
+	}
+	for _, i := range s.DestroyedWeapons {
+		if i < 0 || i >= len(e.armament) {
+			return fmt.Errorf("unit state has invalid destroyed weapon index: %d", i)
+		}
+	}
+	var ammoBins []*AmmoBin
+	if e.ammunition != nil {
+		ammoBins = e.ammunition.AmmoBinList()
+	}
+	if len(s.Ammo) != len(ammoBins) {
+		return fmt.Errorf("unit state has %d ammo bins, expected %d", len(s.Ammo), len(ammoBins))
+	}
+
+	for _, sl := range s.Locations {
+		l := e.GetLocation(sl.Location)
+		if l == nil {
+			return fmt.Errorf("unit state has invalid location: %d", sl.Location)
+		}
+		l.SetArmorPoints(sl.Armor)
+		l.SetStructurePoints(sl.Structure)
+	}
+
+	// unit totals are set directly since a destroyed vital location zeroes structure for the whole unit
+	if s.Armor < e.armor || s.Structure < e.structure {
+		e.hasDamage = true
+	}
+	e.armor = math.Max(math.Min(s.Armor, e.armor), 0)
+	e.structure = math.Max(math.Min(s.Structure, e.structure), 0)
+
+	for _, i := range s.DestroyedWeapons {
+		e.armament[i].SetDestroyed(true)
+	}
+	for i, ammoBin := range ammoBins {
+		ammoBin.ammoCount = max(min(s.Ammo[i], ammoBin.ammoMax), 0)
+	}
+
+	e.SetHeatSinks(min(s.HeatSinks, e.heatSinks))
+	e.jumpJets = max(min(s.JumpJets, e.jumpJets), 0)
+
+	if len(s.DestroyedWeapons) > 0 {
+		e.hasDamage = true
+	}
+	return nil
+}
+
+// SetHeatSinks sets the number of working heat sinks, updating heat dissipation to match
+func (e *UnitModel) SetHeatSinks(heatSinks int) {
+	e.heatSinks = max(heatSinks, 0)
+	e.heatDissipation = SECONDS_PER_TICK / 4 * float64(e.heatSinks) * float64(e.heatSinkType)
+}
+
+func (e *UnitModel) HeatSinks() int {
+	return e.heatSinks
+}
+
+// UnitMissionState is the in-mission state of a unit, in addition to its damage state, used to save and restore a mission in progress
+type UnitMissionState struct {
+	*UnitState
+
+	Position          [2]float64      `json:"position"`
+	PositionZ         float64         `json:"position_z"`
+	Heading           float64         `json:"heading"`
+	TargetHeading     float64         `json:"target_heading"`
+	Pitch             float64         `json:"pitch"`
+	TargetPitch       float64         `json:"target_pitch"`
+	TurretAngle       float64         `json:"turret_angle"`
+	TargetTurretAngle float64         `json:"target_turret_angle"`
+	Velocity          float64         `json:"velocity"`
+	TargetVelocity    float64         `json:"target_velocity"`
+	VelocityZ         float64         `json:"velocity_z"`
+	TargetVelocityZ   float64         `json:"target_velocity_z"`
+	Heat              float64         `json:"heat"`
+	Powered           UnitPowerStatus `json:"powered"`
+	TargetLock        float64         `json:"target_lock"`
+	Cooldowns         []float64       `json:"cooldowns"`
+
+	JumpJetsActive      bool        `json:"jump_jets_active"`
+	JumpJetsDirectional bool        `json:"jump_jets_directional"`
+	JumpJetHeading      float64     `json:"jump_jet_heading"`
+	JumpJetDelay        float64     `json:"jump_jet_delay"`
+	JumpJetDuration     float64     `json:"jump_jet_duration"`
+	JumpJetVector       *[6]float64 `json:"jump_jet_vector,omitempty"`
+
+	// remaining patrol, guard or withdraw path points
+	PathStack [][2]float64 `json:"path_stack,omitempty"`
+}
+
+// UnitMissionState returns the current in-mission state of the unit
+func (e *UnitModel) UnitMissionState() *UnitMissionState {
+	s := &UnitMissionState{
+		UnitState:           e.UnitState(),
+		Position:            [2]float64{e.position.X, e.position.Y},
+		PositionZ:           e.positionZ,
+		Heading:             e.heading,
+		TargetHeading:       e.targetHeading,
+		Pitch:               e.pitch,
+		TargetPitch:         e.targetPitch,
+		TurretAngle:         e.turretAngle,
+		TargetTurretAngle:   e.targetTurretAngle,
+		Velocity:            e.velocity,
+		TargetVelocity:      e.targetVelocity,
+		VelocityZ:           e.velocityZ,
+		TargetVelocityZ:     e.targetVelocityZ,
+		Heat:                e.heat,
+		Powered:             e.powered,
+		TargetLock:          e.targetLock,
+		JumpJetsActive:      e.jumpJetsActive,
+		JumpJetsDirectional: e.jumpJetsDirectional,
+		JumpJetHeading:      e.jumpJetHeading,
+		JumpJetDelay:        e.jumpJetDelay,
+		JumpJetDuration:     e.jumpJetDuration,
+	}
+
+	s.Cooldowns = make([]float64, 0, len(e.armament))
+	for _, w := range e.armament {
+		s.Cooldowns = append(s.Cooldowns, w.Cooldown())
+	}
+
+	if v := e.jumpJetVector; v != nil {
+		s.JumpJetVector = &[6]float64{v.X1, v.Y1, v.Z1, v.X2, v.Y2, v.Z2}
+	}
+
+	if e.pathStack != nil {
+		for _, p := range e.pathStack.Values() {
+			s.PathStack = append(s.PathStack, [2]float64{p.X, p.Y})
+		}
+	}
+
+	return s
+}
+
+// SetUnitMissionState restores the in-mission state of the unit, which needs to have been newly created
+// from the same resource the state was saved from
+func (e *UnitModel) SetUnitMissionState(s *UnitMissionState) error {
+	if s == nil {
+		return nil
+	}
+	if len(s.Cooldowns) != len(e.armament) {
+		return fmt.Errorf("unit state has %d weapon cooldowns, expected %d", len(s.Cooldowns), len(e.armament))
+	}
+	if err := e.SetUnitState(s.UnitState); err != nil {
+		return err
+	}
+
+	e.position = &geom.Vector2{X: s.Position[0], Y: s.Position[1]}
+	e.positionZ = s.PositionZ
+	e.heading, e.targetHeading = s.Heading, s.TargetHeading
+	e.pitch, e.targetPitch = s.Pitch, s.TargetPitch
+	e.turretAngle, e.targetTurretAngle = s.TurretAngle, s.TargetTurretAngle
+	e.velocity, e.targetVelocity = s.Velocity, s.TargetVelocity
+	e.velocityZ, e.targetVelocityZ = s.VelocityZ, s.TargetVelocityZ
+	e.heat = s.Heat
+	e.powered = s.Powered
+	e.targetLock = s.TargetLock
+
+	for i, w := range e.armament {
+		// weapons only expose cooldown changes relative to their max
+		w.TriggerCooldown()
+		w.DecreaseCooldown(w.Cooldown() - s.Cooldowns[i])
+	}
+
+	e.jumpJetsActive, e.jumpJetsDirectional = s.JumpJetsActive, s.JumpJetsDirectional
+	e.jumpJetHeading, e.jumpJetDelay, e.jumpJetDuration = s.JumpJetHeading, s.JumpJetDelay, s.JumpJetDuration
+	e.jumpJetVector = nil
+	if v := s.JumpJetVector; v != nil {
+		e.jumpJetVector = &geom3d.Line3d{X1: v[0], Y1: v[1], Z1: v[2], X2: v[3], Y2: v[4], Z2: v[5]}
+	}
+
+	if e.pathStack != nil {
+		e.pathStack.Reset()
+		for _, p := range s.PathStack {
+			e.pathStack.Push(geom.Vector2{X: p[0], Y: p[1]})
+		}
+	}
+	return nil
+}
@@ -25,10 +25,12 @@ func NewVehicle(r *ModelVehicleResource) *Vehicle {
 		UnitModel: &UnitModel{
 			name:            r.Name,
 			variant:         r.Variant,
+			destroyEffects:  r.DestroyEffects,
 			unitType:        VehicleUnitType,
 			anchor:          raycaster.AnchorBottom,
 			armor:           r.Armor,
 			structure:       r.Structure,
+			locations:       NewUnitLocations(VehicleUnitType, r.Armor, r.Structure, r.Locations),
 			heatSinks:       r.HeatSinks.Quantity,
 			heatSinkType:    r.HeatSinks.Type.HeatSinkType,
 			armament:        make([]Weapon, 0),
@@ -40,6 +42,8 @@ func NewVehicle(r *ModelVehicleResource) *Vehicle {
 			maxTurretRate:   VEHICLE_TURRET_RATE_FACTOR + (100 / r.Tonnage * VEHICLE_TURRET_RATE_FACTOR),
 			jumpJets:        0,
 			powered:         POWER_ON,
+			sensorRange:     sensorRangeUnits(r.SensorRange),
+			equipment:       r.Equipment,
 		},
 	}
 
@@ -81,6 +85,9 @@ func (e *Vehicle) CloneUnit() Unit {
 		eClone.AddArmament(weapon.Clone())
 	}
 
+	// locations need to be cloned so damage is not shared with the original
+	eClone.locations = CloneUnitLocations(e.locations)
+
 	return eClone
 }
 
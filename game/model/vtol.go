@@ -22,21 +22,25 @@ func NewVTOL(r *ModelVTOLResource) *VTOL {
 	m := &VTOL{
 		Resource: r,
 		UnitModel: &UnitModel{
-			name:          r.Name,
-			variant:       r.Variant,
-			unitType:      VTOLUnitType,
-			anchor:        raycaster.AnchorCenter,
-			armor:         r.Armor,
-			structure:     r.Structure,
-			heatSinks:     r.HeatSinks.Quantity,
-			heatSinkType:  r.HeatSinks.Type.HeatSinkType,
-			armament:      make([]Weapon, 0),
-			ammunition:    NewAmmoStock(),
-			maxVelocity:   r.Speed * KPH_TO_VELOCITY,
-			maxTurnRate:   VTOL_TURN_RATE_FACTOR + (100 / r.Tonnage * VTOL_TURN_RATE_FACTOR),
-			maxTurretRate: VTOL_TURN_RATE_FACTOR + (100 / r.Tonnage * VTOL_TURN_RATE_FACTOR),
-			jumpJets:      0,
-			powered:       POWER_ON,
+			name:           r.Name,
+			variant:        r.Variant,
+			destroyEffects: r.DestroyEffects,
+			unitType:       VTOLUnitType,
+			anchor:         raycaster.AnchorCenter,
+			armor:          r.Armor,
+			structure:      r.Structure,
+			locations:      NewUnitLocations(VTOLUnitType, r.Armor, r.Structure, r.Locations),
+			heatSinks:      r.HeatSinks.Quantity,
+			heatSinkType:   r.HeatSinks.Type.HeatSinkType,
+			armament:       make([]Weapon, 0),
+			ammunition:     NewAmmoStock(),
+			maxVelocity:    r.Speed * KPH_TO_VELOCITY,
+			maxTurnRate:    VTOL_TURN_RATE_FACTOR + (100 / r.Tonnage * VTOL_TURN_RATE_FACTOR),
+			maxTurretRate:  VTOL_TURN_RATE_FACTOR + (100 / r.Tonnage * VTOL_TURN_RATE_FACTOR),
+			jumpJets:       0,
+			powered:        POWER_ON,
+			sensorRange:    sensorRangeUnits(r.SensorRange),
+			equipment:      r.Equipment,
 		},
 	}
 
@@ -78,6 +82,9 @@ func (e *VTOL) CloneUnit() Unit {
 		eClone.AddArmament(weapon.Clone())
 	}
 
+	// locations need to be cloned so damage is not shared with the original
+	eClone.locations = CloneUnitLocations(e.locations)
+
 	return eClone
 }
 
@@ -96,6 +96,7 @@ type Weapon interface {
 	SetDestroyed(isDestroyed bool)
 
 	Audio() string
+	MuzzleEffect() string
 	Clone() Weapon
 	Parent() Entity
 }
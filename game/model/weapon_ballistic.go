@@ -242,6 +242,10 @@ func (w *BallisticWeapon) Offset() *geom.Vector2 {
 	return w.offset
 }
 
+func (w *BallisticWeapon) MuzzleEffect() string {
+	return w.Resource.MuzzleEffect
+}
+
 func (w *BallisticWeapon) Audio() string {
 	return w.audio
 }
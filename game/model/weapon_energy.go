@@ -238,6 +238,10 @@ func (w *EnergyWeapon) Offset() *geom.Vector2 {
 	return w.offset
 }
 
+func (w *EnergyWeapon) MuzzleEffect() string {
+	return w.Resource.MuzzleEffect
+}
+
 func (w *EnergyWeapon) Audio() string {
 	return w.audio
 }
@@ -334,6 +334,10 @@ func (w *MissileWeapon) Offset() *geom.Vector2 {
 	return w.offset
 }
 
+func (w *MissileWeapon) MuzzleEffect() string {
+	return w.Resource.MuzzleEffect
+}
+
 func (w *MissileWeapon) Audio() string {
 	return w.audio
 }
@@ -0,0 +1,163 @@
+package game
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/harbdog/raycaster-go/geom"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+)
+
+// MULTIPLAYER_VERSION is the multiplayer protocol version, it needs to be incremented when messages change
+// or when game updates change such that clients and server would no longer agree
+const MULTIPLAYER_VERSION = 1
+
+const (
+	// MULTIPLAYER_DEFAULT_PORT is the TCP port the server listens on if not provided
+	MULTIPLAYER_DEFAULT_PORT = 7707
+
+	// NET_SNAPSHOT_TICKS is the number of ticks between each state snapshot sent by the server
+	NET_SNAPSHOT_TICKS = 3
+
+	// NET_CORRECTION_DISTANCE is how far the client player position can be from the server before it is corrected
+	NET_CORRECTION_DISTANCE = 1.0
+
+	// NET_TIMEOUT is how long to wait for the handshake or for a message to be written before giving up
+	NET_TIMEOUT = 10 * time.Second
+
+	// NET_WRITE_BUFFER is the number of messages that can wait to be written to each client before the oldest is dropped
+	NET_WRITE_BUFFER = 4
+)
+
+// NetMessage is a single newline delimited JSON message between client and server, with only one of its fields set
+type NetMessage struct {
+	Hello    *NetHello    `json:"hello,omitempty"`
+	Welcome  *NetWelcome  `json:"welcome,omitempty"`
+	Input    *NetInput    `json:"input,omitempty"`
+	Snapshot *NetSnapshot `json:"snapshot,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// NetHello is sent by the client when it connects to join the mission
+type NetHello struct {
+	Version int    `json:"version"`
+	Name    string `json:"name,omitempty"`
+	Mech    string `json:"mech"`
+	Team    int    `json:"team"`
+}
+
+// NetWelcome is sent by the server once the client unit has joined the mission
+type NetWelcome struct {
+	Version int    `json:"version"`
+	Mission string `json:"mission"`
+	Seed    int64  `json:"seed"`
+	// sprite sequence of the client unit
+	Sequence uint64 `json:"sequence"`
+}
+
+// NetInput is the client player piloting sent to the server each tick
+type NetInput struct {
+	TargetVelocity      float64               `json:"target_velocity"`
+	TargetVelocityZ     float64               `json:"target_velocity_z"`
+	TargetHeading       float64               `json:"target_heading"`
+	TargetTurretAngle   float64               `json:"target_turret_angle"`
+	TargetPitch         float64               `json:"target_pitch"`
+	JumpJetsActive      bool                  `json:"jump_jets_active,omitempty"`
+	JumpJetsDirectional bool                  `json:"jump_jets_directional,omitempty"`
+	JumpJetHeading      float64               `json:"jump_jet_heading,omitempty"`
+	Powered             model.UnitPowerStatus `json:"powered"`
+	// sprite sequence of the target unit, 0 if none
+	Target     uint64  `json:"target,omitempty"`
+	TargetLock float64 `json:"target_lock,omitempty"`
+	// index in the armament of each weapon fired since the last input
+	Fire []int `json:"fire,omitempty"`
+}
+
+// NetSnapshot is the state of the mission sent by the server to all clients
+type NetSnapshot struct {
+	Tick           uint                 `json:"tick"`
+	MissionSeconds float64              `json:"mission_seconds"`
+	NavVisited     []bool               `json:"nav_visited"`
+	Objectives     []*SaveGameObjective `json:"objectives"`
+	Units          []*NetUnit           `json:"units"`
+	Projectiles    []*NetProjectile     `json:"projectiles,omitempty"`
+}
+
+// NetUnit is the state of a unit in play, referenced by the sequence its sprite was added in on the server
+type NetUnit struct {
+	Sequence uint64 `json:"sequence"`
+	// mech resource needed by clients to create units that were not part of the mission when it was loaded
+	Mech          string                  `json:"mech,omitempty"`
+	ID            string                  `json:"id,omitempty"`
+	Team          int                     `json:"team"`
+	Player        bool                    `json:"player,omitempty"`
+	State         *model.UnitMissionState `json:"state"`
+	PowerOnTimer  int                     `json:"power_on_timer,omitempty"`
+	PowerOffTimer int                     `json:"power_off_timer,omitempty"`
+}
+
+// NetProjectile is the state of a projectile in flight
+type NetProjectile struct {
+	Sequence uint64                 `json:"sequence"`
+	Parent   uint64                 `json:"parent"`
+	Weapon   int                    `json:"weapon"`
+	State    *model.ProjectileState `json:"state"`
+}
+
+// netConn reads and writes newline delimited JSON messages
+type netConn struct {
+	conn    net.Conn
+	decoder *json.Decoder
+	writer  *bufio.Writer
+}
+
+func newNetConn(conn net.Conn) *netConn {
+	return &netConn{
+		conn:    conn,
+		decoder: json.NewDecoder(bufio.NewReader(conn)),
+		writer:  bufio.NewWriter(conn),
+	}
+}
+
+func (c *netConn) read() (*NetMessage, error) {
+	msg := &NetMessage{}
+	if err := c.decoder.Decode(msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (c *netConn) write(msg *NetMessage) error {
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return c.writeBytes(msgBytes)
+}
+
+// writeBytes writes an already marshaled message, so the same snapshot can be sent to every client
+func (c *netConn) writeBytes(msgBytes []byte) error {
+	c.conn.SetWriteDeadline(time.Now().Add(NET_TIMEOUT))
+	if _, err := c.writer.Write(msgBytes); err != nil {
+		return err
+	}
+	if err := c.writer.WriteByte('\n'); err != nil {
+		return err
+	}
+	return c.writer.Flush()
+}
+
+func (c *netConn) close() {
+	c.conn.Close()
+}
+
+// lerpAngle interpolates between two angles in radians in the direction of the smallest difference
+func lerpAngle(from, to, t float64) float64 {
+	delta := model.ClampAngle2Pi(to - from)
+	if delta > geom.Pi {
+		delta -= geom.Pi2
+	}
+	return from + delta*t
+}
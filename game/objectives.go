@@ -2,8 +2,10 @@ package game
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/harbdog/raycaster-go/geom"
 	"github.com/pixelmek-3d/pixelmek-3d/game/model"
 	log "github.com/sirupsen/logrus"
 )
@@ -16,12 +18,30 @@ const (
 	OBJECTIVES_FAILED
 )
 
+func (s ObjectivesStatus) String() string {
+	switch s {
+	case OBJECTIVES_IN_PROGRESS:
+		return "in progress"
+	case OBJECTIVES_COMPLETED:
+		return "completed"
+	case OBJECTIVES_FAILED:
+		return "failed"
+	}
+	return "unknown"
+}
+
 type ObjectivesHandler struct {
 	objectives *model.MissionObjectives
 	current    map[Objective]time.Time
 	completed  map[Objective]time.Time
 	failed     map[Objective]time.Time
 
+	// order of objectives as they were created, for saving and restoring their status
+	order []Objective
+
+	// objectives that can be referenced by mission triggers
+	byID map[string]Objective
+
 	objectivesText string
 }
 
@@ -30,12 +50,24 @@ type Objective interface {
 	Current() bool
 	Completed() bool
 	Failed() bool
+	Bonus() bool
 	Text() string
 }
 
+// bannerObjective is an objective with progress to show in the HUD banner while it is in progress
+type bannerObjective interface {
+	BannerText() string
+}
+
 type BasicObjective struct {
 	completed bool
 	failed    bool
+
+	// bonus objectives are not needed to complete the mission and do not fail it
+	bonus bool
+
+	// progress of timed objectives in seconds
+	progress float64
 }
 
 type PlayerAliveObjective struct {
@@ -67,6 +99,32 @@ type DustoffObjective struct {
 	verifyDustoff bool
 }
 
+type SurviveObjective struct {
+	*BasicObjective
+	objective *model.MissionSurviveObjectives
+}
+
+type EscortObjective struct {
+	*BasicObjective
+	objective *model.MissionEscortObjectives
+	unit      model.Unit
+	nav       *model.NavPoint
+}
+
+type DefendObjective struct {
+	*BasicObjective
+	objective *model.MissionNavDefend
+	nav       *model.NavPoint
+}
+
+type CaptureObjective struct {
+	*BasicObjective
+	objective *model.MissionNavCapture
+	nav       *model.NavPoint
+	occupied  bool
+	contested bool
+}
+
 func (o *BasicObjective) Current() bool {
 	return !o.completed && !o.failed
 }
@@ -79,6 +137,10 @@ func (o *BasicObjective) Failed() bool {
 	return o.failed
 }
 
+func (o *BasicObjective) Bonus() bool {
+	return o.bonus
+}
+
 func NewObjectivesHandler(g *Game, objectives *model.MissionObjectives) *ObjectivesHandler {
 	if objectives == nil {
 		// default objectives: destroy all
@@ -96,8 +158,22 @@ func NewObjectivesHandler(g *Game, objectives *model.MissionObjectives) *Objecti
 		current:    make(map[Objective]time.Time),
 		completed:  make(map[Objective]time.Time),
 		failed:     make(map[Objective]time.Time),
+		byID:       make(map[string]Objective),
 	}
 
+	o.addObjectives(g, objectives)
+	o.updateObjectivesText()
+
+	return o
+}
+
+// AddObjectives adds more objectives to the mission in progress, such as from a mission trigger
+func (o *ObjectivesHandler) AddObjectives(g *Game, objectives *model.MissionObjectives) {
+	o.addObjectives(g, objectives)
+	o.updateObjectivesText()
+}
+
+func (o *ObjectivesHandler) addObjectives(g *Game, objectives *model.MissionObjectives) {
 	all_units := g.getSpriteUnits()
 	var iTime time.Time
 
@@ -116,11 +192,13 @@ func NewObjectivesHandler(g *Game, objectives *model.MissionObjectives) *Objecti
 			}
 
 			protectObjective := &ProtectObjective{
-				BasicObjective: &BasicObjective{},
+				BasicObjective: &BasicObjective{bonus: modelObjective.Bonus},
 				objective:      modelObjective,
 				units:          protectUnits,
 			}
 			o.current[protectObjective] = iTime
+			o.order = append(o.order, protectObjective)
+			o.setID(modelObjective.ID, protectObjective)
 		}
 	}
 
@@ -150,11 +228,13 @@ func NewObjectivesHandler(g *Game, objectives *model.MissionObjectives) *Objecti
 			}
 
 			destroyObjective := &DestroyObjective{
-				BasicObjective: &BasicObjective{},
+				BasicObjective: &BasicObjective{bonus: modelObjective.Bonus},
 				objective:      modelObjective,
 				units:          destroyUnits,
 			}
 			o.current[destroyObjective] = iTime
+			o.order = append(o.order, destroyObjective)
+			o.setID(modelObjective.ID, destroyObjective)
 		}
 	}
 
@@ -164,13 +244,7 @@ func NewObjectivesHandler(g *Game, objectives *model.MissionObjectives) *Objecti
 			if len(navName) == 0 {
 				continue
 			}
-			var objectiveNav *model.NavPoint
-			for _, nav := range g.mission.NavPoints {
-				if navName == nav.Name {
-					objectiveNav = nav
-					break
-				}
-			}
+			objectiveNav := missionNavPoint(g, navName)
 			if objectiveNav == nil {
 				log.Errorf("visit objective nav point not found: %s", navName)
 				continue
@@ -178,11 +252,13 @@ func NewObjectivesHandler(g *Game, objectives *model.MissionObjectives) *Objecti
 
 			objectiveNav.SetObjective(model.NavVisitObjective)
 			visitObjective := &VisitObjective{
-				BasicObjective: &BasicObjective{},
+				BasicObjective: &BasicObjective{bonus: modelObjective.Bonus},
 				objective:      modelObjective,
 				nav:            objectiveNav,
 			}
 			o.current[visitObjective] = iTime
+			o.order = append(o.order, visitObjective)
+			o.setID(modelObjective.ID, visitObjective)
 		}
 
 		for _, modelObjective := range objectives.Nav.Dustoff {
@@ -190,13 +266,7 @@ func NewObjectivesHandler(g *Game, objectives *model.MissionObjectives) *Objecti
 			if len(navName) == 0 {
 				continue
 			}
-			var objectiveNav *model.NavPoint
-			for _, nav := range g.mission.NavPoints {
-				if navName == nav.Name {
-					objectiveNav = nav
-					break
-				}
-			}
+			objectiveNav := missionNavPoint(g, navName)
 			if objectiveNav == nil {
 				log.Errorf("dustoff objective nav point not found: %s", navName)
 				continue
@@ -204,37 +274,185 @@ func NewObjectivesHandler(g *Game, objectives *model.MissionObjectives) *Objecti
 
 			objectiveNav.SetObjective(model.NavDustoffObjective)
 			visitObjective := &DustoffObjective{
-				BasicObjective: &BasicObjective{},
+				BasicObjective: &BasicObjective{bonus: modelObjective.Bonus},
 				objective:      modelObjective,
 				nav:            objectiveNav,
 			}
 			o.current[visitObjective] = iTime
+			o.order = append(o.order, visitObjective)
+			o.setID(modelObjective.ID, visitObjective)
+		}
+
+		for _, modelObjective := range objectives.Nav.Defend {
+			objectiveNav := missionNavPoint(g, modelObjective.Name)
+			if objectiveNav == nil {
+				log.Errorf("defend objective nav point not found: %s", modelObjective.Name)
+				continue
+			}
+
+			objectiveNav.SetObjective(model.NavDefendObjective)
+			defendObjective := &DefendObjective{
+				BasicObjective: &BasicObjective{bonus: modelObjective.Bonus},
+				objective:      modelObjective,
+				nav:            objectiveNav,
+			}
+			o.current[defendObjective] = iTime
+			o.order = append(o.order, defendObjective)
+			o.setID(modelObjective.ID, defendObjective)
+		}
+
+		for _, modelObjective := range objectives.Nav.Capture {
+			objectiveNav := missionNavPoint(g, modelObjective.Name)
+			if objectiveNav == nil {
+				log.Errorf("capture objective nav point not found: %s", modelObjective.Name)
+				continue
+			}
+
+			objectiveNav.SetObjective(model.NavCaptureObjective)
+			captureObjective := &CaptureObjective{
+				BasicObjective: &BasicObjective{bonus: modelObjective.Bonus},
+				objective:      modelObjective,
+				nav:            objectiveNav,
+			}
+			o.current[captureObjective] = iTime
+			o.order = append(o.order, captureObjective)
+			o.setID(modelObjective.ID, captureObjective)
 		}
 	}
 
-	o.updateObjectivesText()
+	for _, modelObjective := range objectives.Escort {
+		var escortUnit model.Unit
+		for _, unit := range all_units {
+			if modelObjective.Unit == unit.ID() {
+				escortUnit = unit
+				break
+			}
+		}
+		if escortUnit == nil {
+			log.Errorf("escort objective unit not found: %s", modelObjective.Unit)
+			continue
+		}
+		objectiveNav := missionNavPoint(g, modelObjective.Nav)
+		if objectiveNav == nil {
+			log.Errorf("escort objective nav point not found: %s", modelObjective.Nav)
+			continue
+		}
 
-	return o
+		// escorted unit follows the path to the nav point
+		escortPath := model.PointsToVector2(modelObjective.Path)
+		escortPath = append(escortPath, objectiveNav.Pos())
+		escortUnit.SetPatrolPath(escortPath)
+		escortUnit.SetObjective(model.ProtectUnitObjective)
+
+		escortObjective := &EscortObjective{
+			BasicObjective: &BasicObjective{bonus: modelObjective.Bonus},
+			objective:      modelObjective,
+			unit:           escortUnit,
+			nav:            objectiveNav,
+		}
+		o.current[escortObjective] = iTime
+		o.order = append(o.order, escortObjective)
+		o.setID(modelObjective.ID, escortObjective)
+	}
+
+	for _, modelObjective := range objectives.Survive {
+		surviveObjective := &SurviveObjective{
+			BasicObjective: &BasicObjective{bonus: modelObjective.Bonus},
+			objective:      modelObjective,
+		}
+		o.current[surviveObjective] = iTime
+		o.order = append(o.order, surviveObjective)
+		o.setID(modelObjective.ID, surviveObjective)
+	}
+}
+
+// missionNavPoint returns the mission nav point with the name, or nil if not found
+func missionNavPoint(g *Game, name string) *model.NavPoint {
+	for _, nav := range g.mission.NavPoints {
+		if name == nav.Name {
+			return nav
+		}
+	}
+	return nil
+}
+
+func (o *ObjectivesHandler) setID(id string, objective Objective) {
+	if len(id) > 0 {
+		o.byID[id] = objective
+	}
+}
+
+// ObjectiveCompleted returns true if the objective with the ID has been completed
+func (o *ObjectivesHandler) ObjectiveCompleted(id string) bool {
+	objective, ok := o.byID[id]
+	return ok && objective.Completed()
+}
+
+// CompleteObjective marks the objective with the ID as completed, if still in progress
+func (o *ObjectivesHandler) CompleteObjective(id string) {
+	objective, ok := o.byID[id]
+	if !ok {
+		log.Errorf("objective ID not found: %s", id)
+		return
+	}
+	if objective.Current() {
+		log.Debugf("objective set completed: %s", id)
+		basicObjective(objective).completed = true
+	}
+}
+
+// FailObjective marks the objective with the ID as failed, if still in progress
+func (o *ObjectivesHandler) FailObjective(id string) {
+	objective, ok := o.byID[id]
+	if !ok {
+		log.Errorf("objective ID not found: %s", id)
+		return
+	}
+	if objective.Current() {
+		log.Debugf("objective set failed: %s", id)
+		basicObjective(objective).failed = true
+	}
+}
+
+// objectiveID returns the ID of the objective, or empty if it does not have one
+func (o *ObjectivesHandler) objectiveID(objective Objective) string {
+	for id, idObjective := range o.byID {
+		if idObjective == objective {
+			return id
+		}
+	}
+	return ""
+}
+
+// objectiveStatus returns the status of an individual objective
+func objectiveStatus(objective Objective) ObjectivesStatus {
+	switch {
+	case objective.Failed():
+		return OBJECTIVES_FAILED
+	case objective.Completed():
+		return OBJECTIVES_COMPLETED
+	}
+	return OBJECTIVES_IN_PROGRESS
 }
 
 func (o *ObjectivesHandler) Update(g *Game) {
 	update := false
 	currTime := time.Now()
 
-	objsDestroy := make([]*DestroyObjective, 0, 16)
+	// number of objectives, other than protect and dustoff, that are required to complete the mission
+	objsRequired := 0
 	objsProtect := make([]*ProtectObjective, 0, 16)
-	objsVisit := make([]*VisitObjective, 0, 4)
 	objsDustoff := make([]*DustoffObjective, 0, 1)
 	for objective := range o.current {
 		switch objective := objective.(type) {
-		case *DestroyObjective:
-			objsDestroy = append(objsDestroy, objective)
 		case *ProtectObjective:
 			objsProtect = append(objsProtect, objective)
-		case *VisitObjective:
-			objsVisit = append(objsVisit, objective)
 		case *DustoffObjective:
 			objsDustoff = append(objsDustoff, objective)
+		default:
+			if !objective.Bonus() {
+				objsRequired++
+			}
 		}
 
 		objective.Update(g)
@@ -256,9 +474,9 @@ func (o *ObjectivesHandler) Update(g *Game) {
 		update = true
 	}
 
-	// special handling for Nav.Dustoff which cannot be completed until after all destroy/visit, where applicable
+	// special handling for Nav.Dustoff which cannot be completed until after all other required objectives, where applicable
 	if len(objsDustoff) > 0 {
-		dustoffReady := (len(objsDestroy) == 0 && len(objsVisit) == 0)
+		dustoffReady := objsRequired == 0
 		dustoffComplete := false
 		for _, objective := range objsDustoff {
 			if !dustoffReady && objective.verifyDustoff {
@@ -284,8 +502,8 @@ func (o *ObjectivesHandler) Update(g *Game) {
 		}
 	}
 
-	// special handling for Protect.Unit which cannot be completed until after all destroy/visit/dustoff, where applicable
-	if len(objsProtect) > 0 && len(objsDestroy) == 0 && len(objsVisit) == 0 && len(objsDustoff) == 0 {
+	// special handling for Protect.Unit which cannot be completed until after all other required objectives, where applicable
+	if len(objsProtect) > 0 && objsRequired == 0 && len(objsDustoff) == 0 {
 		update = true
 		for _, objective := range objsProtect {
 			log.Debugf("protect objective completed: %s", objective.objective.Unit)
@@ -294,13 +512,8 @@ func (o *ObjectivesHandler) Update(g *Game) {
 	}
 
 	// special handling for player objective of staying alive
-	if g.player.IsDestroyed() {
-		update = true
-		o.failed[&PlayerAliveObjective{
-			BasicObjective: &BasicObjective{
-				failed: true,
-			},
-		}] = currTime
+	if g.playerUnitsDestroyed() {
+		o.fail()
 	}
 
 	if update {
@@ -312,42 +525,82 @@ func (o *ObjectivesHandler) updateObjectivesText() {
 	oText := ""
 
 	if len(o.current) > 0 {
-		for objective := range o.current {
-			oText += objective.Text() + "\n"
-		}
+		oText += objectivesListText(o.current)
 	}
 
 	if len(o.failed) > 0 {
 		oText += "\n*FAILED*\n"
-		for objective := range o.failed {
-			oText += objective.Text() + "\n"
-		}
+		oText += objectivesListText(o.failed)
 	}
 
 	if len(o.completed) > 0 {
 		oText += "\n-COMPLETED-\n"
-		for objective := range o.completed {
-			oText += objective.Text() + "\n"
-		}
+		oText += objectivesListText(o.completed)
 	}
 
 	o.objectivesText = oText
 }
 
+// objectivesListText returns the text of each objective sorted so it is listed in a consistent order
+func objectivesListText(objectives map[Objective]time.Time) string {
+	texts := make([]string, 0, len(objectives))
+	for objective := range objectives {
+		texts = append(texts, objective.Text())
+	}
+	sort.Strings(texts)
+
+	listText := ""
+	for _, text := range texts {
+		listText += text + "\n"
+	}
+	return listText
+}
+
 func (o *ObjectivesHandler) Text() string {
 	return o.objectivesText
 }
 
+// BannerText returns the progress of objectives in progress to show in the HUD banner, if any
+func (o *ObjectivesHandler) BannerText() string {
+	bannerText := ""
+	for _, objective := range o.order {
+		b, ok := objective.(bannerObjective)
+		if !ok || !objective.Current() {
+			continue
+		}
+		text := b.BannerText()
+		if len(text) == 0 {
+			continue
+		}
+		if len(bannerText) > 0 {
+			bannerText += "   "
+		}
+		bannerText += text
+	}
+	return bannerText
+}
+
 func (o *ObjectivesHandler) Status() ObjectivesStatus {
 	switch {
-	case len(o.failed) > 0:
+	case requiredCount(o.failed) > 0:
 		return OBJECTIVES_FAILED
-	case len(o.current) == 0 && len(o.completed) > 0:
+	case requiredCount(o.current) == 0 && requiredCount(o.completed) > 0:
 		return OBJECTIVES_COMPLETED
 	}
 	return OBJECTIVES_IN_PROGRESS
 }
 
+// requiredCount returns the number of objectives that are not optional bonus objectives
+func requiredCount(objectives map[Objective]time.Time) int {
+	count := 0
+	for objective := range objectives {
+		if !objective.Bonus() {
+			count++
+		}
+	}
+	return count
+}
+
 func (o *PlayerAliveObjective) Update(g *Game) {}
 func (o *PlayerAliveObjective) Text() string {
 	return ""
@@ -393,9 +646,9 @@ func (o *DestroyObjective) Update(g *Game) {
 }
 func (o *DestroyObjective) Text() string {
 	if o.objective.All {
-		return `Destroy All Enemies`
+		return `Destroy All Enemies` + model.ObjectiveBonusText(o.bonus)
 	}
-	return `Destroy ` + o.objective.Unit
+	return `Destroy ` + o.objective.Unit + model.ObjectiveBonusText(o.bonus)
 }
 
 func (o *ProtectObjective) Update(g *Game) {
@@ -413,7 +666,7 @@ func (o *ProtectObjective) Update(g *Game) {
 	}
 }
 func (o *ProtectObjective) Text() string {
-	return `Protect ` + o.objective.Unit
+	return `Protect ` + o.objective.Unit + model.ObjectiveBonusText(o.bonus)
 }
 
 func (o *VisitObjective) Update(g *Game) {
@@ -423,7 +676,7 @@ func (o *VisitObjective) Update(g *Game) {
 	}
 }
 func (o *VisitObjective) Text() string {
-	return `Visit Nav ` + o.objective.Name
+	return `Visit Nav ` + o.objective.Name + model.ObjectiveBonusText(o.bonus)
 }
 
 func (o *DustoffObjective) Update(g *Game) {
@@ -433,5 +686,187 @@ func (o *DustoffObjective) Update(g *Game) {
 	}
 }
 func (o *DustoffObjective) Text() string {
-	return `Dustoff Nav ` + o.objective.Name
+	return `Dustoff Nav ` + o.objective.Name + model.ObjectiveBonusText(o.bonus)
+}
+
+func (o *SurviveObjective) Update(g *Game) {
+	o.progress += model.SECONDS_PER_TICK
+	if o.progress >= o.objective.Seconds {
+		log.Debugf("survive objective completed: %0.0fs", o.objective.Seconds)
+		o.completed = true
+	}
+}
+func (o *SurviveObjective) Text() string {
+	return `Survive for ` + model.ObjectiveTimeText(o.objective.Seconds) + model.ObjectiveBonusText(o.bonus)
+}
+func (o *SurviveObjective) BannerText() string {
+	return `Survive ` + model.ObjectiveTimeText(o.objective.Seconds-o.progress)
+}
+
+func (o *EscortObjective) Update(g *Game) {
+	if o.unit.IsDestroyed() {
+		log.Debugf("escort objective failed: %s", o.objective.Unit)
+		o.failed = true
+		return
+	}
+
+	pos := o.unit.Pos()
+	navX, navY := o.nav.Position[0], o.nav.Position[1]
+	if model.PointInProximity(2.0, pos.X, pos.Y, navX, navY) {
+		log.Debugf("escort objective completed: %s", o.objective.Unit)
+		o.completed = true
+
+		// escorted unit holds position at the nav point
+		o.unit.SetPatrolPath([]geom.Vector2{o.nav.Pos()})
+	}
+}
+func (o *EscortObjective) Text() string {
+	return `Escort ` + o.objective.Unit + ` to Nav ` + o.objective.Nav + model.ObjectiveBonusText(o.bonus)
+}
+
+func (o *DefendObjective) Update(g *Game) {
+	navX, navY := o.nav.Position[0], o.nav.Position[1]
+	for _, unit := range g.getSpriteUnits() {
+		if unit.IsDestroyed() || g.isPlayerFriendly(unit) {
+			continue
+		}
+		pos := unit.Pos()
+		if model.PointInProximity(o.objective.Radius, pos.X, pos.Y, navX, navY) {
+			log.Debugf("defend objective failed: %s entered nav %s", unit.ID(), o.nav.Name)
+			o.failed = true
+			return
+		}
+	}
+
+	o.progress += model.SECONDS_PER_TICK
+	if o.progress >= o.objective.Seconds {
+		log.Debugf("defend objective completed: %s", o.nav.Name)
+		o.completed = true
+	}
+}
+func (o *DefendObjective) Text() string {
+	return `Defend Nav ` + o.objective.Name + ` for ` + model.ObjectiveTimeText(o.objective.Seconds) + model.ObjectiveBonusText(o.bonus)
+}
+func (o *DefendObjective) BannerText() string {
+	return `Defend Nav ` + o.objective.Name + ` ` + model.ObjectiveTimeText(o.objective.Seconds-o.progress)
+}
+
+func (o *CaptureObjective) Update(g *Game) {
+	navX, navY := o.nav.Position[0], o.nav.Position[1]
+
+	o.occupied = false
+	for _, unit := range g.playerUnits() {
+		pos := unit.Pos()
+		if !unit.IsDestroyed() && model.PointInProximity(o.objective.Radius, pos.X, pos.Y, navX, navY) {
+			o.occupied = true
+			break
+		}
+	}
+
+	o.contested = false
+	for _, unit := range g.getSpriteUnits() {
+		if unit.IsDestroyed() || g.isPlayerFriendly(unit) {
+			continue
+		}
+		pos := unit.Pos()
+		if model.PointInProximity(o.objective.Radius, pos.X, pos.Y, navX, navY) {
+			o.contested = true
+			break
+		}
+	}
+
+	if !o.occupied || o.contested {
+		return
+	}
+
+	o.progress += model.SECONDS_PER_TICK
+	if o.progress >= o.objective.Seconds {
+		log.Debugf("capture objective completed: %s", o.nav.Name)
+		o.completed = true
+	}
+}
+func (o *CaptureObjective) Text() string {
+	return `Capture Nav ` + o.objective.Name + model.ObjectiveBonusText(o.bonus)
+}
+func (o *CaptureObjective) BannerText() string {
+	if !o.occupied && o.progress == 0 {
+		return ""
+	}
+	bannerText := fmt.Sprintf("Capture Nav %s %0.0f%%", o.objective.Name, 100*o.progress/o.objective.Seconds)
+	if o.contested {
+		bannerText += ` (Contested)`
+	}
+	return bannerText
+}
+
+// setObjectiveStates sets the completed and failed status of each objective in the order they were created
+func (o *ObjectivesHandler) setObjectiveStates(states []*SaveGameObjective) error {
+	if len(states) != len(o.order) {
+		return fmt.Errorf("%d objective states, expected %d", len(states), len(o.order))
+	}
+
+	update := false
+	currTime := time.Now()
+	for i, objective := range o.order {
+		state := states[i]
+		basic := basicObjective(objective)
+		basic.progress = state.Progress
+		if objective.Completed() == state.Completed && objective.Failed() == state.Failed {
+			continue
+		}
+		basic.completed, basic.failed = state.Completed, state.Failed
+
+		delete(o.current, objective)
+		delete(o.completed, objective)
+		delete(o.failed, objective)
+		switch {
+		case objective.Failed():
+			o.failed[objective] = currTime
+		case objective.Completed():
+			o.completed[objective] = currTime
+		default:
+			o.current[objective] = currTime
+		}
+		update = true
+	}
+
+	if update {
+		o.updateObjectivesText()
+	}
+	return nil
+}
+
+// fail ends the mission as a failure regardless of the status of other objectives
+func (o *ObjectivesHandler) fail() {
+	o.failed[&PlayerAliveObjective{
+		BasicObjective: &BasicObjective{
+			failed: true,
+		},
+	}] = time.Now()
+	o.updateObjectivesText()
+}
+
+// basicObjective returns the completed and failed status of the objective
+func basicObjective(objective Objective) *BasicObjective {
+	switch objective := objective.(type) {
+	case *DestroyObjective:
+		return objective.BasicObjective
+	case *ProtectObjective:
+		return objective.BasicObjective
+	case *VisitObjective:
+		return objective.BasicObjective
+	case *DustoffObjective:
+		return objective.BasicObjective
+	case *SurviveObjective:
+		return objective.BasicObjective
+	case *EscortObjective:
+		return objective.BasicObjective
+	case *DefendObjective:
+		return objective.BasicObjective
+	case *CaptureObjective:
+		return objective.BasicObjective
+	case *PlayerAliveObjective:
+		return objective.BasicObjective
+	}
+	panic(fmt.Errorf("basic objective not implemented: %v", objective))
 }
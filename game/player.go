@@ -45,6 +45,9 @@ type Player struct {
 	currentNav        *sprites.NavSprite
 	ejectionPod       *sprites.ProjectileSprite
 
+	// autopilot is set when the player unit is piloted by AI, such as in headless simulation
+	autopilot bool
+
 	debugCameraTgt model.Unit
 	debugCameraMu  sync.Mutex
 }
@@ -70,18 +73,23 @@ func NewPlayer(unit model.Unit, sprite *sprites.Sprite, x, y, z, angle, pitch fl
 	p.SetPitch(pitch)
 	p.SetVelocity(0)
 
-	p.weaponGroups = getUnitWeaponGroups(unit)
+	p.setWeaponGroups(getUnitWeaponGroups(unit))
+
+	return p
+}
+
+// setWeaponGroups sets the weapon groups and resets weapon selection to the first weapon
+func (p *Player) setWeaponGroups(weaponGroups [][]model.Weapon) {
+	p.weaponGroups = weaponGroups
 	p.selectedWeapon = 0
 	p.selectedGroup = model.WEAPON_GROUP_NONE
-	if len(unit.Armament()) > 0 {
+	if len(p.Armament()) > 0 {
 		// initialize first selected weapon with the first group it is in
-		g := p.GetGroupsForWeapon(unit.Armament()[p.selectedWeapon])
+		g := p.GetGroupsForWeapon(p.Armament()[p.selectedWeapon])
 		if len(g) > 0 {
 			p.selectedGroup = g[0]
 		}
 	}
-
-	return p
 }
 
 func (p *Player) Heading() float64 {
@@ -354,7 +362,11 @@ func (p *Player) Eject(g *Game) bool {
 
 func (p *Player) Update() bool {
 	// handle player specific updates
-	if p.HasTurret() {
+	if p.autopilot {
+		// camera angle/pitch follows where the AI is piloting the unit
+		p.cameraAngle = p.TurretAngle()
+		p.cameraPitch = p.Pitch()
+	} else if p.HasTurret() {
 		// camera angle/pitch leads turret angle/pitch
 		p.SetTargetTurretAngle(p.cameraAngle)
 		p.SetTargetPitch(p.cameraPitch)
@@ -364,7 +376,8 @@ func (p *Player) Update() bool {
 		p.SetTargetPitch(p.cameraPitch)
 	}
 
-	posZ := p.PosZ()
+	// height above the terrain the unit is on
+	posZ := p.PosZ() - p.GroundZ()
 
 	// camera bobbing from mech movement
 	switch p.Unit.(type) {
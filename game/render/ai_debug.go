@@ -0,0 +1,89 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/pixelmek-3d/pixelmek-3d/game/render/fonts"
+	"github.com/tinne26/etxt"
+)
+
+var (
+	_colorAIDebugText = _colorDefaultYellow
+	_colorAIDebugPath = _colorDefaultYellow
+)
+
+type AIDebugOverlay struct {
+	HUDSprite
+	fontRenderer *etxt.Renderer
+	lines        []string
+	pathPoints   []image.Point
+}
+
+// NewAIDebugOverlay creates a developer debug overlay of unit AI behavior to be rendered on demand
+func NewAIDebugOverlay(font *fonts.Font) *AIDebugOverlay {
+	// create and configure font renderer
+	renderer := etxt.NewRenderer()
+	renderer.SetCacheHandler(font.FontCache.NewHandler())
+	renderer.SetFont(font.Font)
+
+	d := &AIDebugOverlay{
+		HUDSprite:    NewHUDSprite(nil, 1.0),
+		fontRenderer: renderer,
+	}
+
+	return d
+}
+
+// SetLines sets the lines of text describing the unit AI
+func (d *AIDebugOverlay) SetLines(lines []string) {
+	d.lines = lines
+}
+
+// SetPathPoints sets the screen points of the unit AI planned path to draw over the world, starting from the unit
+func (d *AIDebugOverlay) SetPathPoints(points []image.Point) {
+	d.pathPoints = points
+}
+
+func (d *AIDebugOverlay) updateFontSize(_, height int) {
+	// set font size based on element size
+	pxSize := float64(height) / 20
+	if pxSize < 1 {
+		pxSize = 1
+	}
+
+	d.fontRenderer.SetSize(pxSize)
+}
+
+func (d *AIDebugOverlay) Draw(bounds image.Rectangle, hudOpts *DrawHudOptions) {
+	screen := hudOpts.Screen
+
+	bX, bY, bW, bH := bounds.Min.X, bounds.Min.Y, bounds.Dx(), bounds.Dy()
+	d.updateFontSize(bW, bH)
+
+	// planned path in the world
+	pColor := hudOpts.HudColor(_colorAIDebugPath)
+	var pT float32 = 2 // TODO: calculate line thickness based on image height
+	for i := 1; i < len(d.pathPoints); i++ {
+		p1, p2 := d.pathPoints[i-1], d.pathPoints[i]
+		vector.StrokeLine(screen, float32(p1.X), float32(p1.Y), float32(p2.X), float32(p2.Y), pT, pColor, false)
+	}
+	for _, p := range d.pathPoints {
+		vector.FillCircle(screen, float32(p.X), float32(p.Y), 2*pT, pColor, false)
+	}
+
+	if len(d.lines) == 0 {
+		return
+	}
+
+	// text background for readability over the world
+	tColor := hudOpts.HudColor(_colorAIDebugText)
+	bAlpha := uint8(int(tColor.A) / 3)
+	vector.FillRect(screen, float32(bX), float32(bY), float32(bW), float32(bH), color.NRGBA{R: 0, G: 0, B: 0, A: bAlpha}, false)
+
+	d.fontRenderer.SetColor(tColor)
+	d.fontRenderer.SetAlign(etxt.Top | etxt.Left)
+	d.fontRenderer.Draw(screen, strings.Join(d.lines, "\n"), bX+bW/50, bY+bH/50)
+}
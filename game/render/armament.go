@@ -211,7 +211,11 @@ func (a *Armament) drawWeapon(w *Weapon, bounds image.Rectangle, hudOpts *DrawHu
 	wAmmoBin := weapon.AmmoBin()
 	isAmmoEmpty := wAmmoBin != nil && wAmmoBin.AmmoCount() == 0
 
-	if weapon.Cooldown() > 0 || isAmmoEmpty {
+	if weapon.Destroyed() {
+		// show destroyed weapons in critical status color
+		wColor = hudOpts.HudColor(_colorStatusCritical)
+		wColor.A = uint8(2 * (int(wColor.A) / 5))
+	} else if weapon.Cooldown() > 0 || isAmmoEmpty {
 		wColor.A = uint8(2 * (int(wColor.A) / 5))
 	}
 	a.fontRenderer.SetColor(color.NRGBA(wColor))
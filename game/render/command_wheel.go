@@ -0,0 +1,106 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/harbdog/raycaster-go/geom"
+	"github.com/pixelmek-3d/pixelmek-3d/game/render/fonts"
+	"github.com/tinne26/etxt"
+)
+
+var (
+	_colorCommandWheel         = _colorDefaultGreen
+	_colorCommandWheelSelected = _colorDefaultYellow
+)
+
+type CommandWheel struct {
+	HUDSprite
+	fontRenderer *etxt.Renderer
+	commands     []string
+	selected     int
+}
+
+// NewCommandWheel creates a wheel of commands to select from to be rendered on demand
+func NewCommandWheel(font *fonts.Font) *CommandWheel {
+	// create and configure font renderer
+	renderer := etxt.NewRenderer()
+	renderer.SetCacheHandler(font.FontCache.NewHandler())
+	renderer.SetFont(font.Font)
+
+	c := &CommandWheel{
+		HUDSprite:    NewHUDSprite(nil, 1.0),
+		fontRenderer: renderer,
+		selected:     -1,
+	}
+
+	return c
+}
+
+// SetCommands sets the commands shown around the wheel, starting from the top and going clockwise
+func (c *CommandWheel) SetCommands(commands []string) {
+	c.commands = commands
+}
+
+// SetSelected sets the index of the selected command, or -1 if none
+func (c *CommandWheel) SetSelected(selected int) {
+	c.selected = selected
+}
+
+func (c *CommandWheel) updateFontSize(_, height int) {
+	// set font size based on element size
+	pxSize := float64(height) / 16
+	if pxSize < 1 {
+		pxSize = 1
+	}
+
+	c.fontRenderer.SetSize(pxSize)
+}
+
+func (c *CommandWheel) Draw(bounds image.Rectangle, hudOpts *DrawHudOptions) {
+	screen := hudOpts.Screen
+	c.fontRenderer.SetAlign(etxt.VertCenter | etxt.HorzCenter)
+
+	bX, bY, bW, bH := bounds.Min.X, bounds.Min.Y, bounds.Dx(), bounds.Dy()
+	c.updateFontSize(bW, bH)
+
+	midX, midY := float64(bX)+float64(bW)/2, float64(bY)+float64(bH)/2
+	radius := float64(bH) / 2
+
+	// background circle
+	wColor := hudOpts.HudColor(_colorCommandWheel)
+	bAlpha := uint8(int(wColor.A) / 5)
+	vector.FillCircle(screen, float32(midX), float32(midY), float32(radius), color.NRGBA{R: 0, G: 0, B: 0, A: bAlpha}, false)
+
+	var oT float32 = 2 // TODO: calculate line thickness based on image height
+	oAlpha := uint8(int(wColor.A) / 2)
+	vector.StrokeCircle(screen, float32(midX), float32(midY), float32(radius), oT, color.NRGBA{wColor.R, wColor.G, wColor.B, oAlpha}, false)
+
+	if len(c.commands) == 0 {
+		return
+	}
+
+	// commands around the wheel starting from the top going clockwise
+	segment := geom.Pi2 / float64(len(c.commands))
+	sColor := hudOpts.HudColor(_colorCommandWheelSelected)
+	for i, command := range c.commands {
+		angle := float64(i) * segment
+		tX := midX + 0.65*radius*math.Sin(angle)
+		tY := midY - 0.65*radius*math.Cos(angle)
+
+		if i == c.selected {
+			// line from center towards the selected command
+			lX, lY := midX+0.35*radius*math.Sin(angle), midY-0.35*radius*math.Cos(angle)
+			vector.StrokeLine(screen, float32(midX), float32(midY), float32(lX), float32(lY), oT, sColor, false)
+			c.fontRenderer.SetColor(sColor)
+		} else {
+			c.fontRenderer.SetColor(wColor)
+		}
+		c.fontRenderer.Draw(screen, command, int(tX), int(tY))
+	}
+
+	// center of the wheel
+	vector.FillCircle(screen, float32(midX), float32(midY), 2*oT, wColor, false)
+}
@@ -23,6 +23,7 @@ type HeatIndicator struct {
 	heat         float64
 	maxHeat      float64
 	dissipation  float64
+	thresholds   []float64
 }
 
 // NewHeatIndicator creates a heat indicator image to be rendered on demand
@@ -58,6 +59,11 @@ func (h *HeatIndicator) SetValues(heat, maxHeat, dissipation float64) {
 	h.dissipation = dissipation
 }
 
+// SetThresholds sets the heat scale band thresholds to mark, as fractions of max heat
+func (h *HeatIndicator) SetThresholds(thresholds []float64) {
+	h.thresholds = thresholds
+}
+
 func (h *HeatIndicator) Draw(bounds image.Rectangle, hudOpts *DrawHudOptions) {
 	screen := hudOpts.Screen
 
@@ -93,6 +99,14 @@ func (h *HeatIndicator) Draw(bounds image.Rectangle, hudOpts *DrawHudOptions) {
 	oX, oY, oW, oH := float32(bX), float32(bY), float32(bW), float32(bH)/2
 	vector.StrokeRect(screen, oX, oY, oW, oH, oT, oColor, false)
 
+	// heat scale band threshold marks on both sides, since the heat level box grows out from the middle
+	for _, threshold := range h.thresholds {
+		tW := float32(threshold) * oW
+		for _, tX := range []float32{midX - tW/2, midX + tW/2} {
+			vector.StrokeLine(screen, tX, oY, tX, oY+oH, oT/2, oColor, false)
+		}
+	}
+
 	// current heat level text
 	tColor := hudOpts.HudColor(_colorHeatText)
 	h.fontRenderer.SetColor(tColor)
@@ -103,6 +103,34 @@ func NewMissionImage(m *model.Mission, res *model.ModelResources, tex *texture.T
 			return nil, err
 		}
 	}
+	for _, lance := range m.Lances {
+		// lance members already have the lance team and formation positions applied
+		if (lance.Team >= 0 && !missionOpts.RenderEnemyUnits) || (lance.Team < 0 && !missionOpts.RenderFriendlyUnits) {
+			continue
+		}
+		for _, u := range lance.Mechs {
+			if err := renderMissionUnit[model.Mech](missionImage, u); err != nil {
+				return nil, err
+			}
+		}
+		for _, u := range lance.Vehicles {
+			if err := renderMissionUnit[model.Vehicle](missionImage, u); err != nil {
+				return nil, err
+			}
+		}
+		for _, u := range lance.Infantry {
+			if err := renderMissionUnit[model.Infantry](missionImage, u); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if missionOpts.RenderFriendlyUnits {
+		for _, u := range m.Lancemates {
+			if err := renderMissionUnit[model.Mech](missionImage, u); err != nil {
+				return nil, err
+			}
+		}
+	}
 	for _, u := range m.Emplacements {
 		if (u.Team >= 0 && !missionOpts.RenderEnemyUnits) || (u.Team < 0 && !missionOpts.RenderFriendlyUnits) {
 			continue
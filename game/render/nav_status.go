@@ -114,7 +114,9 @@ func (n *NavStatus) Draw(bounds image.Rectangle, hudOpts *DrawHudOptions) {
 		break
 	case n.navPoint.Objective() == model.NavDustoffObjective:
 		navName = "^" + navName + "^"
-	case n.navPoint.Objective() == model.NavVisitObjective:
+	case n.navPoint.Objective() == model.NavVisitObjective,
+		n.navPoint.Objective() == model.NavDefendObjective,
+		n.navPoint.Objective() == model.NavCaptureObjective:
 		navName = "*" + navName + "*"
 	}
 	n.fontRenderer.Draw(screen, navName, bX+bW/2, bY)
@@ -41,6 +41,7 @@ type Radar struct {
 	fovDegrees   float64
 	radarRange   float64
 	showPosition bool
+	jammed       bool
 }
 
 // RadarBlip represents persistent, targettable contacts on the radar
@@ -169,6 +170,11 @@ func (r *Radar) ShowPosition(show bool) {
 	r.showPosition = show
 }
 
+// SetJammed sets whether the radar is inside the ECM field of an enemy unit
+func (r *Radar) SetJammed(jammed bool) {
+	r.jammed = jammed
+}
+
 func (r *Radar) Update() {
 	// Manage animated radar pings
 	numPings := len(r.radarPings)
@@ -229,6 +235,9 @@ func (r *Radar) Draw(bounds image.Rectangle, hudOpts *DrawHudOptions) {
 	if r.showPosition {
 		radarStr += fmt.Sprintf("\nP:%0.0f,%0.0f", r.position.X, r.position.Y)
 	}
+	if r.jammed {
+		radarStr += "\nECM"
+	}
 	r.fontRenderer.Draw(screen, radarStr, bX, bY)
 
 	// Draw radar circle outline
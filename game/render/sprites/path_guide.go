@@ -0,0 +1,32 @@
+package sprites
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/harbdog/raycaster-go"
+	"github.com/harbdog/raycaster-go/geom3d"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+)
+
+type PathGuide struct {
+	*Sprite
+}
+
+func NewPathGuide(pos geom3d.Vector3) *PathGuide {
+	// use smallest possible empty image to be a raycasted guide for drawing a path in the world on the HUD
+	guideImg := ebiten.NewImage(2, 2)
+	guideEntity := model.BasicVisualEntity(pos.X, pos.Y, pos.Z, raycaster.AnchorBottom)
+	p := &PathGuide{
+		Sprite: NewSprite(guideEntity, 0.25, guideImg),
+	}
+
+	// path guide cannot be focused upon by player reticle
+	p.focusable = false
+
+	return p
+}
+
+func (p *PathGuide) SetPosition(pos geom3d.Vector3) {
+	p.Pos().X = pos.X
+	p.Pos().Y = pos.Y
+	p.SetPosZ(pos.Z)
+}
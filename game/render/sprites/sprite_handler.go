@@ -1,8 +1,11 @@
 package sprites
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/harbdog/raycaster-go"
 )
@@ -24,6 +27,10 @@ const (
 type SpriteHandler struct {
 	sprites map[SpriteType]*sync.Map
 
+	// ordered is used to range over sprites in the order they were added, such as for headless simulation
+	ordered  bool
+	sequence atomic.Uint64
+
 	MechSpriteTemplates        map[string]*MechSprite
 	VehicleSpriteTemplates     map[string]*VehicleSprite
 	VTOLSpriteTemplates        map[string]*VTOLSprite
@@ -54,30 +61,78 @@ func NewSpriteHandler() *SpriteHandler {
 	return s
 }
 
+// SetOrdered sets whether ranging over sprites is in the consistent order they were added instead of
+// the faster unordered range, needed for deterministic updates
+func (s *SpriteHandler) SetOrdered(ordered bool) {
+	s.ordered = ordered
+}
+
 func (s *SpriteHandler) SpriteTypes() []SpriteType {
 	typesArr := make([]SpriteType, 0, len(s.sprites))
 	for spriteType := range s.sprites {
 		typesArr = append(typesArr, spriteType)
 	}
+	slices.Sort(typesArr)
 	return typesArr
 }
 
 func (s *SpriteHandler) Range(f func(key, value any) bool) {
-	for _, spriteMap := range s.sprites {
-		spriteMap.Range(f)
+	for _, spriteType := range s.SpriteTypes() {
+		s.RangeByType(spriteType, f)
 	}
 }
 
 func (s *SpriteHandler) RangeByType(spriteType SpriteType, f func(key, value any) bool) {
-	if spriteMap, ok := s.sprites[spriteType]; ok {
+	spriteMap, ok := s.sprites[spriteType]
+	if !ok {
+		return
+	}
+	if !s.ordered {
 		spriteMap.Range(f)
+		return
+	}
+
+	type orderedSprite struct {
+		key      any
+		sequence uint64
 	}
+
+	sorted := make([]orderedSprite, 0, 64)
+	spriteMap.Range(func(k, v any) bool {
+		sorted = append(sorted, orderedSprite{key: k, sequence: v.(uint64)})
+		return true
+	})
+	slices.SortFunc(sorted, func(a, b orderedSprite) int {
+		return cmp.Compare(a.sequence, b.sequence)
+	})
+
+	for _, o := range sorted {
+		v, ok := spriteMap.Load(o.key)
+		if !ok {
+			// sprite was removed while ranging
+			continue
+		}
+		if !f(o.key, v) {
+			return
+		}
+	}
+}
+
+// store adds the sprite to its type map with the sequence it was added in for ordered ranging
+func (s *SpriteHandler) store(spriteType SpriteType, sprite raycaster.Sprite) {
+	s.sprites[spriteType].Store(sprite, s.sequence.Add(1))
 }
 
 func (s *SpriteHandler) Clear() {
 	for spriteType := range s.sprites {
 		s.sprites[spriteType] = &sync.Map{}
 	}
+	s.sequence.Store(0)
+}
+
+// DeleteSprite removes the sprite of any type
+func (s *SpriteHandler) DeleteSprite(sprite raycaster.Sprite) {
+	s.sprites[GetSpriteType(sprite)].Delete(sprite)
 }
 
 func GetSpriteType(sInterface raycaster.Sprite) SpriteType {
@@ -104,7 +159,7 @@ func GetSpriteType(sInterface raycaster.Sprite) SpriteType {
 }
 
 func (s *SpriteHandler) AddMapSprite(sprite *Sprite) {
-	s.sprites[MapSpriteType].Store(sprite, struct{}{})
+	s.store(MapSpriteType, sprite)
 }
 
 func (s *SpriteHandler) DeleteMapSprite(sprite *Sprite) {
@@ -112,7 +167,7 @@ func (s *SpriteHandler) DeleteMapSprite(sprite *Sprite) {
 }
 
 func (s *SpriteHandler) AddMechSprite(mech *MechSprite) {
-	s.sprites[MechSpriteType].Store(mech, struct{}{})
+	s.store(MechSpriteType, mech)
 }
 
 func (s *SpriteHandler) DeleteMechSprite(mech *MechSprite) {
@@ -120,7 +175,7 @@ func (s *SpriteHandler) DeleteMechSprite(mech *MechSprite) {
 }
 
 func (s *SpriteHandler) AddVehicleSprite(vehicle *VehicleSprite) {
-	s.sprites[VehicleSpriteType].Store(vehicle, struct{}{})
+	s.store(VehicleSpriteType, vehicle)
 }
 
 func (s *SpriteHandler) DeleteVehicleSprite(vehicle *VehicleSprite) {
@@ -128,7 +183,7 @@ func (s *SpriteHandler) DeleteVehicleSprite(vehicle *VehicleSprite) {
 }
 
 func (s *SpriteHandler) AddVTOLSprite(vtol *VTOLSprite) {
-	s.sprites[VTOLSpriteType].Store(vtol, struct{}{})
+	s.store(VTOLSpriteType, vtol)
 }
 
 func (s *SpriteHandler) DeleteVTOLSprite(vtol *VTOLSprite) {
@@ -136,7 +191,7 @@ func (s *SpriteHandler) DeleteVTOLSprite(vtol *VTOLSprite) {
 }
 
 func (s *SpriteHandler) AddInfantrySprite(infantry *InfantrySprite) {
-	s.sprites[InfantrySpriteType].Store(infantry, struct{}{})
+	s.store(InfantrySpriteType, infantry)
 }
 
 func (s *SpriteHandler) DeleteInfantrySprite(infantry *InfantrySprite) {
@@ -144,7 +199,7 @@ func (s *SpriteHandler) DeleteInfantrySprite(infantry *InfantrySprite) {
 }
 
 func (s *SpriteHandler) AddEmplacementSprite(emplacement *EmplacementSprite) {
-	s.sprites[EmplacementSpriteType].Store(emplacement, struct{}{})
+	s.store(EmplacementSpriteType, emplacement)
 }
 
 func (s *SpriteHandler) DeleteEmplacementSprite(emplacement *EmplacementSprite) {
@@ -152,7 +207,7 @@ func (s *SpriteHandler) DeleteEmplacementSprite(emplacement *EmplacementSprite)
 }
 
 func (s *SpriteHandler) AddProjectile(projectile *ProjectileSprite) {
-	s.sprites[ProjectileSpriteType].Store(projectile, struct{}{})
+	s.store(ProjectileSpriteType, projectile)
 }
 
 func (s *SpriteHandler) DeleteProjectile(projectile *ProjectileSprite) {
@@ -160,7 +215,7 @@ func (s *SpriteHandler) DeleteProjectile(projectile *ProjectileSprite) {
 }
 
 func (s *SpriteHandler) AddEffect(effect *EffectSprite) {
-	s.sprites[EffectSpriteType].Store(effect, struct{}{})
+	s.store(EffectSpriteType, effect)
 }
 
 func (s *SpriteHandler) DeleteEffect(effect *EffectSprite) {
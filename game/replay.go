@@ -0,0 +1,494 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/harbdog/raycaster-go"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	"github.com/pixelmek-3d/pixelmek-3d/game/render/sprites"
+	input "github.com/quasilyte/ebitengine-input"
+	log "github.com/sirupsen/logrus"
+)
+
+// REPLAY_VERSION is the replay file format version, it needs to be incremented when the format changes
+// or when game updates change such that the same input no longer gives the same result
+const REPLAY_VERSION = 1
+
+// replaySpeeds are the playback speed multipliers available to cycle through
+var replaySpeeds = []float64{0.25, 0.5, 1, 2, 4, 8}
+
+// ReplayFile is the saved recording of a mission as the initial seed and settings plus player input by tick
+type ReplayFile struct {
+	Version       int                   `json:"version"`
+	Seed          int64                 `json:"seed"`
+	Mission       string                `json:"mission"`
+	Mech          string                `json:"mech"`
+	WeaponGroups  [][]model.WeaponGroup `json:"weapon_groups"`
+	Difficulty    string                `json:"difficulty"`
+	ThrottleDecay bool                  `json:"throttle_decay"`
+	Ticks         uint                  `json:"ticks"`
+	Frames        []*ReplayFrame        `json:"frames"`
+}
+
+// ReplayFrame is the player input for a single tick, only saved for ticks with input or changes in state
+type ReplayFrame struct {
+	Tick         uint     `json:"tick"`
+	Pressed      []string `json:"pressed,omitempty"`
+	JustPressed  []string `json:"just_pressed,omitempty"`
+	JustReleased []string `json:"just_released,omitempty"`
+
+	MoveDx   float64 `json:"move_dx,omitempty"`
+	MoveDy   float64 `json:"move_dy,omitempty"`
+	TurretDx float64 `json:"turret_dx,omitempty"`
+	TurretDy float64 `json:"turret_dy,omitempty"`
+
+	// state from rendering and menus that does not come from player input, only set when changed
+	Crosshairs    *uint64               `json:"crosshairs,omitempty"`
+	LockOn        *bool                 `json:"lock_on,omitempty"`
+	WeaponGroups  [][]model.WeaponGroup `json:"weapon_groups,omitempty"`
+	Difficulty    *string               `json:"difficulty,omitempty"`
+	ThrottleDecay *bool                 `json:"throttle_decay,omitempty"`
+	Eject         bool                  `json:"eject,omitempty"`
+}
+
+// Replay handles recording or playback of a mission replay
+type Replay struct {
+	file     *ReplayFile
+	filePath string
+	playback bool
+
+	tick       uint
+	frame      *ReplayFrame
+	frameIndex int
+
+	// last recorded or played back state that does not come from player input
+	crosshairs    uint64
+	lockOn        bool
+	weaponGroups  [][]model.WeaponGroup
+	difficulty    string
+	throttleDecay bool
+	eject         bool
+
+	// playback controls
+	paused     bool
+	speedIndex int
+	tickAccum  float64
+
+	// settings to restore after playback
+	prevDifficulty    *DifficultyLevel
+	prevThrottleDecay bool
+}
+
+// RecordReplay sets the next mission launched to be recorded to a replay file, using the given seed
+// for random generation. The mission and player unit need to already be set.
+func (g *Game) RecordReplay(replayFile, missionFile string, seed int64) error {
+	if g.mission == nil || g.player == nil {
+		return fmt.Errorf("mission and player unit must be set before recording a replay")
+	}
+	if _, ok := g.player.Unit.(*model.Mech); !ok {
+		return fmt.Errorf("replay recording only supports a player mech")
+	}
+
+	g.replay = &Replay{
+		filePath: replayFile,
+		file: &ReplayFile{
+			Version: REPLAY_VERSION,
+			Seed:    seed,
+			Mission: missionFile,
+		},
+	}
+	return nil
+}
+
+// LoadReplay loads the mission and player unit from a replay file so the next mission launched plays it back
+func (g *Game) LoadReplay(replayFile string) error {
+	rf, err := loadReplayFile(replayFile)
+	if err != nil {
+		return err
+	}
+
+	if _, err := g.LoadMission(rf.Mission); err != nil {
+		return fmt.Errorf("[%s] %s", replayFile, err.Error())
+	}
+
+	unit := g.LoadUnit(model.MechResourceType, rf.Mech)
+	if unit == nil {
+		return fmt.Errorf("[%s] mech not found: %s", replayFile, rf.Mech)
+	}
+	if len(rf.WeaponGroups) > len(unit.Armament()) {
+		return fmt.Errorf("[%s] weapon groups do not match mech armament: %s", replayFile, rf.Mech)
+	}
+	g.SetPlayerUnit(unit)
+
+	g.replay = &Replay{
+		file:       rf,
+		filePath:   replayFile,
+		playback:   true,
+		speedIndex: slices.Index(replaySpeeds, 1),
+	}
+	return nil
+}
+
+func loadReplayFile(replayFile string) (*ReplayFile, error) {
+	replayBytes, err := os.ReadFile(replayFile)
+	if err != nil {
+		return nil, err
+	}
+
+	rf := &ReplayFile{}
+	if err := json.Unmarshal(replayBytes, rf); err != nil {
+		return nil, fmt.Errorf("[%s] %s", replayFile, err.Error())
+	}
+	if rf.Version != REPLAY_VERSION {
+		return nil, fmt.Errorf("[%s] unsupported replay version %d, expected version %d", replayFile, rf.Version, REPLAY_VERSION)
+	}
+	if difficultyByName(rf.Difficulty) == nil {
+		return nil, fmt.Errorf("[%s] unknown difficulty: %s", replayFile, rf.Difficulty)
+	}
+
+	var prevTick uint
+	for i, f := range rf.Frames {
+		if (i > 0 && f.Tick <= prevTick) || f.Tick >= rf.Ticks {
+			return nil, fmt.Errorf("[%s] frame %d has out of order tick: %d", replayFile, i, f.Tick)
+		}
+		if f.Difficulty != nil && difficultyByName(*f.Difficulty) == nil {
+			return nil, fmt.Errorf("[%s] frame %d has unknown difficulty: %s", replayFile, i, *f.Difficulty)
+		}
+		prevTick = f.Tick
+	}
+	return rf, nil
+}
+
+func (rf *ReplayFile) save(replayFile string) error {
+	if err := os.MkdirAll(filepath.Dir(replayFile), 0755); err != nil {
+		return err
+	}
+
+	replayBytes, err := json.Marshal(rf)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(replayFile, replayBytes, 0644)
+}
+
+func difficultyByName(name string) *DifficultyLevel {
+	for _, d := range DifficultyLevels {
+		if d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// Playback returns true if the replay is being played back instead of recorded
+func (r *Replay) Playback() bool {
+	return r.playback
+}
+
+func (r *Replay) String() string {
+	tickDuration := func(ticks uint) time.Duration {
+		return time.Duration(float64(ticks) * model.SECONDS_PER_TICK * float64(time.Second)).Round(time.Second)
+	}
+
+	replayText := fmt.Sprintf("Replay %gx | %v / %v", replaySpeeds[r.speedIndex], tickDuration(r.tick), tickDuration(r.file.Ticks))
+	if r.paused {
+		replayText += " | Paused"
+	}
+	return replayText
+}
+
+// start seeds random generation and sets game state to be the same for recording and playback,
+// needs to be called before the mission is initialized
+func (r *Replay) start(g *Game) {
+	model.SetRandomSeed(r.file.Seed)
+	g.initCombatVariables()
+
+	// sprites need to be updated in a consistent order, and mission time based on ticks
+	g.sprites.SetOrdered(true)
+	g.mission.SetTickTimer(true)
+
+	rf := r.file
+	if r.playback {
+		r.prevDifficulty, r.prevThrottleDecay = g.difficulty, g.throttleDecay
+
+		g.difficulty = difficultyByName(rf.Difficulty)
+		g.throttleDecay = rf.ThrottleDecay
+		g.player.setWeaponGroups(weaponGroupsFromIndex(g.player, rf.WeaponGroups))
+	} else {
+		rf.Mech = model.TrimExtension(g.player.Unit.(*model.Mech).Resource.File)
+		rf.Difficulty = g.difficulty.Name
+		rf.ThrottleDecay = g.throttleDecay
+		rf.WeaponGroups = weaponGroupsByIndex(g.player, g.player.weaponGroups)
+		rf.Frames = make([]*ReplayFrame, 0, 1024)
+	}
+
+	r.weaponGroups, r.difficulty, r.throttleDecay = rf.WeaponGroups, rf.Difficulty, rf.ThrottleDecay
+	r.tick, r.frame, r.frameIndex = 0, nil, 0
+	r.crosshairs, r.lockOn, r.eject = 0, false, false
+}
+
+// stopReplay ends replay recording or playback, saving the replay file when recording
+func (g *Game) stopReplay() {
+	r := g.replay
+	if r == nil {
+		return
+	}
+
+	g.replay = nil
+	g.sprites.SetOrdered(false)
+
+	if r.playback {
+		if r.prevDifficulty != nil {
+			g.difficulty, g.throttleDecay = r.prevDifficulty, r.prevThrottleDecay
+		}
+		return
+	}
+
+	r.file.Ticks = r.tick
+	if err := r.file.save(r.filePath); err != nil {
+		log.Error("error saving replay file: ", err)
+		return
+	}
+	log.Info("replay saved: " + r.filePath)
+}
+
+// record captures the player input and any changes in state for the current tick into a new frame
+func (r *Replay) record(g *Game, in playerInput) *ReplayFrame {
+	f := &ReplayFrame{Tick: r.tick}
+	for a := ActionUnknown + 1; a < actionCount; a++ {
+		if in.ActionIsPressed(a) {
+			f.Pressed = append(f.Pressed, actionString(a))
+		}
+		if in.ActionIsJustPressed(a) {
+			f.JustPressed = append(f.JustPressed, actionString(a))
+		}
+		if in.ActionIsJustReleased(a) {
+			f.JustReleased = append(f.JustReleased, actionString(a))
+		}
+	}
+	f.MoveDx, f.MoveDy, f.TurretDx, f.TurretDy = in.Axes()
+
+	if crosshairs := g.spriteSequence(g.spriteInCrosshairs()); crosshairs != r.crosshairs {
+		r.crosshairs = crosshairs
+		f.Crosshairs = &crosshairs
+	}
+
+	weaponGroups := weaponGroupsByIndex(g.player, g.player.weaponGroups)
+	if !slices.EqualFunc(weaponGroups, r.weaponGroups, func(a, b []model.WeaponGroup) bool { return slices.Equal(a, b) }) {
+		r.weaponGroups = weaponGroups
+		f.WeaponGroups = weaponGroups
+	}
+
+	if difficulty := g.difficulty.Name; difficulty != r.difficulty {
+		r.difficulty = difficulty
+		f.Difficulty = &difficulty
+	}
+
+	if throttleDecay := g.throttleDecay; throttleDecay != r.throttleDecay {
+		r.throttleDecay = throttleDecay
+		f.ThrottleDecay = &throttleDecay
+	}
+
+	f.Eject, r.eject = r.eject, false
+
+	r.frame = f
+	return f
+}
+
+// recordLockOn records whether the crosshairs are on target for lock-on during the current tick
+func (r *Replay) recordLockOn(lockOn bool) {
+	if r.playback || r.frame == nil || lockOn == r.lockOn {
+		return
+	}
+	r.lockOn = lockOn
+	r.frame.LockOn = &lockOn
+}
+
+// recordEject records that the player ejected from the menu, applied at the start of the next tick
+func (r *Replay) recordEject() {
+	if !r.playback {
+		r.eject = true
+	}
+}
+
+// endTick finishes the current tick, saving the recorded frame if it is not empty
+func (r *Replay) endTick() {
+	if !r.playback && r.frame != nil && !r.frame.empty() {
+		r.file.Frames = append(r.file.Frames, r.frame)
+	}
+	r.frame = nil
+	r.tick++
+}
+
+// nextFrame returns the player input frame for the current tick and applies its changes in state,
+// or nil if the end of the replay is reached
+func (r *Replay) nextFrame(g *Game) *ReplayFrame {
+	rf := r.file
+	if r.tick >= rf.Ticks {
+		return nil
+	}
+
+	f := &ReplayFrame{Tick: r.tick}
+	if r.frameIndex < len(rf.Frames) && rf.Frames[r.frameIndex].Tick == r.tick {
+		f = rf.Frames[r.frameIndex]
+		r.frameIndex++
+	}
+
+	if f.Crosshairs != nil {
+		r.crosshairs = *f.Crosshairs
+	}
+	if f.LockOn != nil {
+		r.lockOn = *f.LockOn
+	}
+	if f.WeaponGroups != nil {
+		g.player.weaponGroups = weaponGroupsFromIndex(g.player, f.WeaponGroups)
+	}
+	if f.Difficulty != nil {
+		g.difficulty = difficultyByName(*f.Difficulty)
+	}
+	if f.ThrottleDecay != nil {
+		g.throttleDecay = *f.ThrottleDecay
+	}
+	if f.Eject && g.player.ejectionPod == nil {
+		destroyEntity(g.player)
+	}
+
+	r.frame = f
+	return f
+}
+
+// playbackTicks returns the number of ticks to update during the current game update based on playback speed
+func (r *Replay) playbackTicks() int {
+	if r.paused {
+		return 0
+	}
+
+	r.tickAccum += replaySpeeds[r.speedIndex]
+	ticks := int(r.tickAccum)
+	r.tickAccum -= float64(ticks)
+	return ticks
+}
+
+// crosshairsSprite returns the sprite that was in the crosshairs when recorded
+func (r *Replay) crosshairsSprite(g *Game) *sprites.Sprite {
+	if r.crosshairs == 0 {
+		return nil
+	}
+	return g.spriteFromSequence(r.crosshairs)
+}
+
+// handlePlaybackInput handles the replay playback controls
+func (g *Game) handlePlaybackInput() {
+	r := g.replay
+
+	if g.input.ActionIsJustPressed(ActionPowerToggle) {
+		r.paused = !r.paused
+	}
+
+	if g.input.ActionIsJustPressed(ActionUp) && r.speedIndex < len(replaySpeeds)-1 {
+		r.speedIndex++
+	} else if g.input.ActionIsJustPressed(ActionDown) && r.speedIndex > 0 {
+		r.speedIndex--
+	}
+
+	if g.input.ActionIsJustPressed(ActionCameraCycle) {
+		g.cycleReplayCamera()
+	}
+}
+
+// cycleReplayCamera moves the camera to the next unit in play, then back to the player unit after the last one
+func (g *Game) cycleReplayCamera() {
+	debugCamTgt := g.player.DebugCameraTarget()
+
+	var nextTgt model.Unit
+	found := debugCamTgt == nil
+	for _, u := range g.getSpriteUnits() {
+		if u.IsDestroyed() {
+			continue
+		}
+		if found {
+			nextTgt = u
+			break
+		}
+		if u == debugCamTgt {
+			found = true
+		}
+	}
+
+	g.player.SetDebugCameraTarget(nextTgt)
+	g.updatePlayerCamera(true)
+}
+
+// spriteSequence returns the sequence number the sprite was added to the sprite handler with,
+// which is consistent between recording and playback, or zero if not found
+func (g *Game) spriteSequence(s *sprites.Sprite) uint64 {
+	if s == nil {
+		return 0
+	}
+
+	var sequence uint64
+	for _, spriteType := range g.sprites.SpriteTypes() {
+		g.sprites.RangeByType(spriteType, func(k, v any) bool {
+			if getSpriteFromInterface(k.(raycaster.Sprite)) == s {
+				sequence = v.(uint64)
+				return false
+			}
+			return true
+		})
+		if sequence > 0 {
+			return sequence
+		}
+	}
+
+	// sprites not handled directly, such as the target lead reticle, use the sprite of their entity
+	if eSprite := g.getSpriteFromEntity(s.Entity); eSprite != nil && eSprite != s {
+		return g.spriteSequence(eSprite)
+	}
+	return 0
+}
+
+// spriteFromSequence returns the sprite added to the sprite handler with the sequence number
+func (g *Game) spriteFromSequence(sequence uint64) *sprites.Sprite {
+	var found *sprites.Sprite
+	for _, spriteType := range g.sprites.SpriteTypes() {
+		g.sprites.RangeByType(spriteType, func(k, v any) bool {
+			if v.(uint64) == sequence {
+				found = getSpriteFromInterface(k.(raycaster.Sprite))
+				return false
+			}
+			return true
+		})
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func (f *ReplayFrame) ActionIsPressed(a input.Action) bool {
+	return slices.Contains(f.Pressed, actionString(a))
+}
+
+func (f *ReplayFrame) ActionIsJustPressed(a input.Action) bool {
+	return slices.Contains(f.JustPressed, actionString(a))
+}
+
+func (f *ReplayFrame) ActionIsJustReleased(a input.Action) bool {
+	return slices.Contains(f.JustReleased, actionString(a))
+}
+
+func (f *ReplayFrame) Axes() (moveDx, moveDy, turretDx, turretDy float64) {
+	return f.MoveDx, f.MoveDy, f.TurretDx, f.TurretDy
+}
+
+func (f *ReplayFrame) empty() bool {
+	return len(f.Pressed) == 0 && len(f.JustPressed) == 0 && len(f.JustReleased) == 0 &&
+		f.MoveDx == 0 && f.MoveDy == 0 && f.TurretDx == 0 && f.TurretDy == 0 &&
+		f.Crosshairs == nil && f.LockOn == nil && f.WeaponGroups == nil &&
+		f.Difficulty == nil && f.ThrottleDecay == nil && !f.Eject
+}
@@ -92,6 +92,50 @@ func initFS() {
 			}
 		}
 	}
+
+	// walk files placed directly in the mods directory, such as units saved from the mech lab
+	// * loaded after mods/*.tar so that loose files "win" over archived files
+	dirFS := os.DirFS(modsPath)
+	fs.WalkDir(dirFS, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			log.Errorf("error walking mods directory %s", err)
+			return nil
+		}
+		if path.Dir(p) == "." && !d.IsDir() {
+			// only resource folders expected at the top level (tar files, readme)
+			return nil
+		}
+		log.Debugf("[%s] %s", modsPath, p)
+		_storeFsResource(p, d, dirFS)
+		return nil
+	})
+}
+
+// WriteModFile writes a resource file into the mods directory and makes it available to be read immediately
+func WriteModFile(fPath string, data []byte) (string, error) {
+	modFilePath := filepath.Join(modsPath, filepath.FromSlash(fPath))
+	if err := os.MkdirAll(filepath.Dir(modFilePath), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(modFilePath, data, 0644); err != nil {
+		return "", err
+	}
+
+	// store the entries for the file and any of its parent folders not already known
+	dirFS := os.DirFS(modsPath)
+	for p := fPath; p != "."; p = path.Dir(p) {
+		info, err := fs.Stat(dirFS, p)
+		if err != nil {
+			return "", err
+		}
+		if _, ok := fsPathMap[path.Dir(p)][path.Base(p)]; ok && info.IsDir() {
+			continue
+		}
+		_storeFsResource(p, fs.FileInfoToDirEntry(info), dirFS)
+	}
+	hasLocalResources = true
+
+	return modFilePath, nil
 }
 
 func _storeFsResource(p string, d fs.DirEntry, _fs fs.FS) {
@@ -147,6 +191,12 @@ func _fsForPath(fPath string) (fs.FS, error) {
 	return fsr.fs, nil
 }
 
+// IsModFile returns true if the resource file at the path is provided by mods instead of the game
+func IsModFile(fPath string) bool {
+	tfs, err := _fsForPath(fPath)
+	return err == nil && tfs != embedded
+}
+
 func FileAt(path string) (fs.File, error) {
 	tfs, err := _fsForPath(path)
 	if err != nil {
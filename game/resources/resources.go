@@ -38,13 +38,14 @@ var (
 	UserConfigFile       string
 	UserKeymapFile       string
 	UserWeaponGroupsFile string
+	UserSavePath         string
 
 	CrosshairsSheet *CrosshairsSheetConfig
 
 	imageByPath = make(map[string]*ebiten.Image)
 	rgbaByPath  = make(map[string]*image.RGBA)
 
-	//go:embed ai audio fonts icons maps menu missions shaders sprites textures all:units all:weapons
+	//go:embed ai audio campaigns effects fonts icons maps menu missions scripts shaders sprites textures all:units all:weapons
 	embedded embed.FS
 )
 
@@ -69,6 +70,7 @@ func InitConfig() {
 	UserConfigFile = userConfigPath + "/config.json"
 	UserKeymapFile = userConfigPath + "/keymap.json"
 	UserWeaponGroupsFile = userConfigPath + "/weapon_groups.json"
+	UserSavePath = userConfigPath + "/saves"
 
 	Viper.AddConfigPath(userConfigPath)
 
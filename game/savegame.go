@@ -0,0 +1,585 @@
+package game
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/harbdog/raycaster-go"
+	"github.com/harbdog/raycaster-go/geom"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	"github.com/pixelmek-3d/pixelmek-3d/game/render/sprites"
+	"github.com/pixelmek-3d/pixelmek-3d/game/resources"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SAVEGAME_VERSION is the saved game file format version, it needs to be incremented when the format changes
+const SAVEGAME_VERSION = 1
+
+// SaveGame is the saved state of a mission in progress. Units and map sprites are referenced by the
+// sequence their sprite was added in when the mission was loaded, which is the same each time it is loaded.
+type SaveGame struct {
+	Version        int                   `json:"version"`
+	Mission        string                `json:"mission"`
+	Lancemates     []string              `json:"lancemates,omitempty"`
+	Campaign       string                `json:"campaign,omitempty"`
+	MissionSeconds float64               `json:"mission_seconds"`
+	NavVisited     []bool                `json:"nav_visited"`
+	Objectives     []*SaveGameObjective  `json:"objectives"`
+	Triggers       []int                 `json:"triggers,omitempty"`
+	Player         *SaveGamePlayer       `json:"player"`
+	Units          []*SaveGameUnit       `json:"units"`
+	MapSprites     []uint64              `json:"map_sprites"`
+	Projectiles    []*SaveGameProjectile `json:"projectiles,omitempty"`
+
+	// enemy mechs destroyed so far that can be salvaged after a campaign mission
+	Salvage []*CampaignRosterMech `json:"salvage,omitempty"`
+}
+
+type SaveGameObjective struct {
+	Completed bool    `json:"completed"`
+	Failed    bool    `json:"failed"`
+	Progress  float64 `json:"progress,omitempty"`
+}
+
+type SaveGamePlayer struct {
+	Mech           string                  `json:"mech"`
+	State          *model.UnitMissionState `json:"state"`
+	PowerOnTimer   int                     `json:"power_on_timer"`
+	PowerOffTimer  int                     `json:"power_off_timer"`
+	CameraAngle    float64                 `json:"camera_angle"`
+	CameraPitch    float64                 `json:"camera_pitch"`
+	WeaponGroups   [][]model.WeaponGroup   `json:"weapon_groups"`
+	SelectedWeapon uint                    `json:"selected_weapon"`
+	SelectedGroup  model.WeaponGroup       `json:"selected_group"`
+	FireMode       model.WeaponFireMode    `json:"fire_mode"`
+
+	// index of the selected nav point, -1 if none
+	Nav int `json:"nav"`
+	// sprite sequence of the target unit, 0 if none
+	Target uint64 `json:"target,omitempty"`
+}
+
+type SaveGameUnit struct {
+	Sequence      uint64                  `json:"sequence"`
+	State         *model.UnitMissionState `json:"state"`
+	PowerOnTimer  int                     `json:"power_on_timer,omitempty"`
+	PowerOffTimer int                     `json:"power_off_timer,omitempty"`
+	Target        uint64                  `json:"target,omitempty"`
+	TargetPlayer  bool                    `json:"target_player,omitempty"`
+	AI            *SaveGameAI             `json:"ai,omitempty"`
+
+	// trigger index and unit index within the trigger for reinforcements, which have no sequence from the mission load
+	Reinforcement *[2]int `json:"reinforcement,omitempty"`
+}
+
+type SaveGameAI struct {
+	DestPos         *[2]float64  `json:"dest_pos,omitempty"`
+	DestPath        [][2]float64 `json:"dest_path,omitempty"`
+	TicksSinceFired uint         `json:"ticks_since_fired"`
+	TicksSinceEval  uint         `json:"ticks_since_eval"`
+
+	// order given by the player to a lancemate
+	Order *SaveGameAIOrder `json:"order,omitempty"`
+}
+
+type SaveGameAIOrder struct {
+	Command AICommand `json:"command"`
+	// sprite sequence of the unit ordered to attack, 0 if none
+	Target   uint64      `json:"target,omitempty"`
+	Position *[2]float64 `json:"position,omitempty"`
+	HoldFire bool        `json:"hold_fire,omitempty"`
+}
+
+type SaveGameProjectile struct {
+	// sprite sequence of the unit that fired it, 0 for the player
+	Parent uint64                 `json:"parent,omitempty"`
+	Weapon int                    `json:"weapon"`
+	State  *model.ProjectileState `json:"state"`
+}
+
+// QuickSavePath returns the path of the saved game file used by the in-game menu
+func QuickSavePath() string {
+	return filepath.Join(resources.UserSavePath, "quicksave.json")
+}
+
+// HasQuickSave returns true if there is a saved game from the in-game menu
+func HasQuickSave() bool {
+	_, err := os.Stat(QuickSavePath())
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+// quickSave saves the mission in progress from the in-game menu
+func (g *Game) quickSave() error {
+	saveFile := QuickSavePath()
+	if err := g.SaveGame(saveFile); err != nil {
+		return err
+	}
+	log.Info("game saved: " + saveFile)
+	return nil
+}
+
+// loadQuickSave restarts the game scene from the saved game from the in-game menu
+func (g *Game) loadQuickSave() {
+	saveFile := QuickSavePath()
+	if err := g.LoadSaveGame(saveFile); err != nil {
+		log.Error("Error loading saved game: ", saveFile)
+		log.Error(err)
+		return
+	}
+	g.scene = NewGameScene(g)
+}
+
+// CanSaveGame returns nil if the mission in progress can be saved, otherwise the reason it cannot
+func (g *Game) CanSaveGame() error {
+	switch {
+	case g.mission == nil || g.player == nil || g.objectives == nil:
+		return fmt.Errorf("no mission in progress")
+	case g.mission.File == "":
+		return fmt.Errorf("only missions loaded from a mission file can be saved")
+	case g.replay != nil:
+		return fmt.Errorf("cannot save during a replay")
+	case g.client != nil:
+		return fmt.Errorf("cannot save a multiplayer mission")
+	case g.scripts != nil:
+		return fmt.Errorf("cannot save a mission with a script")
+	case !g.InProgress():
+		return fmt.Errorf("mission is no longer in progress")
+	case g.player.ejectionPod != nil || g.player.IsDestroyed():
+		return fmt.Errorf("cannot save after the player unit is destroyed")
+	}
+	if _, ok := g.player.Unit.(*model.Mech); !ok {
+		return fmt.Errorf("saving only supports a player mech")
+	}
+	return nil
+}
+
+// SaveGame writes the state of the mission in progress to the saved game file
+func (g *Game) SaveGame(saveFile string) error {
+	if err := g.CanSaveGame(); err != nil {
+		return err
+	}
+
+	sg := &SaveGame{
+		Version:        SAVEGAME_VERSION,
+		Mission:        g.mission.File,
+		Lancemates:     g.mission.AddedLancemates(),
+		MissionSeconds: g.mission.TimerSeconds(),
+		Units:          make([]*SaveGameUnit, 0, 64),
+		MapSprites:     make([]uint64, 0, 256),
+	}
+
+	// sequences of units still in play to reference as targets and projectile parents
+	unitSequences := make(map[model.Unit]uint64, 64)
+	for _, spriteType := range g.sprites.SpriteTypes() {
+		if spriteType != sprites.MapSpriteType && !isInteractiveType(spriteType) {
+			continue
+		}
+		g.sprites.RangeByType(spriteType, func(k, v any) bool {
+			s := getSpriteFromInterface(k.(raycaster.Sprite))
+			if s.IsDestroyed() {
+				return true
+			}
+			sequence := v.(uint64)
+			if spriteType == sprites.MapSpriteType {
+				sg.MapSprites = append(sg.MapSprites, sequence)
+			} else if u := s.Unit(); u != nil {
+				unitSequences[u] = sequence
+			}
+			return true
+		})
+	}
+	slices.Sort(sg.MapSprites)
+
+	for u, sequence := range unitSequences {
+		su := &SaveGameUnit{
+			Sequence: sequence,
+			State:    u.UnitMissionState(),
+		}
+		if m, ok := u.(*model.Mech); ok {
+			su.PowerOnTimer, su.PowerOffTimer = m.PowerOnTimer, m.PowerOffTimer
+		}
+		if key, ok := g.triggers.reinforcements[u]; ok {
+			su.Reinforcement = &key
+		}
+
+		if t := model.EntityUnit(u.Target()); t != nil {
+			if t == g.player.Unit {
+				su.TargetPlayer = true
+			} else {
+				su.Target = unitSequences[t]
+			}
+		}
+
+		if a := g.ai.UnitAI(u); a != nil {
+			pathing := a.piloting.pathing
+			su.AI = &SaveGameAI{
+				TicksSinceFired: a.gunnery.ticksSinceFired,
+				TicksSinceEval:  a.piloting.ticksSinceEval,
+			}
+			if pathing.destPos != nil {
+				su.AI.DestPos = &[2]float64{pathing.destPos.X, pathing.destPos.Y}
+			}
+			for _, p := range pathing.destPath {
+				su.AI.DestPath = append(su.AI.DestPath, [2]float64{p.X, p.Y})
+			}
+			if order := a.order; order != nil {
+				su.AI.Order = &SaveGameAIOrder{
+					Command:  order.command,
+					HoldFire: order.holdFire,
+				}
+				if order.target != nil {
+					su.AI.Order.Target = unitSequences[order.target]
+				}
+				if order.position != nil {
+					su.AI.Order.Position = &[2]float64{order.position.X, order.position.Y}
+				}
+			}
+		}
+		sg.Units = append(sg.Units, su)
+	}
+	slices.SortFunc(sg.Units, func(a, b *SaveGameUnit) int {
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+
+	// player unit
+	p := g.player
+	mech := p.Unit.(*model.Mech)
+	sg.Player = &SaveGamePlayer{
+		Mech:           model.TrimExtension(mech.Resource.File),
+		State:          mech.UnitMissionState(),
+		PowerOnTimer:   mech.PowerOnTimer,
+		PowerOffTimer:  mech.PowerOffTimer,
+		CameraAngle:    p.cameraAngle,
+		CameraPitch:    p.cameraPitch,
+		WeaponGroups:   weaponGroupsByIndex(p, p.weaponGroups),
+		SelectedWeapon: p.selectedWeapon,
+		SelectedGroup:  p.selectedGroup,
+		FireMode:       p.fireMode,
+		Nav:            -1,
+	}
+	if p.currentNav != nil {
+		sg.Player.Nav = slices.Index(g.mission.NavPoints, p.currentNav.NavPoint)
+	}
+	if t := model.EntityUnit(p.Target()); t != nil {
+		sg.Player.Target = unitSequences[t]
+	}
+
+	// mission progress
+	for _, nav := range g.mission.NavPoints {
+		sg.NavVisited = append(sg.NavVisited, nav.Visited())
+	}
+	for _, objective := range g.objectives.order {
+		sg.Objectives = append(sg.Objectives, &SaveGameObjective{
+			Completed: objective.Completed(),
+			Failed:    objective.Failed(),
+			Progress:  basicObjective(objective).progress,
+		})
+	}
+	sg.Triggers = append(sg.Triggers, g.triggers.fired...)
+
+	// projectiles in flight, except for those whose unit is no longer in play such as ejection pods
+	g.sprites.RangeByType(sprites.ProjectileSpriteType, func(k, _ any) bool {
+		projectile := k.(*sprites.ProjectileSprite).Projectile
+		if projectile == nil || projectile.Lifespan() <= 0 {
+			return true
+		}
+
+		parent := model.EntityUnit(projectile.Parent())
+		var parentSequence uint64
+		if parent != g.player.Unit {
+			var inPlay bool
+			if parentSequence, inPlay = unitSequences[parent]; !inPlay {
+				return true
+			}
+		}
+
+		weaponIndex := slices.Index(parent.Armament(), projectile.Weapon())
+		if weaponIndex < 0 {
+			return true
+		}
+
+		sg.Projectiles = append(sg.Projectiles, &SaveGameProjectile{
+			Parent: parentSequence,
+			Weapon: weaponIndex,
+			State:  projectile.ProjectileState(),
+		})
+		return true
+	})
+
+	if c := g.campaign; c != nil {
+		sg.Campaign = c.save.Campaign
+		for _, unit := range c.salvage {
+			mech := unit.(*model.Mech)
+			sg.Salvage = append(sg.Salvage, &CampaignRosterMech{
+				Mech:  model.TrimExtension(mech.Resource.File),
+				State: mech.UnitState(),
+			})
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(saveFile), 0755); err != nil {
+		return err
+	}
+	saveBytes, err := json.Marshal(sg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(saveFile, saveBytes, 0644)
+}
+
+// LoadSaveGame loads the mission and player unit from a saved game file so the next mission launched
+// is restored to the saved state
+func (g *Game) LoadSaveGame(saveFile string) error {
+	saveBytes, err := os.ReadFile(saveFile)
+	if err != nil {
+		return err
+	}
+
+	sg := &SaveGame{}
+	if err := json.Unmarshal(saveBytes, sg); err != nil {
+		return fmt.Errorf("[%s] %s", saveFile, err.Error())
+	}
+	if sg.Version != SAVEGAME_VERSION {
+		return fmt.Errorf("[%s] unsupported saved game version %d, expected version %d", saveFile, sg.Version, SAVEGAME_VERSION)
+	}
+	if sg.Player == nil || sg.Player.State == nil {
+		return fmt.Errorf("[%s] player unit is missing", saveFile)
+	}
+
+	// everything is loaded and checked before changing the game so a failed load leaves it as it was
+	mission, err := model.LoadMission(sg.Mission)
+	if err != nil {
+		return fmt.Errorf("[%s] %s", saveFile, err.Error())
+	}
+	// lancemates added when the mission was launched are added again so their units have the same sequence
+	mission.AddLancemates(sg.Lancemates...)
+	if len(sg.NavVisited) != len(mission.NavPoints) {
+		return fmt.Errorf("[%s] saved game has %d nav points, expected %d", saveFile, len(sg.NavVisited), len(mission.NavPoints))
+	}
+
+	unit := g.LoadUnit(model.MechResourceType, sg.Player.Mech)
+	if unit == nil {
+		return fmt.Errorf("[%s] mech not found: %s", saveFile, sg.Player.Mech)
+	}
+
+	prevCampaign := g.campaign
+	g.campaign = nil
+	if sg.Campaign != "" {
+		err := g.LoadCampaign(sg.Campaign)
+		if err == nil {
+			err = g.campaign.loadSalvage(g, sg)
+		}
+		if err != nil {
+			g.campaign = prevCampaign
+			return fmt.Errorf("[%s] %s", saveFile, err.Error())
+		}
+	}
+
+	g.mission = mission
+	g.SetPlayerUnit(unit)
+
+	g.saveGame = sg
+	return nil
+}
+
+// loadSalvage restores the mechs that can be salvaged from the saved campaign mission in progress
+func (c *Campaign) loadSalvage(g *Game, sg *SaveGame) error {
+	// the campaign progress needs to still be at the mission that was saved
+	cMission := c.Mission()
+	if cMission == nil || cMission.Mission != sg.Mission {
+		return fmt.Errorf("campaign is no longer at mission: %s", sg.Mission)
+	}
+
+	c.salvage = c.salvage[:0]
+	for _, r := range sg.Salvage {
+		unit := g.LoadUnit(model.MechResourceType, r.Mech)
+		if unit == nil {
+			return fmt.Errorf("salvage mech not found: %s", r.Mech)
+		}
+		if err := unit.SetUnitState(r.State); err != nil {
+			return fmt.Errorf("%s: %s", r.Mech, err.Error())
+		}
+		c.salvage = append(c.salvage, unit)
+	}
+	return nil
+}
+
+// restoreSaveGame applies the pending saved game state to the newly initialized mission
+func (g *Game) restoreSaveGame() error {
+	sg := g.saveGame
+	g.saveGame = nil
+
+	// reinforcements and other lasting changes from triggers that fired before saving
+	if err := g.triggers.restore(g, sg.Triggers); err != nil {
+		return err
+	}
+
+	// map units and sprites in play by their sequence, removing those that were destroyed before saving
+	savedUnits := make(map[uint64]*SaveGameUnit, len(sg.Units))
+	savedReinforcements := make(map[[2]int]*SaveGameUnit)
+	for _, su := range sg.Units {
+		if su.Reinforcement != nil {
+			savedReinforcements[*su.Reinforcement] = su
+			continue
+		}
+		savedUnits[su.Sequence] = su
+	}
+	savedMapSprites := make(map[uint64]bool, len(sg.MapSprites))
+	for _, sequence := range sg.MapSprites {
+		savedMapSprites[sequence] = true
+	}
+
+	units := make(map[uint64]model.Unit, len(sg.Units))
+	removed := make([]raycaster.Sprite, 0, 64)
+	for _, spriteType := range g.sprites.SpriteTypes() {
+		if spriteType != sprites.MapSpriteType && !isInteractiveType(spriteType) {
+			continue
+		}
+		g.sprites.RangeByType(spriteType, func(k, v any) bool {
+			sequence := v.(uint64)
+			if spriteType == sprites.MapSpriteType {
+				if !savedMapSprites[sequence] {
+					removed = append(removed, k.(raycaster.Sprite))
+				}
+				return true
+			}
+
+			u := getSpriteFromInterface(k.(raycaster.Sprite)).Unit()
+			su, saved := savedUnits[sequence]
+			if key, ok := g.triggers.reinforcements[u]; ok {
+				su, saved = savedReinforcements[key]
+			}
+			if !saved {
+				// destroyed units are kept by objectives that check for their destruction
+				u.SetStructurePoints(0)
+				removed = append(removed, k.(raycaster.Sprite))
+				return true
+			}
+			units[su.Sequence] = u
+			return true
+		})
+	}
+	for _, s := range removed {
+		g.sprites.DeleteSprite(s)
+	}
+	if len(units) != len(sg.Units) {
+		return fmt.Errorf("saved game has %d units, only %d found in mission", len(sg.Units), len(units))
+	}
+
+	// AI is recreated to only include the units still in play
+	g.ai = NewAIHandler(g)
+	g.triggers.restoreAI(g)
+
+	for _, su := range sg.Units {
+		u := units[su.Sequence]
+		if err := u.SetUnitMissionState(su.State); err != nil {
+			return fmt.Errorf("%s: %s", u.ID(), err.Error())
+		}
+		if m, ok := u.(*model.Mech); ok {
+			m.PowerOnTimer, m.PowerOffTimer = su.PowerOnTimer, su.PowerOffTimer
+		}
+
+		switch {
+		case su.TargetPlayer:
+			u.SetTarget(g.player.Unit)
+		case su.Target > 0:
+			if t, ok := units[su.Target]; ok {
+				u.SetTarget(t)
+			}
+		}
+
+		a := g.ai.UnitAI(u)
+		if a == nil || su.AI == nil {
+			continue
+		}
+		a.gunnery.ticksSinceFired = su.AI.TicksSinceFired
+		a.piloting.ticksSinceEval = su.AI.TicksSinceEval
+
+		var destPos *geom.Vector2
+		if su.AI.DestPos != nil {
+			destPos = &geom.Vector2{X: su.AI.DestPos[0], Y: su.AI.DestPos[1]}
+		}
+		destPath := make([]*geom.Vector2, 0, len(su.AI.DestPath))
+		for _, p := range su.AI.DestPath {
+			destPath = append(destPath, &geom.Vector2{X: p[0], Y: p[1]})
+		}
+		a.piloting.pathing.SetDestination(destPos, destPath)
+
+		if so := su.AI.Order; so != nil && a.order != nil {
+			a.order.command = so.Command
+			a.order.holdFire = so.HoldFire
+			if t, ok := units[so.Target]; ok {
+				a.order.target = t
+			}
+			if so.Position != nil {
+				a.order.position = &geom.Vector2{X: so.Position[0], Y: so.Position[1]}
+			}
+		}
+	}
+
+	// player unit
+	p, sp := g.player, sg.Player
+	if err := p.SetUnitMissionState(sp.State); err != nil {
+		return fmt.Errorf("%s: %s", sp.Mech, err.Error())
+	}
+	if m, ok := p.Unit.(*model.Mech); ok {
+		m.PowerOnTimer, m.PowerOffTimer = sp.PowerOnTimer, sp.PowerOffTimer
+	}
+	p.cameraAngle, p.cameraPitch = sp.CameraAngle, sp.CameraPitch
+	if len(sp.WeaponGroups) <= len(p.Armament()) {
+		p.setWeaponGroups(weaponGroupsFromIndex(p, sp.WeaponGroups))
+	}
+	if int(sp.SelectedWeapon) < len(p.Armament()) {
+		p.selectedWeapon, p.selectedGroup = sp.SelectedWeapon, sp.SelectedGroup
+	}
+	p.fireMode = sp.FireMode
+	if sp.Nav >= 0 && sp.Nav < len(g.mission.NavPoints) {
+		p.currentNav = sprites.NewNavSprite(g.mission.NavPoints[sp.Nav], 1.0)
+	}
+	if t, ok := units[sp.Target]; ok {
+		p.SetTarget(t)
+	}
+	g.updatePlayerCamera(true)
+	if g.clutter != nil {
+		g.clutter.Update(g, true)
+	}
+
+	// mission progress
+	g.mission.SetTimerSeconds(sg.MissionSeconds)
+	for i, nav := range g.mission.NavPoints {
+		nav.SetVisited(sg.NavVisited[i])
+	}
+
+	if err := g.objectives.setObjectiveStates(sg.Objectives); err != nil {
+		return err
+	}
+
+	// projectiles in flight
+	for _, sp := range sg.Projectiles {
+		parent := p.Unit
+		if sp.Parent > 0 {
+			var ok bool
+			if parent, ok = units[sp.Parent]; !ok {
+				continue
+			}
+		}
+		if sp.Weapon < 0 || sp.Weapon >= len(parent.Armament()) || sp.State == nil {
+			continue
+		}
+
+		w := parent.Armament()[sp.Weapon]
+		projectile := w.SpawnProjectile(sp.State.Heading, sp.State.Pitch, parent)
+		projectile.SetProjectileState(sp.State)
+		g.addProjectileSprite(w, projectile)
+	}
+
+	log.Debugf("restored saved game for mission: %s", sg.Mission)
+	return nil
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type CampaignScene struct {
+	Game           *Game
+	campaignSelect *CampaignMenu
+	mechBay        *MechBayMenu
+	launchBriefing *LaunchMenu
+
+	menuOrder []Menu
+	menuIndex int
+}
+
+func NewCampaignScene(g *Game) Scene {
+	campaignSelect := createCampaignMenu(g)
+	mechBay := createMechBayMenu(g)
+	launchBriefing := createLaunchMenu(g)
+
+	scene := &CampaignScene{
+		Game:           g,
+		campaignSelect: campaignSelect,
+		mechBay:        mechBay,
+		launchBriefing: launchBriefing,
+		menuOrder: []Menu{
+			campaignSelect,
+			mechBay,
+			launchBriefing,
+		},
+	}
+
+	if g.campaign != nil {
+		// returning from a campaign mission goes straight to the mech bay
+		scene.menuIndex = 1
+		mechBay.loadRoster()
+	}
+	scene.SetMenu(scene.getMenu())
+	return scene
+}
+
+func (s *CampaignScene) SetMenu(m Menu) {
+	s.Game.menu = m
+}
+
+func (s *CampaignScene) getMenu() Menu {
+	if s.menuIndex >= 0 && s.menuIndex < len(s.menuOrder) {
+		return s.menuOrder[s.menuIndex]
+	}
+	return nil
+}
+
+func (s *CampaignScene) Update() error {
+	g := s.Game
+
+	if g.input.ActionIsJustPressed(ActionBack) {
+		s.back()
+	}
+
+	// update the menu
+	g.menu.Update()
+
+	return nil
+}
+
+func (s *CampaignScene) Draw(screen *ebiten.Image) {
+	g := s.Game
+
+	// draw menu
+	g.menu.Draw(screen)
+}
+
+func (s *CampaignScene) back() {
+	g := s.Game
+
+	s.menuIndex -= 1
+
+	prevMenu := s.getMenu()
+	if s.menuIndex < 0 {
+		// back to main menu
+		g.scene = NewMainMenuScene(g)
+	} else {
+		// back to previous menu
+		s.SetMenu(prevMenu)
+	}
+}
+
+func (s *CampaignScene) next() {
+	g := s.Game
+
+	// check actions for current menu
+	switch s.getMenu() {
+	case s.campaignSelect:
+		if g.campaign == nil {
+			return
+		}
+		s.mechBay.loadRoster()
+
+	case s.mechBay:
+		if g.campaign.Complete() {
+			return
+		}
+		if err := g.launchCampaignMission(); err != nil {
+			s.mechBay.setStatus(err.Error())
+			return
+		}
+		s.launchBriefing.loadBriefing(g.mission)
+
+	case s.launchBriefing:
+		// launch game scene into mission
+		g.scene = NewGameScene(g)
+		return
+	}
+
+	s.menuIndex += 1
+	if s.menuIndex < len(s.menuOrder) {
+		// proceed to next menu
+		s.SetMenu(s.getMenu())
+	}
+}
@@ -3,6 +3,8 @@ package game
 import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/pixelmek-3d/pixelmek-3d/game/render/transitions"
+
+	log "github.com/sirupsen/logrus"
 )
 
 type GameScene struct {
@@ -13,9 +15,30 @@ type GameScene struct {
 }
 
 func NewGameScene(g *Game) Scene {
+	if g.replay != nil {
+		// seed and set up for replay before anything in the mission is initialized
+		g.replay.start(g)
+	}
+
+	if g.client != nil {
+		// seed the same as the multiplayer server before anything in the mission is initialized
+		g.client.start()
+	}
+
 	// load mission resources and launch
 	g.initMission()
 
+	if g.client != nil {
+		g.client.initMission(g)
+	}
+
+	if g.saveGame != nil {
+		// restore the saved game over the newly initialized mission
+		if err := g.restoreSaveGame(); err != nil {
+			log.Error("error restoring saved game: ", err)
+		}
+	}
+
 	// prepare for battle
 	gameMenu := createGameMenu(g)
 	g.menu = gameMenu
@@ -50,19 +73,37 @@ func NewGameScene(g *Game) Scene {
 		scene.benchmark = NewBenchmarkHandler()
 	}
 
+	if g.traceAI {
+		g.aiTrace = NewAITraceHandler()
+	}
+
 	g.mission.TimerStart()
 	return scene
 }
 
 func (g *Game) LeaveGame() {
+	g.stopReplay()
+	g.stopClient()
+
 	if gs, ok := g.scene.(*GameScene); ok && gs.benchmark != nil {
 		// close benchmark
 		gs.benchmark.Close()
 		gs.benchmark = nil
 	}
 
+	if g.aiTrace != nil {
+		// close AI trace
+		g.aiTrace.Close()
+		g.aiTrace = nil
+	}
+
 	g.Pause()
 
+	if g.campaign != nil {
+		// carry mission results over to the campaign
+		g.completeCampaignMission()
+	}
+
 	// go to mission debrief
 	g.scene = NewMissionDebriefScene(g)
 }
@@ -94,50 +135,20 @@ func (s *GameScene) Update() error {
 	g.handleInput()
 
 	if !g.paused {
-		// Perform logical updates
-		g.updateAI()
-		g.updatePlayer()
-		g.updateProjectiles()
-		g.UpdateSprites()
-		g.updateObjectives()
-
-		if g.clutter != nil {
-			g.clutter.Update(g, false)
-		}
-
-		// handle player weapon updates
-		g.updateWeaponCooldowns(g.player.Unit)
-
-		// handle player camera movement
-		g.updatePlayerCamera(false)
-
-		// handle player HUD tick-based udpates
-		g.updateHUD()
-
-		if g.InProgress() {
-			if s.transition != nil {
-				// update transition at start of game
-				s.transition.Update()
-				if s.transition.Completed() {
-					s.transition = nil
-				}
-			}
-		} else {
-			if s.transition == nil {
-				// transition out to leave game
-				tOpts := &transitions.TransitionOptions{
-					InDuration:   0.0,
-					HoldDuration: 4.0,
-					OutDuration:  3.0,
-				}
-				s.transition = transitions.NewFade(g.overlayScreen, tOpts, ebiten.GeoM{})
-			} else {
-				// update transition about to leave game
-				s.transition.Update()
-				if s.transition.Completed() {
+		if g.replay != nil && g.replay.Playback() {
+			// replay playback updates as many ticks as needed for the playback speed
+			for ticks := g.replay.playbackTicks(); ticks > 0 && g.scene == s; ticks-- {
+				frame := g.replay.nextFrame(g)
+				if frame == nil {
+					// end of replay
 					g.LeaveGame()
+					break
 				}
+				g.handlePlayerInput(frame)
+				s.updateTick()
 			}
+		} else {
+			s.updateTick()
 		}
 	}
 
@@ -151,6 +162,73 @@ func (s *GameScene) Update() error {
 	return nil
 }
 
+// updateTick performs the logical updates for a single game tick
+func (s *GameScene) updateTick() {
+	g := s.Game
+
+	if g.client != nil {
+		// apply the latest state from the multiplayer server
+		g.client.update(g)
+	}
+
+	// Perform logical updates
+	g.updateSpatialIndex()
+	g.updateSensors()
+	g.updateAI()
+	g.updatePlayer()
+	g.updateProjectiles()
+	g.UpdateSprites()
+	g.updateObjectives()
+
+	if g.clutter != nil {
+		g.clutter.Update(g, false)
+	}
+
+	// handle player weapon updates
+	g.updateWeaponCooldowns(g.player.Unit)
+	g.updateHeatScale(g.player.Unit)
+
+	// handle player camera movement
+	g.updatePlayerCamera(false)
+
+	// handle player HUD tick-based udpates
+	g.updateHUD()
+
+	if g.InProgress() {
+		if s.transition != nil {
+			// update transition at start of game
+			s.transition.Update()
+			if s.transition.Completed() {
+				s.transition = nil
+			}
+		}
+	} else {
+		if s.transition == nil {
+			// transition out to leave game
+			tOpts := &transitions.TransitionOptions{
+				InDuration:   0.0,
+				HoldDuration: 4.0,
+				OutDuration:  3.0,
+			}
+			s.transition = transitions.NewFade(g.overlayScreen, tOpts, ebiten.GeoM{})
+		} else {
+			// update transition about to leave game
+			s.transition.Update()
+			if s.transition.Completed() {
+				g.LeaveGame()
+			}
+		}
+	}
+
+	g.mission.TimerTick()
+	if g.replay != nil {
+		g.replay.endTick()
+	}
+	if g.client != nil {
+		g.client.endTick(g)
+	}
+}
+
 func (s *GameScene) Draw(screen *ebiten.Image) {
 	g := s.Game
 
@@ -11,6 +11,7 @@ type InstantActionScene struct {
 	Game             *Game
 	mapSelect        *MapMenu
 	playerUnitSelect *UnitMenu
+	allyUnitSelect   *UnitMenu
 	enemyUnitSelect  *UnitMenu
 	launchBriefing   *LaunchMenu
 
@@ -21,6 +22,7 @@ type InstantActionScene struct {
 func NewInstantActionScene(g *Game) Scene {
 	mapSelect := createMapMenu(g)
 	unitSelect := createUnitMenu(g, PlayerUnitMenu)
+	allySelect := createUnitMenu(g, FriendlyUnitMenu)
 	enemySelect := createUnitMenu(g, EnemyUnitMenu)
 	launchBriefing := createLaunchMenu(g)
 
@@ -28,11 +30,13 @@ func NewInstantActionScene(g *Game) Scene {
 		Game:             g,
 		mapSelect:        mapSelect,
 		playerUnitSelect: unitSelect,
+		allyUnitSelect:   allySelect,
 		enemyUnitSelect:  enemySelect,
 		launchBriefing:   launchBriefing,
 		menuOrder: []Menu{
 			mapSelect,
 			unitSelect,
+			allySelect,
 			enemySelect,
 			launchBriefing,
 		},
@@ -120,6 +124,15 @@ func (s *InstantActionScene) next() {
 			opts.enemies = append(opts.enemies, s.enemyUnitSelect.selectedUnit)
 		}
 
+		// ally unit joins the player as a lancemate, picked at random if not selected
+		ally := s.allyUnitSelect.selectedUnit
+		if ally == nil {
+			ally = g.RandomUnit(model.MechResourceType)
+		}
+		if mech, ok := ally.(*model.Mech); ok {
+			opts.lancemates = append(opts.lancemates, model.TrimExtension(mech.Resource.File))
+		}
+
 		mission, err := g.LoadInstantActionFromMap(s.mapSelect.selectedMap, opts)
 		if err != nil {
 			log.Error("Error loading mission from map: ", s.mapSelect.selectedMap.Name)
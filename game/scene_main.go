@@ -15,6 +15,9 @@ type MainMenuScene struct {
 }
 
 func NewMainMenuScene(g *Game) Scene {
+	// leaving to main menu ends any active campaign
+	g.campaign = nil
+
 	if !g.audio.IsMusicPlaying() {
 		g.audio.StartMenuMusic()
 	}
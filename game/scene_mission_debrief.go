@@ -47,6 +47,12 @@ func (s *MissionDebriefScene) Draw(screen *ebiten.Image) {
 func (s *MissionDebriefScene) back() {
 	g := s.Game
 
+	if g.campaign != nil {
+		// back to campaign mech bay
+		g.scene = NewCampaignScene(g)
+		return
+	}
+
 	// back to main menu
 	g.scene = NewMainMenuScene(g)
 }
@@ -0,0 +1,396 @@
+package game
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/harbdog/raycaster-go/geom"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"gopkg.in/yaml.v3"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// maximum computation steps of each call into a script, so a script cannot stall the game
+	SCRIPT_MAX_STEPS uint64 = 1_000_000
+)
+
+// ScriptHandler runs the sandboxed Starlark script of a mission, calling its callbacks for mission events:
+// onTick(seconds), onUnitDestroyed(unit), onNavVisited(name) and onObjectiveChanged(objective)
+type ScriptHandler struct {
+	g       *Game
+	file    string
+	globals starlark.StringDict
+
+	// stopped after a script error instead of repeating the same error every tick
+	stopped bool
+
+	// mission state as of the last update, to detect events since
+	alive      map[model.Unit]bool
+	visited    map[*model.NavPoint]bool
+	objectives map[Objective]ObjectivesStatus
+}
+
+func NewScriptHandler(g *Game, scriptFile string) (*ScriptHandler, error) {
+	src, err := model.LoadMissionScript(scriptFile)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ScriptHandler{
+		g:          g,
+		file:       scriptFile,
+		alive:      make(map[model.Unit]bool),
+		visited:    make(map[*model.NavPoint]bool),
+		objectives: make(map[Objective]ObjectivesStatus),
+	}
+
+	predeclared := s.builtins()
+	_, program, err := starlark.SourceProgramOptions(
+		model.ScriptFileOptions, path.Join("scripts", scriptFile), src, predeclared.Has,
+	)
+	if err != nil {
+		return nil, scriptError(scriptFile, err)
+	}
+
+	// top level statements of the script are run when the mission starts, globals are
+	// not frozen afterwards so that callbacks can keep script state in global lists and dicts
+	s.globals, err = program.Init(s.thread(), predeclared)
+	if err != nil {
+		return nil, scriptError(scriptFile, err)
+	}
+
+	// initial mission state is not an event
+	s.updateEvents(false)
+	return s, nil
+}
+
+// Update calls the script callbacks for mission events since the last update, then onTick
+func (s *ScriptHandler) Update() {
+	if s.stopped {
+		return
+	}
+	s.updateEvents(true)
+	s.call("onTick", starlark.Float(s.g.mission.TimerSeconds()))
+}
+
+func (s *ScriptHandler) updateEvents(callback bool) {
+	g := s.g
+
+	alive := make(map[model.Unit]bool, len(s.alive))
+	for _, u := range g.getSpriteUnits() {
+		if !u.IsDestroyed() {
+			alive[u] = true
+			continue
+		}
+		if callback && s.alive[u] {
+			s.call("onUnitDestroyed", s.scriptUnit(u))
+		}
+	}
+	s.alive = alive
+
+	for _, nav := range g.mission.NavPoints {
+		if callback && nav.Visited() && !s.visited[nav] {
+			s.call("onNavVisited", starlark.String(nav.Name))
+		}
+		s.visited[nav] = nav.Visited()
+	}
+
+	for _, objective := range g.objectives.order {
+		status := objectiveStatus(objective)
+		if prevStatus, ok := s.objectives[objective]; callback && ok && status != prevStatus {
+			s.call("onObjectiveChanged", s.scriptObjective(objective))
+		}
+		s.objectives[objective] = status
+	}
+}
+
+// call calls the script function by name, if the script defines it
+func (s *ScriptHandler) call(name string, args ...starlark.Value) {
+	fn, ok := s.globals[name]
+	if !ok || s.stopped {
+		return
+	}
+
+	_, err := starlark.Call(s.thread(), fn, args, nil)
+	if err != nil {
+		log.Error(scriptError(s.file, err))
+		log.Errorf("[%s] script stopped", s.file)
+		s.stopped = true
+	}
+}
+
+// thread returns a new thread for each call into the script, so that the computation steps are limited per call
+func (s *ScriptHandler) thread() *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: s.file,
+		Print: func(_ *starlark.Thread, msg string) {
+			log.Infof("[%s] %s", s.file, msg)
+		},
+		// load statements are not supported, scripts cannot access other files
+		Load: nil,
+	}
+	thread.SetMaxExecutionSteps(SCRIPT_MAX_STEPS)
+	return thread
+}
+
+func scriptError(scriptFile string, err error) error {
+	if evalErr, ok := err.(*starlark.EvalError); ok {
+		return fmt.Errorf("[%s] %s", scriptFile, evalErr.Backtrace())
+	}
+	return fmt.Errorf("[%s] %s", scriptFile, err.Error())
+}
+
+// builtins returns the curated API available to scripts
+func (s *ScriptHandler) builtins() starlark.StringDict {
+	return starlark.StringDict{
+		"getSpriteUnits":    starlark.NewBuiltin("getSpriteUnits", s.getSpriteUnits),
+		"getPlayerUnit":     starlark.NewBuiltin("getPlayerUnit", s.getPlayerUnit),
+		"spawnMissionUnit":  starlark.NewBuiltin("spawnMissionUnit", s.spawnMissionUnit),
+		"addObjectives":     starlark.NewBuiltin("addObjectives", s.addObjectives),
+		"completeObjective": starlark.NewBuiltin("completeObjective", s.completeObjective),
+		"failObjective":     starlark.NewBuiltin("failObjective", s.failObjective),
+		"showMessage":       starlark.NewBuiltin("showMessage", s.showMessage),
+		"missionSeconds":    starlark.NewBuiltin("missionSeconds", s.missionSeconds),
+	}
+}
+
+// getSpriteUnits() returns a list of all units in play, not including the player
+func (s *ScriptHandler) getSpriteUnits(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+
+	units := s.g.getSpriteUnits()
+	values := make([]starlark.Value, 0, len(units))
+	for _, u := range units {
+		values = append(values, s.scriptUnit(u))
+	}
+	return starlark.NewList(values), nil
+}
+
+// getPlayerUnit() returns the player unit
+func (s *ScriptHandler) getPlayerUnit(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+	return s.scriptUnit(s.g.player.Unit), nil
+}
+
+// spawnMissionUnit(type, unit, x, y, z=0, heading=0, id="", team=0) adds a unit to the mission,
+// where type is one of mechs, vehicles, infantry or vtols. Returns the unit, or None if it could not be spawned
+func (s *ScriptHandler) spawnMissionUnit(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var unitType, unit, id string
+	var x, y, z, heading scriptNumber
+	var team int
+	err := starlark.UnpackArgs(b.Name(), args, kwargs,
+		"type", &unitType, "unit", &unit, "x", &x, "y", &y,
+		"z?", &z, "heading?", &heading, "id?", &id, "team?", &team,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	missionUnit := model.MissionUnit{
+		ID:       id,
+		Team:     team,
+		Unit:     unit,
+		Position: [2]float64{float64(x), float64(y)},
+		Heading:  float64(heading),
+	}
+
+	var u model.Unit
+	switch unitType {
+	case model.MechResourceType:
+		u, err = createMissionUnitModel[model.Mech](s.g, missionUnit)
+	case model.VehicleResourceType:
+		u, err = createMissionUnitModel[model.Vehicle](s.g, missionUnit)
+	case model.InfantryResourceType:
+		u, err = createMissionUnitModel[model.Infantry](s.g, missionUnit)
+	case model.VTOLResourceType:
+		u, err = createMissionFlyingUnitModel[model.VTOL](s.g, model.MissionFlyingUnit{
+			ID:        id,
+			Team:      team,
+			Unit:      unit,
+			Position:  missionUnit.Position,
+			ZPosition: float64(z),
+			Heading:   missionUnit.Heading,
+		})
+	default:
+		return nil, fmt.Errorf("%s: unknown unit type '%s'", b.Name(), unitType)
+	}
+	if err == nil {
+		err = s.g.addUnitSprite(u)
+	}
+	if err != nil {
+		log.Errorf("[%s] error spawning mission unit: %v", s.file, err)
+		return starlark.None, nil
+	}
+
+	if s.g.ai != nil {
+		s.g.ai.AddReinforcementAI([]model.Unit{u})
+	}
+	return s.scriptUnit(u), nil
+}
+
+// addObjectives(objectives) adds objectives from a dict in the same format as mission file objectives
+func (s *ScriptHandler) addObjectives(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var value *starlark.Dict
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "objectives", &value); err != nil {
+		return nil, err
+	}
+
+	// convert to the mission objectives model using the same yaml keys as mission files
+	objectivesValue, err := goValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err.Error())
+	}
+	objectivesYaml, err := yaml.Marshal(objectivesValue)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err.Error())
+	}
+
+	objectives := &model.MissionObjectives{}
+	if err := yaml.Unmarshal(objectivesYaml, objectives); err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err.Error())
+	}
+	if err := validator.New().Struct(objectives); err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err.Error())
+	}
+
+	s.g.objectives.AddObjectives(s.g, objectives)
+	return starlark.None, nil
+}
+
+// completeObjective(id) marks the objective with the ID as completed
+func (s *ScriptHandler) completeObjective(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var id string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "id", &id); err != nil {
+		return nil, err
+	}
+	s.g.objectives.CompleteObjective(id)
+	return starlark.None, nil
+}
+
+// failObjective(id) marks the objective with the ID as failed
+func (s *ScriptHandler) failObjective(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var id string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "id", &id); err != nil {
+		return nil, err
+	}
+	s.g.objectives.FailObjective(id)
+	return starlark.None, nil
+}
+
+// showMessage(message, seconds=0) shows a message in the HUD banner for a number of seconds, or a default duration
+func (s *ScriptHandler) showMessage(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var message string
+	var seconds scriptNumber
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "message", &message, "seconds?", &seconds); err != nil {
+		return nil, err
+	}
+	s.g.triggers.showMessage(s.g, message, float64(seconds))
+	return starlark.None, nil
+}
+
+// missionSeconds() returns the number of seconds since the mission started
+func (s *ScriptHandler) missionSeconds(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+	return starlark.Float(s.g.mission.TimerSeconds()), nil
+}
+
+// scriptUnit returns the read only view of a unit given to scripts
+func (s *ScriptHandler) scriptUnit(u model.Unit) starlark.Value {
+	pos := u.Pos()
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"id":           starlark.String(u.ID()),
+		"name":         starlark.String(u.Name()),
+		"variant":      starlark.String(u.Variant()),
+		"team":         starlark.MakeInt(u.Team()),
+		"x":            starlark.Float(pos.X),
+		"y":            starlark.Float(pos.Y),
+		"z":            starlark.Float(u.PosZ()),
+		"heading":      starlark.Float(geom.Degrees(u.Heading())),
+		"armor":        starlark.Float(u.ArmorPoints()),
+		"structure":    starlark.Float(u.StructurePoints()),
+		"maxArmor":     starlark.Float(u.MaxArmorPoints()),
+		"maxStructure": starlark.Float(u.MaxStructurePoints()),
+		"destroyed":    starlark.Bool(u.IsDestroyed()),
+		"player":       starlark.Bool(u == s.g.player.Unit),
+		"friendly":     starlark.Bool(s.g.IsFriendly(s.g.player, u)),
+	})
+}
+
+// scriptObjective returns the read only view of an objective given to scripts
+func (s *ScriptHandler) scriptObjective(objective Objective) starlark.Value {
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"id":     starlark.String(s.g.objectives.objectiveID(objective)),
+		"text":   starlark.String(objective.Text()),
+		"status": starlark.String(objectiveStatus(objective).String()),
+		"bonus":  starlark.Bool(objective.Bonus()),
+	})
+}
+
+// scriptNumber unpacks a script argument of either int or float as a float
+type scriptNumber float64
+
+func (n *scriptNumber) Unpack(v starlark.Value) error {
+	f, ok := starlark.AsFloat(v)
+	if !ok {
+		return fmt.Errorf("got %s, want float or int", v.Type())
+	}
+	*n = scriptNumber(f)
+	return nil
+}
+
+// goValue converts a script value to its equivalent Go value
+func goValue(v starlark.Value) (any, error) {
+	switch v := v.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.Int:
+		i, ok := v.Int64()
+		if !ok {
+			return nil, fmt.Errorf("int value out of range: %s", v.String())
+		}
+		return i, nil
+	case starlark.Float:
+		return float64(v), nil
+	case starlark.String:
+		return string(v), nil
+	case starlark.Indexable:
+		// list or tuple
+		values := make([]any, v.Len())
+		for i := range values {
+			value, err := goValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	case *starlark.Dict:
+		values := make(map[string]any, v.Len())
+		for _, item := range v.Items() {
+			key, ok := item[0].(starlark.String)
+			if !ok {
+				return nil, fmt.Errorf("dict key must be a string: %s", item[0].String())
+			}
+			value, err := goValue(item[1])
+			if err != nil {
+				return nil, err
+			}
+			values[string(key)] = value
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unsupported value type: %s", v.Type())
+}
@@ -0,0 +1,141 @@
+package game
+
+import (
+	"github.com/harbdog/raycaster-go/geom"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+)
+
+const (
+	// number of ticks between sensor updates, since line of sight checks are expensive
+	SENSOR_UPDATE_TICKS uint = 6
+)
+
+// sensorContact is a target unit detected by a source unit
+type sensorContact struct {
+	source model.Unit
+	target model.Unit
+}
+
+type SensorsHandler struct {
+	tick uint
+
+	// seconds each target has been continuously detected by each source
+	contacts map[sensorContact]float64
+
+	// units with working ECM as of the last update
+	ecmUnits []model.Unit
+}
+
+func NewSensorsHandler() *SensorsHandler {
+	return &SensorsHandler{
+		contacts: make(map[sensorContact]float64),
+	}
+}
+
+// Update tracks how long each unit has detected each enemy unit using its sensors
+func (s *SensorsHandler) Update(g *Game) {
+	s.tick++
+	if s.tick%SENSOR_UPDATE_TICKS != 0 {
+		return
+	}
+	elapsed := float64(SENSOR_UPDATE_TICKS) * model.SECONDS_PER_TICK
+
+	units := g.getSpriteUnits()
+	if g.player != nil && g.player.sprite != nil {
+		units = append(units, g.player.Unit)
+	}
+
+	s.ecmUnits = s.ecmUnits[:0]
+	for _, u := range units {
+		if !u.IsDestroyed() && u.HasElectronics(model.ECM) {
+			s.ecmUnits = append(s.ecmUnits, u)
+		}
+	}
+
+	contacts := make(map[sensorContact]float64, len(s.contacts))
+	for _, source := range units {
+		if source.IsDestroyed() || source.Powered() != model.POWER_ON {
+			continue
+		}
+		for _, target := range units {
+			if target == source || target.IsDestroyed() || g.IsFriendly(source, target) {
+				continue
+			}
+			if !s.canDetect(g, source, target) {
+				continue
+			}
+			contact := sensorContact{source: source, target: target}
+			contacts[contact] = s.contacts[contact] + elapsed
+		}
+	}
+	s.contacts = contacts
+}
+
+// canDetect returns true if the target is in sensor range of the source, and either close by
+// or in line of sight and not hidden by ECM
+func (s *SensorsHandler) canDetect(g *Game, source, target model.Unit) bool {
+	distance := model.EntityDistance(source, target)
+	if !g.inSensorRange(source, target, distance) {
+		return false
+	}
+	if distance <= model.SENSOR_PROXIMITY_METERS/model.METERS_PER_UNIT {
+		return true
+	}
+	return g.lineOfSight(source, target) && !s.IsECMProtected(g, source, target)
+}
+
+// IsDetected returns true if the source has detected the target for long enough to show on radar and be targeted
+func (s *SensorsHandler) IsDetected(source, target model.Unit) bool {
+	return s.contacts[sensorContact{source: source, target: target}] >= model.SENSOR_DETECT_SECONDS
+}
+
+// IsDetecting returns true if the source has started detecting the target, but not yet long enough to identify it
+func (s *SensorsHandler) IsDetecting(source, target model.Unit) bool {
+	seconds := s.contacts[sensorContact{source: source, target: target}]
+	return seconds > 0 && seconds < model.SENSOR_DETECT_SECONDS
+}
+
+// IsECMProtected returns true if the target is inside the ECM bubble of one of its friendly units,
+// unless the source has an active probe close enough to counter it
+func (s *SensorsHandler) IsECMProtected(g *Game, source, target model.Unit) bool {
+	tPos := target.Pos()
+	protected := false
+	for _, ecm := range s.ecmUnits {
+		if ecm.IsDestroyed() || !g.IsFriendly(ecm, target) {
+			continue
+		}
+		ePos := ecm.Pos()
+		if geom.Distance(ePos.X, ePos.Y, tPos.X, tPos.Y) <= model.ECM_RANGE_METERS/model.METERS_PER_UNIT {
+			protected = true
+			break
+		}
+	}
+	if !protected {
+		return false
+	}
+
+	// active probe counters ECM of targets within its range
+	return !(source.HasElectronics(model.BAP) && model.EntityDistance(source, target) <= model.BAP_RANGE_METERS/model.METERS_PER_UNIT)
+}
+
+// IsJammed returns true if the unit is inside the ECM bubble of an enemy unit
+func (s *SensorsHandler) IsJammed(g *Game, u model.Unit) bool {
+	uPos := u.Pos()
+	for _, ecm := range s.ecmUnits {
+		if ecm.IsDestroyed() || g.IsFriendly(ecm, u) {
+			continue
+		}
+		ePos := ecm.Pos()
+		if geom.Distance(ePos.X, ePos.Y, uPos.X, uPos.Y) <= model.ECM_RANGE_METERS/model.METERS_PER_UNIT {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) updateSensors() {
+	if g.sensors == nil {
+		return
+	}
+	g.sensors.Update(g)
+}
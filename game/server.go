@@ -0,0 +1,498 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/harbdog/raycaster-go"
+	"github.com/harbdog/raycaster-go/geom"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	"github.com/pixelmek-3d/pixelmek-3d/game/render/sprites"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Server hosts a multiplayer mission, running the authoritative game simulation for all of its clients
+type Server struct {
+	listener net.Listener
+	seed     int64
+	players  int
+	started  bool
+	tick     uint
+
+	// clients that completed the handshake, waiting to be spawned into the mission
+	joins   chan *serverClient
+	clients []*serverClient
+
+	// closed when the server stops so pending handshakes do not wait to join
+	done chan struct{}
+}
+
+// serverClient is a client connected to the server, piloting its own unit
+type serverClient struct {
+	conn     *netConn
+	hello    *NetHello
+	unit     model.Unit
+	sprite   *sprites.MechSprite
+	sequence uint64
+
+	// messages waiting to be written to the client connection, so the server never waits on a slow client
+	writes chan []byte
+
+	// input is received concurrently from the client connection
+	mu     sync.Mutex
+	input  *NetInput
+	fire   []int
+	closed atomic.Bool
+}
+
+// Serve hosts the loaded mission for clients to join on the given address, starting once the number of players
+// have joined, and running until the objectives are completed or failed or until all clients have left
+func (g *Game) Serve(address string, players int, seed int64) (*SimulationResult, error) {
+	if !g.headless {
+		panic("serve requires a game from NewHeadlessGame!")
+	}
+	if g.mission == nil || g.player == nil {
+		return nil, fmt.Errorf("mission and player unit must be set before serving")
+	}
+	if g.mission.File == "" {
+		return nil, fmt.Errorf("only missions loaded from a mission file can be served")
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+
+	s := &Server{
+		listener: listener,
+		seed:     seed,
+		players:  max(players, 1),
+		joins:    make(chan *serverClient, 16),
+		clients:  make([]*serverClient, 0, 8),
+		done:     make(chan struct{}),
+	}
+	defer close(s.done)
+	g.server = s
+
+	// seed right before the mission is initialized, the same as clients do, so mission content is created the same
+	model.SetRandomSeed(seed)
+	g.initMission()
+
+	// mission time is based on ticks simulated instead of real time
+	g.mission.SetTickTimer(true)
+
+	// the player unit is only an observer at the drop zone, it is not targeted or collided with
+	g.aiIgnorePlayer = true
+
+	go s.accept()
+	log.Infof("server listening on %s for %d player(s)", listener.Addr(), s.players)
+
+	ticker := time.NewTicker(time.Second / time.Duration(model.TICKS_PER_SECOND))
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.handleJoins(g)
+		s.applyInputs(g)
+
+		if !s.started && len(s.clients) >= s.players {
+			log.Info("all players joined, starting mission")
+			s.started = true
+		}
+
+		if s.started {
+			g.updateSpatialIndex()
+			g.updateSensors()
+			g.updateAI()
+			g.updateProjectiles()
+			g.UpdateSprites()
+			s.updateNavPoints(g)
+			g.updateObjectives()
+			g.mission.TimerTick()
+		}
+
+		s.tick++
+		if s.started && (!g.InProgress() || len(s.clients) == 0) {
+			break
+		}
+		if s.tick%NET_SNAPSHOT_TICKS == 0 {
+			s.broadcast(g)
+		}
+	}
+
+	// final snapshot so clients have the mission results, each connection is closed once it has been written
+	s.broadcast(g)
+	for _, c := range s.clients {
+		close(c.writes)
+	}
+
+	return &SimulationResult{
+		Status:     g.objectives.Status(),
+		Ticks:      s.tick,
+		Seconds:    g.mission.TimerSeconds(),
+		Objectives: g.objectives.Text(),
+	}, nil
+}
+
+// accept handles new connections until the listener is closed
+func (s *Server) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handshake(newNetConn(conn))
+	}
+}
+
+// handshake waits for the client hello before passing it on to be spawned into the mission
+func (s *Server) handshake(conn *netConn) {
+	conn.conn.SetReadDeadline(time.Now().Add(NET_TIMEOUT))
+	msg, err := conn.read()
+	if err != nil {
+		log.Error("client handshake failed: ", err)
+		conn.close()
+		return
+	}
+
+	hello := msg.Hello
+	switch {
+	case hello == nil:
+		err = fmt.Errorf("expected hello message")
+	case hello.Version != MULTIPLAYER_VERSION:
+		err = fmt.Errorf("unsupported multiplayer version %d, expected version %d", hello.Version, MULTIPLAYER_VERSION)
+	}
+	if err != nil {
+		log.Error("client handshake failed: ", err)
+		conn.write(&NetMessage{Error: err.Error()})
+		conn.close()
+		return
+	}
+
+	conn.conn.SetReadDeadline(time.Time{})
+	select {
+	case s.joins <- &serverClient{conn: conn, hello: hello}:
+	case <-s.done:
+		conn.close()
+	}
+}
+
+// handleJoins spawns units for clients that have completed the handshake
+func (s *Server) handleJoins(g *Game) {
+	for {
+		select {
+		case c := <-s.joins:
+			if err := s.join(g, c); err != nil {
+				log.Error("client unable to join: ", err)
+				go func() {
+					c.conn.write(&NetMessage{Error: err.Error()})
+					c.conn.close()
+				}()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (s *Server) join(g *Game, c *serverClient) error {
+	unit := g.LoadUnit(model.MechResourceType, c.hello.Mech)
+	if unit == nil {
+		return fmt.Errorf("mech not found: %s", c.hello.Mech)
+	}
+
+	id := c.hello.Name
+	if id == "" {
+		id = fmt.Sprintf("Player %d", len(s.clients)+1)
+	}
+	unit.SetID(id)
+	unit.SetTeam(c.hello.Team)
+
+	// clients spawn in a line beside the drop zone, facing the same heading
+	dz := g.mission.DropZone
+	dzPos := &geom.Vector2{X: dz.Position[0], Y: dz.Position[1]}
+	heading := model.CardinalToAngle(dz.Heading)
+	unit.SetPos(dzPos)
+
+	spacing := 4 * unit.CollisionRadius() * float64(len(s.clients)+1)
+	spawnLine := geom.LineFromAngle(dzPos.X, dzPos.Y, heading-geom.HalfPi, spacing)
+	spawnPos, _, _, _ := g.getValidMove(unit, spawnLine.X2, spawnLine.Y2, 0, false)
+	unit.SetPos(spawnPos)
+	unit.SetHeading(heading)
+	unit.SetTargetHeading(heading)
+	unit.SetTurretAngle(heading)
+
+	unit.SetInitialPoweredStatus(dz.PowerStatus)
+	if unit.Powered() != model.POWER_ON {
+		unit.SetPowered(model.POWER_ON)
+	}
+
+	sprite := g.CreateUnitSprite(unit).(*sprites.MechSprite)
+	g.sprites.AddMechSprite(sprite)
+
+	c.unit, c.sprite = unit, sprite
+	c.sequence = g.spriteSequence(sprite.Sprite)
+
+	welcome := &NetWelcome{
+		Version:  MULTIPLAYER_VERSION,
+		Mission:  g.mission.File,
+		Seed:     s.seed,
+		Sequence: c.sequence,
+	}
+	msgBytes, err := json.Marshal(&NetMessage{Welcome: welcome})
+	if err != nil {
+		g.sprites.DeleteMechSprite(sprite)
+		return err
+	}
+
+	c.writes = make(chan []byte, NET_WRITE_BUFFER)
+
+	s.clients = append(s.clients, c)
+	go c.writeMessages(msgBytes)
+	go c.readInputs()
+
+	log.Infof("[%s] joined with %s %s on team %d", id, unit.Name(), unit.Variant(), unit.Team())
+	return nil
+}
+
+// writeMessages writes the welcome message and then queued messages until the queue is closed or a write fails,
+// then closes the connection
+func (c *serverClient) writeMessages(welcome []byte) {
+	defer c.conn.close()
+	if err := c.conn.writeBytes(welcome); err != nil {
+		c.closed.Store(true)
+		return
+	}
+	for msgBytes := range c.writes {
+		if err := c.conn.writeBytes(msgBytes); err != nil {
+			// removed with the next input update
+			c.closed.Store(true)
+			return
+		}
+	}
+}
+
+// queue adds a message to be written to the client without waiting, replacing the oldest waiting
+// message if the client is falling behind since each snapshot has the full mission state
+func (c *serverClient) queue(msgBytes []byte) {
+	select {
+	case c.writes <- msgBytes:
+	default:
+		select {
+		case <-c.writes:
+		default:
+		}
+		// the server is the only sender so there is room now
+		c.writes <- msgBytes
+	}
+}
+
+// readInputs receives client input until the connection is closed
+func (c *serverClient) readInputs() {
+	for {
+		msg, err := c.conn.read()
+		if err != nil {
+			c.closed.Store(true)
+			return
+		}
+		if msg.Input == nil {
+			continue
+		}
+
+		c.mu.Lock()
+		c.input = msg.Input
+		c.fire = append(c.fire, msg.Input.Fire...)
+		c.mu.Unlock()
+	}
+}
+
+// applyInputs applies the latest input from each client to its unit, removing clients that have disconnected
+func (s *Server) applyInputs(g *Game) {
+	connected := s.clients[:0]
+	for _, c := range s.clients {
+		if c.closed.Load() {
+			log.Infof("[%s] left the mission", c.unit.ID())
+			if !c.unit.IsDestroyed() {
+				g.sprites.DeleteMechSprite(c.sprite)
+			}
+			close(c.writes)
+			c.conn.close()
+			continue
+		}
+		connected = append(connected, c)
+
+		c.mu.Lock()
+		in, fire := c.input, c.fire
+		c.fire = nil
+		c.mu.Unlock()
+
+		if !s.started || in == nil || c.unit.IsDestroyed() {
+			continue
+		}
+		g.applyNetInput(c.unit, in, fire)
+	}
+	s.clients = connected
+}
+
+// applyNetInput pilots the client unit from its input
+func (g *Game) applyNetInput(u model.Unit, in *NetInput, fire []int) {
+	u.SetTargetVelocity(in.TargetVelocity)
+	u.SetTargetVelocityZ(in.TargetVelocityZ)
+	u.SetTargetHeading(in.TargetHeading)
+	if u.HasTurret() {
+		u.SetTargetTurretAngle(in.TargetTurretAngle)
+	}
+	u.SetTargetPitch(in.TargetPitch)
+
+	if in.JumpJetsActive && u.JumpJets() > 0 && u.JumpJetDuration() < u.MaxJumpJetDuration() {
+		u.SetJumpJetsActive(true)
+		u.SetJumpJetsDirectional(in.JumpJetsDirectional)
+		u.SetJumpJetHeading(in.JumpJetHeading)
+	} else if u.JumpJetsActive() {
+		u.SetJumpJetsActive(false)
+	}
+
+	// only manual power changes are taken from the client, the server handles heat shutdown
+	switch {
+	case in.Powered == model.POWER_OFF_MANUAL && u.Powered() == model.POWER_ON:
+		u.SetPowered(model.POWER_OFF_MANUAL)
+	case (in.Powered == model.POWER_ON || in.Powered == model.POWER_ON_IN_PROGRESS) && u.Powered() == model.POWER_OFF_MANUAL:
+		u.SetPowered(model.POWER_ON)
+	}
+
+	var target model.Entity
+	if in.Target > 0 {
+		if s := g.spriteFromSequence(in.Target); s != nil && s.Unit() != nil && !s.IsDestroyed() {
+			target = s.Unit()
+		}
+	}
+	u.SetTarget(target)
+	u.SetTargetLock(in.TargetLock)
+
+	if u.Powered() != model.POWER_ON {
+		return
+	}
+	armament := u.Armament()
+	for _, i := range fire {
+		if i >= 0 && i < len(armament) {
+			g.fireUnitWeapon(u, armament[i])
+		}
+	}
+}
+
+// updateNavPoints checks for nav point visits by client units
+func (s *Server) updateNavPoints(g *Game) {
+	for _, c := range s.clients {
+		if !c.unit.IsDestroyed() {
+			g.visitNavPoints(c.unit.Pos())
+		}
+	}
+}
+
+// clientUnit returns the client whose unit is the entity, or nil if it is not a client unit
+func (s *Server) clientUnit(e model.Entity) *serverClient {
+	for _, c := range s.clients {
+		if c.unit == e {
+			return c
+		}
+	}
+	return nil
+}
+
+// snapshot returns the current state of the mission to send to clients
+func (s *Server) snapshot(g *Game) *NetSnapshot {
+	snapshot := &NetSnapshot{
+		Tick:           s.tick,
+		MissionSeconds: g.mission.TimerSeconds(),
+		Units:          make([]*NetUnit, 0, 64),
+	}
+	for _, nav := range g.mission.NavPoints {
+		snapshot.NavVisited = append(snapshot.NavVisited, nav.Visited())
+	}
+	for _, objective := range g.objectives.order {
+		snapshot.Objectives = append(snapshot.Objectives, &SaveGameObjective{
+			Completed: objective.Completed(),
+			Failed:    objective.Failed(),
+			Progress:  basicObjective(objective).progress,
+		})
+	}
+
+	sequences := make(map[model.Unit]uint64, 64)
+	for _, spriteType := range g.sprites.SpriteTypes() {
+		if !isInteractiveType(spriteType) {
+			continue
+		}
+		g.sprites.RangeByType(spriteType, func(k, v any) bool {
+			u := getSpriteFromInterface(k.(raycaster.Sprite)).Unit()
+			if u == nil {
+				return true
+			}
+			sequence := v.(uint64)
+			sequences[u] = sequence
+
+			nu := &NetUnit{
+				Sequence: sequence,
+				ID:       u.ID(),
+				Team:     u.Team(),
+				Player:   s.clientUnit(u) != nil,
+				State:    u.UnitMissionState(),
+			}
+			if m, ok := u.(*model.Mech); ok {
+				nu.Mech = model.TrimExtension(m.Resource.File)
+				nu.PowerOnTimer, nu.PowerOffTimer = m.PowerOnTimer, m.PowerOffTimer
+			}
+			snapshot.Units = append(snapshot.Units, nu)
+			return true
+		})
+	}
+
+	// projectiles in flight, except for those whose unit is no longer in play such as ejection pods
+	g.sprites.RangeByType(sprites.ProjectileSpriteType, func(k, v any) bool {
+		projectile := k.(*sprites.ProjectileSprite).Projectile
+		if projectile == nil || projectile.Lifespan() <= 0 {
+			return true
+		}
+
+		parent := model.EntityUnit(projectile.Parent())
+		parentSequence, inPlay := sequences[parent]
+		if !inPlay {
+			return true
+		}
+		weaponIndex := slices.Index(parent.Armament(), projectile.Weapon())
+		if weaponIndex < 0 {
+			return true
+		}
+
+		snapshot.Projectiles = append(snapshot.Projectiles, &NetProjectile{
+			Sequence: v.(uint64),
+			Parent:   parentSequence,
+			Weapon:   weaponIndex,
+			State:    projectile.ProjectileState(),
+		})
+		return true
+	})
+
+	return snapshot
+}
+
+// broadcast sends the current state of the mission to all clients
+func (s *Server) broadcast(g *Game) {
+	if len(s.clients) == 0 {
+		return
+	}
+
+	msgBytes, err := json.Marshal(&NetMessage{Snapshot: s.snapshot(g)})
+	if err != nil {
+		log.Error("error creating snapshot: ", err)
+		return
+	}
+	for _, c := range s.clients {
+		c.queue(msgBytes)
+	}
+}
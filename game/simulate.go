@@ -0,0 +1,108 @@
+package game
+
+import (
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+	"github.com/pixelmek-3d/pixelmek-3d/game/render/fonts"
+	"github.com/pixelmek-3d/pixelmek-3d/game/render/sprites"
+	"github.com/pixelmek-3d/pixelmek-3d/game/resources"
+	"github.com/pixelmek-3d/pixelmek-3d/game/texture"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SimulationResult is the outcome of a headless mission simulation
+type SimulationResult struct {
+	Status     ObjectivesStatus
+	Ticks      uint
+	Seconds    float64
+	Objectives string
+}
+
+// NewHeadlessGame initializes the game without the game loop, player input, or audio output
+// so a mission can be simulated without a window, where the same seed gives the same result
+func NewHeadlessGame(seed int64) *Game {
+	// seed before anything random is generated
+	model.SetRandomSeed(seed)
+
+	g := new(Game)
+	g.headless = true
+	g.initConfig()
+
+	// initialize common resources
+	resources.InitResources()
+
+	// fonts are still needed for nav point and HUD elements created with the mission
+	var err error
+	g.fonts, err = fonts.NewFontHandler(g.hudFont)
+	if err != nil {
+		log.Error("Error loading font handler:", err)
+		exit(1)
+	}
+
+	// audio handler is used throughout game updates, keep it muted
+	g.audio = NewAudioHandler()
+	g.audio.SetMusicVolume(0)
+	g.audio.SetSFXVolume(0)
+
+	g.initCombatVariables()
+	g.setRenderScale(g.renderScale)
+
+	g.resources, err = model.LoadModelResources()
+	if err != nil {
+		log.Error("Error loading models:", err)
+		exit(1)
+	}
+
+	g.tex = texture.NewTextureHandler(nil)
+	g.tex.SetRenderFloorTex(false)
+	g.sprites = sprites.NewSpriteHandler()
+
+	// sprites need to be updated in a consistent order for a deterministic simulation
+	g.sprites.SetOrdered(true)
+
+	return g
+}
+
+// Simulate runs the loaded mission with the player unit piloted by AI until the objectives
+// are completed or failed, or until maxTicks have passed when maxTicks is greater than zero
+func (g *Game) Simulate(maxTicks uint) *SimulationResult {
+	if !g.headless {
+		panic("simulate requires a game from NewHeadlessGame!")
+	}
+
+	g.initMission()
+
+	// mission time is based on ticks simulated instead of real time
+	g.mission.SetTickTimer(true)
+
+	// no player input, so the player unit is piloted by AI
+	g.player.autopilot = true
+	g.ai.NewUnitAI(g.player.Unit)
+
+	var tick uint
+	for ; g.InProgress() && (maxTicks == 0 || tick < maxTicks); tick++ {
+		g.simulateTick()
+	}
+
+	return &SimulationResult{
+		Status:     g.objectives.Status(),
+		Ticks:      tick,
+		Seconds:    g.mission.TimerSeconds(),
+		Objectives: g.objectives.Text(),
+	}
+}
+
+// simulateTick performs the logical updates for a single headless game tick
+func (g *Game) simulateTick() {
+	g.updateSpatialIndex()
+	g.updateSensors()
+	g.updateAI()
+	g.updatePlayer()
+	g.updateProjectiles()
+	g.UpdateSprites()
+	g.updateObjectives()
+
+	g.updateWeaponCooldowns(g.player.Unit)
+	g.updateHeatScale(g.player.Unit)
+	g.mission.TimerTick()
+}
@@ -0,0 +1,80 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+)
+
+const (
+	testSimulateMission = "trial_day.yaml"
+	testSimulateMech    = "timber_wolf_prime"
+	// long enough for combat, so critical hits, heat and aim jitter rolls are part of the result
+	testSimulateSeconds = 120
+)
+
+// simulation is the result of a simulated mission along with the end state of the player unit
+type simulation struct {
+	result *SimulationResult
+	player string
+}
+
+func simulateMission(t *testing.T, seed int64) *simulation {
+	t.Helper()
+
+	g := NewHeadlessGame(seed)
+	if _, err := g.LoadMission(testSimulateMission); err != nil {
+		t.Fatalf("error loading mission %s: %v", testSimulateMission, err)
+	}
+
+	unit := g.LoadUnit(model.MechResourceType, testSimulateMech)
+	if unit == nil {
+		t.Fatalf("error loading mech %s", testSimulateMech)
+	}
+	g.SetPlayerUnit(unit)
+
+	result := g.Simulate(uint(testSimulateSeconds * model.TICKS_PER_SECOND))
+
+	p := g.player.Unit
+	pos := p.Pos()
+	return &simulation{
+		result: result,
+		player: fmt.Sprintf("pos (%0.4f, %0.4f) armor %0.2f structure %0.2f heat %0.2f",
+			pos.X, pos.Y, p.ArmorPoints(), p.StructurePoints(), p.Heat()),
+	}
+}
+
+func TestSimulateSameSeed(t *testing.T) {
+	first := simulateMission(t, 42)
+	second := simulateMission(t, 42)
+
+	if first.result.Ticks != second.result.Ticks {
+		t.Errorf("ticks differ with the same seed: %d != %d", first.result.Ticks, second.result.Ticks)
+	}
+	if first.result.Status != second.result.Status {
+		t.Errorf("status differs with the same seed: %s != %s", first.result.Status, second.result.Status)
+	}
+	if first.result.Seconds != second.result.Seconds {
+		t.Errorf("seconds differ with the same seed: %v != %v", first.result.Seconds, second.result.Seconds)
+	}
+	if first.result.Objectives != second.result.Objectives {
+		t.Errorf("objectives differ with the same seed:\n%s\n!=\n%s", first.result.Objectives, second.result.Objectives)
+	}
+	if first.player != second.player {
+		t.Errorf("player differs with the same seed: %s != %s", first.player, second.player)
+	}
+}
+
+func TestSimulateDifferentSeed(t *testing.T) {
+	first := simulateMission(t, 42)
+	second := simulateMission(t, 7)
+
+	// the same mission may end the same way, but not with the player unit in the same state
+	if first.result.Ticks == second.result.Ticks &&
+		first.result.Status == second.result.Status &&
+		first.result.Objectives == second.result.Objectives &&
+		first.player == second.player {
+		t.Errorf("same result with different seeds: %s, %s", first.result.Status, first.player)
+	}
+}
@@ -0,0 +1,236 @@
+package game
+
+import (
+	"math"
+	"slices"
+
+	"github.com/harbdog/raycaster-go"
+	"github.com/harbdog/raycaster-go/geom"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+)
+
+const (
+	// SPATIAL_CELL_SIZE is the width and height in map cells of each spatial index grid cell
+	SPATIAL_CELL_SIZE float64 = 4
+)
+
+// spatialWall is a wall collision line with the lowest grid cell it is in, used to avoid
+// returning the same wall more than once from an area query
+type spatialWall struct {
+	line         *geom.Line
+	minCX, minCY int
+}
+
+// SpatialIndex is a uniform grid of wall collision lines and collision entities, so collision and
+// line of sight checks only test against what is nearby instead of everything in the mission
+type SpatialIndex struct {
+	cellSize float64
+	width    int
+	height   int
+
+	walls     []spatialWall
+	wallCells [][]int
+
+	entityCells [][]model.Entity
+	entityCell  map[model.Entity]int
+	maxRadius   float64
+}
+
+// NewSpatialIndex creates a spatial index covering the map size, where a cell size of zero or less
+// uses a single grid cell for the whole map which is the same as checking against everything
+func NewSpatialIndex(mapWidth, mapHeight int, cellSize float64) *SpatialIndex {
+	if cellSize <= 0 {
+		cellSize = math.Max(float64(max(mapWidth, mapHeight)), 1)
+	}
+	width := max(int(math.Ceil(float64(mapWidth)/cellSize)), 1)
+	height := max(int(math.Ceil(float64(mapHeight)/cellSize)), 1)
+
+	s := &SpatialIndex{
+		cellSize:    cellSize,
+		width:       width,
+		height:      height,
+		wallCells:   make([][]int, width*height),
+		entityCells: make([][]model.Entity, width*height),
+		entityCell:  make(map[model.Entity]int),
+	}
+	return s
+}
+
+// cellXY returns the grid cell coordinates of the position, clamped to the grid
+func (s *SpatialIndex) cellXY(x, y float64) (int, int) {
+	cx := int(math.Floor(x / s.cellSize))
+	cy := int(math.Floor(y / s.cellSize))
+	return max(0, min(cx, s.width-1)), max(0, min(cy, s.height-1))
+}
+
+func (s *SpatialIndex) cellIndex(cx, cy int) int {
+	return cy*s.width + cx
+}
+
+// SetWalls indexes the wall collision lines of the map
+func (s *SpatialIndex) SetWalls(lines []*geom.Line) {
+	s.walls = make([]spatialWall, 0, len(lines))
+	s.wallCells = make([][]int, s.width*s.height)
+
+	for i, line := range lines {
+		minCX, minCY := s.cellXY(math.Min(line.X1, line.X2), math.Min(line.Y1, line.Y2))
+		maxCX, maxCY := s.cellXY(math.Max(line.X1, line.X2), math.Max(line.Y1, line.Y2))
+		s.walls = append(s.walls, spatialWall{line: line, minCX: minCX, minCY: minCY})
+
+		for cy := minCY; cy <= maxCY; cy++ {
+			for cx := minCX; cx <= maxCX; cx++ {
+				c := s.cellIndex(cx, cy)
+				s.wallCells[c] = append(s.wallCells[c], i)
+			}
+		}
+	}
+}
+
+// ClearEntities removes all entities from the index
+func (s *SpatialIndex) ClearEntities() {
+	for i := range s.entityCells {
+		s.entityCells[i] = s.entityCells[i][:0]
+	}
+	clear(s.entityCell)
+	s.maxRadius = 0
+}
+
+// AddEntity indexes the entity by its current position
+func (s *SpatialIndex) AddEntity(e model.Entity) {
+	if _, ok := s.entityCell[e]; ok {
+		s.UpdateEntity(e)
+		return
+	}
+
+	pos := e.Pos()
+	c := s.cellIndex(s.cellXY(pos.X, pos.Y))
+	s.entityCells[c] = append(s.entityCells[c], e)
+	s.entityCell[e] = c
+	s.maxRadius = math.Max(s.maxRadius, e.CollisionRadius())
+}
+
+// UpdateEntity moves an indexed entity to the grid cell of its current position, if it has changed cells
+func (s *SpatialIndex) UpdateEntity(e model.Entity) {
+	prevCell, ok := s.entityCell[e]
+	if !ok {
+		return
+	}
+
+	pos := e.Pos()
+	c := s.cellIndex(s.cellXY(pos.X, pos.Y))
+	if c == prevCell {
+		return
+	}
+
+	if i := slices.Index(s.entityCells[prevCell], e); i >= 0 {
+		s.entityCells[prevCell] = slices.Delete(s.entityCells[prevCell], i, i+1)
+	}
+	s.entityCells[c] = append(s.entityCells[c], e)
+	s.entityCell[e] = c
+}
+
+// RangeWalls calls fn for each wall line in grid cells overlapping the area, each only once,
+// until fn returns false
+func (s *SpatialIndex) RangeWalls(minX, minY, maxX, maxY float64, fn func(line *geom.Line) bool) {
+	minCX, minCY := s.cellXY(minX, minY)
+	maxCX, maxCY := s.cellXY(maxX, maxY)
+
+	for cy := minCY; cy <= maxCY; cy++ {
+		for cx := minCX; cx <= maxCX; cx++ {
+			for _, i := range s.wallCells[s.cellIndex(cx, cy)] {
+				// only return a wall in multiple cells from the first of its cells within the area
+				w := s.walls[i]
+				if max(w.minCX, minCX) != cx || max(w.minCY, minCY) != cy {
+					continue
+				}
+				if !fn(w.line) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// RangeWallsOnLine calls fn for each wall line in grid cells the line passes through, until fn returns false.
+// A wall may be passed to fn more than once if the line passes through more than one of its cells.
+func (s *SpatialIndex) RangeWallsOnLine(line geom.Line, fn func(line *geom.Line) bool) {
+	x1, y1, x2, y2 := line.X1, line.Y1, line.X2, line.Y2
+	if x1 > x2 {
+		x1, y1, x2, y2 = x2, y2, x1, y1
+	}
+
+	minCX, _ := s.cellXY(x1, y1)
+	maxCX, _ := s.cellXY(x2, y2)
+	for cx := minCX; cx <= maxCX; cx++ {
+		// portion of the line within this column of grid cells
+		colX1 := math.Max(x1, float64(cx)*s.cellSize)
+		colX2 := math.Min(x2, float64(cx+1)*s.cellSize)
+		colY1, colY2 := y1, y2
+		if x2 != x1 {
+			colY1 = y1 + (colX1-x1)*(y2-y1)/(x2-x1)
+			colY2 = y1 + (colX2-x1)*(y2-y1)/(x2-x1)
+		}
+
+		_, minCY := s.cellXY(colX1, math.Min(colY1, colY2))
+		_, maxCY := s.cellXY(colX2, math.Max(colY1, colY2))
+		for cy := minCY; cy <= maxCY; cy++ {
+			for _, i := range s.wallCells[s.cellIndex(cx, cy)] {
+				if !fn(s.walls[i].line) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// RangeEntities calls fn for each entity with a collision radius that may overlap the area,
+// until fn returns false
+func (s *SpatialIndex) RangeEntities(minX, minY, maxX, maxY float64, fn func(e model.Entity) bool) {
+	// entities are indexed by their center, so expand the area by the largest entity collision radius
+	minCX, minCY := s.cellXY(minX-s.maxRadius, minY-s.maxRadius)
+	maxCX, maxCY := s.cellXY(maxX+s.maxRadius, maxY+s.maxRadius)
+
+	for cy := minCY; cy <= maxCY; cy++ {
+		for cx := minCX; cx <= maxCX; cx++ {
+			for _, e := range s.entityCells[s.cellIndex(cx, cy)] {
+				if !fn(e) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// initSpatialIndex creates the spatial index for the mission map walls
+func (g *Game) initSpatialIndex() {
+	cellSize := SPATIAL_CELL_SIZE
+	if g.spatialDisabled {
+		// a single grid cell checks against everything, only used to benchmark against
+		cellSize = 0
+	}
+	g.spatial = NewSpatialIndex(g.mapWidth, g.mapHeight, cellSize)
+	g.spatial.SetWalls(g.collisionMap)
+	g.updateSpatialIndex()
+}
+
+// updateSpatialIndex rebuilds the index of collision entities at the start of each tick, since sprites
+// can be added, removed, or moved outside of the game update, such as from mission triggers
+func (g *Game) updateSpatialIndex() {
+	if g.spatial == nil {
+		return
+	}
+
+	g.spatial.ClearEntities()
+	for _, spriteType := range g.sprites.SpriteTypes() {
+		if !g.isCollisionType(spriteType) {
+			continue
+		}
+		g.sprites.RangeByType(spriteType, func(k, _ any) bool {
+			e := getEntityFromInterface(k.(raycaster.Sprite))
+			if e.CollisionRadius() > 0 {
+				g.spatial.AddEntity(e)
+			}
+			return true
+		})
+	}
+}
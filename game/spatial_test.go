@@ -0,0 +1,132 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/harbdog/raycaster-go/geom"
+)
+
+const testSpatialMapSize = 16
+
+// newSpatialTestWalls creates the wall collision lines of a map with a border, walls on and across
+// grid cell boundaries, and long walls passing through many grid cells
+func newSpatialTestWalls() []*geom.Line {
+	rects := [][]geom.Line{
+		geom.Rect(0, 0, testSpatialMapSize, testSpatialMapSize),
+		geom.Rect(1, 1, 1, 1),
+		geom.Rect(3, 3, 1, 1),
+		geom.Rect(7, 7, 2, 2),
+		geom.Rect(11, 2, 1, 6),
+	}
+	lines := []geom.Line{
+		{X1: 2, Y1: 6, X2: 14, Y2: 6},
+		{X1: 1.5, Y1: 14.5, X2: 14.5, Y2: 9.5},
+	}
+
+	walls := make([]*geom.Line, 0, 32)
+	for _, rect := range rects {
+		for i := range rect {
+			walls = append(walls, &rect[i])
+		}
+	}
+	for i := range lines {
+		walls = append(walls, &lines[i])
+	}
+	return walls
+}
+
+func newSpatialTestIndex(walls []*geom.Line) *SpatialIndex {
+	s := NewSpatialIndex(testSpatialMapSize, testSpatialMapSize, SPATIAL_CELL_SIZE)
+	s.SetWalls(walls)
+	return s
+}
+
+func TestSpatialRangeWalls(t *testing.T) {
+	walls := newSpatialTestWalls()
+	s := newSpatialTestIndex(walls)
+
+	tests := []struct {
+		name                   string
+		minX, minY, maxX, maxY float64
+	}{
+		{"whole map", 0, 0, testSpatialMapSize, testSpatialMapSize},
+		{"single cell", 0.5, 0.5, 3.5, 3.5},
+		{"across cell boundaries", 3, 3, 9, 9},
+		{"middle cells of long walls", 5, 5, 11, 11},
+		{"on a cell boundary", 8, 8, 8, 8},
+		{"out of bounds", -8, -8, 24, 24},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := make(map[*geom.Line]int)
+			s.RangeWalls(tt.minX, tt.minY, tt.maxX, tt.maxY, func(line *geom.Line) bool {
+				found[line]++
+				return true
+			})
+
+			for _, w := range walls {
+				if found[w] > 1 {
+					t.Errorf("wall %v returned %d times", w, found[w])
+				}
+				// any wall with bounds overlapping the area must be returned
+				overlaps := min(w.X1, w.X2) <= tt.maxX && max(w.X1, w.X2) >= tt.minX &&
+					min(w.Y1, w.Y2) <= tt.maxY && max(w.Y1, w.Y2) >= tt.minY
+				if overlaps && found[w] == 0 {
+					t.Errorf("wall %v overlapping the area not returned", w)
+				}
+			}
+		})
+	}
+}
+
+func TestSpatialRangeWallsOnLine(t *testing.T) {
+	walls := newSpatialTestWalls()
+	s := newSpatialTestIndex(walls)
+
+	tests := []struct {
+		name string
+		line geom.Line
+	}{
+		{"diagonal", geom.Line{X1: 0.5, Y1: 0.5, X2: 15.5, Y2: 12.3}},
+		{"diagonal reversed", geom.Line{X1: 15.5, Y1: 12.3, X2: 0.5, Y2: 0.5}},
+		{"steep diagonal", geom.Line{X1: 9.5, Y1: 0.5, X2: 6.5, Y2: 15.5}},
+		{"vertical", geom.Line{X1: 7.5, Y1: 0.5, X2: 7.5, Y2: 15.5}},
+		{"vertical on a cell boundary", geom.Line{X1: 8, Y1: 15.5, X2: 8, Y2: 0.5}},
+		{"horizontal", geom.Line{X1: 15.5, Y1: 7.5, X2: 0.5, Y2: 7.5}},
+		{"within a cell", geom.Line{X1: 0.5, Y1: 1.5, X2: 2.5, Y2: 1.5}},
+		{"out of bounds", geom.Line{X1: -4, Y1: -2, X2: 20, Y2: 18}},
+		{"out of bounds crossing a corner", geom.Line{X1: -3, Y1: 8, X2: 8, Y2: -3}},
+		{"out of bounds vertical", geom.Line{X1: 11.5, Y1: -10, X2: 11.5, Y2: 30}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := make(map[*geom.Line]bool)
+			s.RangeWallsOnLine(tt.line, func(line *geom.Line) bool {
+				if _, _, ok := geom.LineIntersection(tt.line, *line); ok {
+					found[line] = true
+				}
+				return true
+			})
+
+			// the same walls need to be found as checking against every wall
+			want := 0
+			for _, w := range walls {
+				if _, _, ok := geom.LineIntersection(tt.line, *w); !ok {
+					continue
+				}
+				want++
+				if !found[w] {
+					t.Errorf("wall %v intersecting the line not found", w)
+				}
+			}
+			if want == 0 {
+				t.Error("line does not intersect any walls")
+			}
+			if len(found) != want {
+				t.Errorf("found %d intersecting walls, want %d", len(found), want)
+			}
+		})
+	}
+}
@@ -99,3 +99,20 @@ func spawnRandomUnit[T model.AnyUnitModel](g *Game) *T {
 
 	return spawnMissionUnit[T](g, unit)
 }
+
+// addUnitSprite creates the sprite of a unit added after the mission started and adds it to the game
+func (g *Game) addUnitSprite(u model.Unit) error {
+	switch s := g.CreateUnitSprite(u).(type) {
+	case *sprites.MechSprite:
+		g.sprites.AddMechSprite(s)
+	case *sprites.VehicleSprite:
+		g.sprites.AddVehicleSprite(s)
+	case *sprites.InfantrySprite:
+		g.sprites.AddInfantrySprite(s)
+	case *sprites.VTOLSprite:
+		g.sprites.AddVTOLSprite(s)
+	default:
+		return fmt.Errorf("unit sprite type not implemented: %T", s)
+	}
+	return nil
+}
@@ -135,6 +135,12 @@ func (g *Game) getRaycastSprites() []raycaster.Sprite {
 		count++
 	}
 
+	// add guides along the planned path of the AI shown in the debug overlay
+	for _, guide := range g.pathGuides() {
+		raycastSprites = append(raycastSprites, guide)
+		count++
+	}
+
 	if g.player.DebugCameraTarget() != nil {
 		// add player sprite to be raycasted only when camera attached to a target
 		raycastSprites = append(raycastSprites, g.player.sprite)
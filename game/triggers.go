@@ -0,0 +1,249 @@
+package game
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/harbdog/raycaster-go/geom"
+	"github.com/pixelmek-3d/pixelmek-3d/game/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	TRIGGER_MESSAGE_SECONDS float64 = 5.0
+)
+
+type TriggersHandler struct {
+	triggers []*model.MissionTrigger
+	isFired  []bool
+
+	// indices of triggers in the order they fired, for saving and restoring
+	fired []int
+
+	// reinforcement units spawned by triggers, keyed by trigger index and unit index within the trigger
+	reinforcements map[model.Unit][2]int
+
+	message        string
+	messageSeconds float64
+}
+
+func NewTriggersHandler(triggers []*model.MissionTrigger) *TriggersHandler {
+	return &TriggersHandler{
+		triggers:       triggers,
+		isFired:        make([]bool, len(triggers)),
+		fired:          make([]int, 0, len(triggers)),
+		reinforcements: make(map[model.Unit][2]int),
+	}
+}
+
+// Update fires each trigger that has not yet fired once all of its conditions are met
+func (t *TriggersHandler) Update(g *Game) {
+	for i, trigger := range t.triggers {
+		if t.isFired[i] || !t.conditionsMet(g, &trigger.Conditions) {
+			continue
+		}
+
+		log.Debugf("mission trigger fired: [%d] %s", i, trigger.ID)
+		t.fire(g, i, false)
+	}
+}
+
+// Message returns the current HUD banner message from a trigger, if any
+func (t *TriggersHandler) Message(g *Game) string {
+	if len(t.message) == 0 || g.mission.TimerSeconds() > t.messageSeconds {
+		return ""
+	}
+	return t.message
+}
+
+// showMessage shows text in the HUD banner for a number of seconds, or a default duration if zero
+func (t *TriggersHandler) showMessage(g *Game, message string, seconds float64) {
+	if seconds == 0 {
+		seconds = TRIGGER_MESSAGE_SECONDS
+	}
+	t.message = message
+	t.messageSeconds = g.mission.TimerSeconds() + seconds
+}
+
+func (t *TriggersHandler) conditionsMet(g *Game, c *model.MissionTriggerConditions) bool {
+	if c.TimeElapsed > 0 && g.mission.TimerSeconds() < c.TimeElapsed {
+		return false
+	}
+
+	if len(c.UnitDestroyed) > 0 {
+		u := g.getSpriteUnitByID(c.UnitDestroyed)
+		if u != nil && !u.IsDestroyed() {
+			return false
+		}
+	}
+
+	if len(c.NavPointVisited) > 0 {
+		visited := false
+		for _, nav := range g.mission.NavPoints {
+			if nav.Name == c.NavPointVisited {
+				visited = nav.Visited()
+				break
+			}
+		}
+		if !visited {
+			return false
+		}
+	}
+
+	if c.AreaEntered != nil {
+		u := t.conditionUnit(g, c.AreaEntered.Unit)
+		if u == nil || u.IsDestroyed() {
+			return false
+		}
+		uPos := u.Pos()
+		aX, aY := c.AreaEntered.Position[0], c.AreaEntered.Position[1]
+		if geom.Distance(uPos.X, uPos.Y, aX, aY) > c.AreaEntered.Radius {
+			return false
+		}
+	}
+
+	if c.UnitHealth != nil {
+		u := t.conditionUnit(g, c.UnitHealth.Unit)
+		if u != nil && !u.IsDestroyed() {
+			hp, maxHP := u.ArmorPoints()+u.StructurePoints(), u.MaxArmorPoints()+u.MaxStructurePoints()
+			if maxHP > 0 && 100*hp/maxHP >= c.UnitHealth.Below {
+				return false
+			}
+		}
+	}
+
+	if len(c.ObjectiveCompleted) > 0 {
+		if g.objectives == nil || !g.objectives.ObjectiveCompleted(c.ObjectiveCompleted) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// conditionUnit returns the unit by ID, or the player unit if no ID given
+func (t *TriggersHandler) conditionUnit(g *Game, unitID string) model.Unit {
+	if len(unitID) == 0 {
+		return g.player.Unit
+	}
+	return g.getSpriteUnitByID(unitID)
+}
+
+// fire performs the trigger actions, when restoring a saved game only the lasting changes are performed
+func (t *TriggersHandler) fire(g *Game, index int, restoring bool) {
+	t.isFired[index] = true
+	t.fired = append(t.fired, index)
+
+	trigger := t.triggers[index]
+	unitIndex := 0
+	for _, a := range trigger.Actions {
+		if a.Spawn != nil {
+			units := t.spawnReinforcements(g, index, a.Spawn, &unitIndex)
+			if g.ai != nil {
+				g.ai.AddReinforcementAI(units)
+			}
+		}
+
+		if a.AddObjectives != nil {
+			g.objectives.AddObjectives(g, a.AddObjectives)
+		}
+
+		if a.Lighting != nil {
+			g.setLightFalloff(a.Lighting.Falloff)
+			g.setGlobalIllumination(a.Lighting.Illumination)
+			g.setLightRGB(a.Lighting.LightRGB())
+		}
+
+		if restoring {
+			// AI changes are restored after the AI handler is recreated, others are not lasting changes
+			continue
+		}
+
+		if a.AI != nil {
+			t.setUnitAI(g, a.AI)
+		}
+
+		if len(a.CompleteObjective) > 0 {
+			g.objectives.CompleteObjective(a.CompleteObjective)
+		}
+
+		if len(a.Message) > 0 {
+			t.showMessage(g, a.Message, a.MessageSeconds)
+		}
+
+		if len(a.Audio) > 0 && g.audio != nil {
+			go g.audio.PlaySFX(path.Join("audio/sfx", a.Audio), 1.0, 0)
+		}
+	}
+}
+
+func (t *TriggersHandler) setUnitAI(g *Game, ai *model.MissionTriggerAI) {
+	u := g.getSpriteUnitByID(ai.Unit)
+	if u == nil || u.IsDestroyed() {
+		log.Debugf("trigger AI unit not in play: %s", ai.Unit)
+		return
+	}
+	if err := g.ai.SetUnitBehaviorTree(u, ai.Tree); err != nil {
+		log.Error(err)
+	}
+}
+
+func (t *TriggersHandler) spawnReinforcements(g *Game, index int, spawn *model.MissionReinforcements, unitIndex *int) []model.Unit {
+	units := make([]model.Unit, 0, len(spawn.Mechs)+len(spawn.Vehicles)+len(spawn.Infantry)+len(spawn.VTOLs))
+	addUnit := func(u model.Unit, err error) {
+		// unit index is counted even if not spawned so the index of each unit is always the same
+		key := [2]int{index, *unitIndex}
+		*unitIndex++
+
+		if err != nil {
+			log.Errorf("error spawning reinforcement unit: %v", err)
+			return
+		}
+
+		if err := g.addUnitSprite(u); err != nil {
+			log.Errorf("error spawning reinforcement unit: %v", err)
+			return
+		}
+
+		t.reinforcements[u] = key
+		units = append(units, u)
+	}
+
+	for _, missionUnit := range spawn.Mechs {
+		addUnit(createMissionUnitModel[model.Mech](g, missionUnit))
+	}
+	for _, missionUnit := range spawn.Vehicles {
+		addUnit(createMissionUnitModel[model.Vehicle](g, missionUnit))
+	}
+	for _, missionUnit := range spawn.Infantry {
+		addUnit(createMissionUnitModel[model.Infantry](g, missionUnit))
+	}
+	for _, missionUnit := range spawn.VTOLs {
+		addUnit(createMissionFlyingUnitModel[model.VTOL](g, missionUnit))
+	}
+
+	return units
+}
+
+// restore fires the triggers that fired before the game was saved, in the same order
+func (t *TriggersHandler) restore(g *Game, fired []int) error {
+	for _, index := range fired {
+		if index < 0 || index >= len(t.triggers) || t.isFired[index] {
+			return fmt.Errorf("invalid fired trigger index %d", index)
+		}
+		t.fire(g, index, true)
+	}
+	return nil
+}
+
+// restoreAI changes the behavior trees of unit AI from triggers that fired before the game was saved
+func (t *TriggersHandler) restoreAI(g *Game) {
+	for _, index := range t.fired {
+		for _, a := range t.triggers[index].Actions {
+			if a.AI != nil {
+				t.setUnitAI(g, a.AI)
+			}
+		}
+	}
+}
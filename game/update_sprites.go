@@ -53,6 +53,11 @@ func (g *Game) updateSprite(spriteType sprites.SpriteType, sInterface raycaster.
 				// spawn ejection pod
 				g.spawnEjectionPod(s.Sprite)
 
+				if g.campaign != nil && !g.IsFriendly(g.player.Unit, sUnit) {
+					// destroyed enemy mechs can be salvaged after the mission
+					g.campaign.recordSalvage(sUnit)
+				}
+
 			} else if s.LoopCounter() >= 1 {
 				// delete when animation is over
 				g.sprites.DeleteMechSprite(s)
@@ -72,6 +77,7 @@ func (g *Game) updateSprite(spriteType sprites.SpriteType, sInterface raycaster.
 		g.updateUnitPosition(mech)
 		s.Update(g.player.CameraPosXY())
 		g.updateWeaponCooldowns(sUnit)
+		g.updateHeatScale(sUnit)
 
 		if sUnit.Powered() != model.POWER_ON {
 			poweringOn := sUnit.Powered() == model.POWER_ON_IN_PROGRESS
@@ -179,7 +185,7 @@ func (g *Game) updateSprite(spriteType sprites.SpriteType, sInterface raycaster.
 
 				// use the destroy counter to determine which effects to spawn
 				s.SetDestroyCounter(1)
-			} else if s.PosZ() <= 0 {
+			} else if s.PosZ() <= g.mission.Map().HeightAt(s.Pos().X, s.Pos().Y) {
 				// instantly delete if it gets below the ground
 				g.sprites.DeleteVTOLSprite(s)
 				break
@@ -266,6 +272,7 @@ func (g *Game) updateSpritePosition(s *sprites.Sprite) bool {
 		} else {
 			s.SetPos(newPos)
 			s.SetPosZ(newPosZ)
+			g.spatial.UpdateEntity(s.Entity)
 		}
 	}
 	return false
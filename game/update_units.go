@@ -8,23 +8,41 @@ import (
 )
 
 func (g *Game) updateUnitPosition(u model.Unit) {
+	if g.client != nil && g.client.interpolate(u) {
+		// units piloted by the multiplayer server move between snapshots
+		return
+	}
+
+	m := g.mission.Map()
+	position := u.Pos()
+	u.SetGroundZ(m.HeightAt(position.X, position.Y))
+
 	if u.Update() {
-		position, posZ := u.Pos(), u.PosZ()
+		posZ := u.PosZ()
 		velocity, velocityZ := u.Velocity(), u.VelocityZ()
 
 		moveHeading := u.Heading()
-		if u.JumpJetsActive() || (posZ > 0 && u.JumpJets() > 0) {
+		if u.JumpJetsActive() || (posZ > u.GroundZ() && u.JumpJets() > 0) {
 			// while jumping, or still in air after jumping, continue from last jump jet active heading and velocity
 			moveHeading = u.JumpJetHeading()
 			velocity = u.JumpJetVelocity()
 		}
 		moveLine := geom.LineFromAngle(position.X, position.Y, moveHeading, velocity)
 		moveX, moveY, moveZ := moveLine.X2, moveLine.Y2, posZ+velocityZ
+		if u.UnitType() != model.VTOLUnitType && !u.JumpJetsActive() && posZ <= u.GroundZ() {
+			// units on the ground follow the terrain elevation up and down ramps and platforms,
+			// but stay at their height to collide with terrain too high to step up onto without jumping
+			if m.CanStepUp(posZ, moveX, moveY) {
+				moveZ = m.HeightAt(moveX, moveY)
+			}
+		}
 
 		newPos, newPosZ, isCollision, collisions := g.getValidMove(u, moveX, moveY, moveZ, true)
 		if !(newPos.Equals(position) && newPosZ == posZ) {
 			u.SetPos(newPos)
 			u.SetPosZ(newPosZ)
+			u.SetGroundZ(m.HeightAt(newPos.X, newPos.Y))
+			g.spatial.UpdateEntity(u)
 			//log.Debugf("[%s] unit moved %0.4f (%v -> %v) heading @ %0.3f", u.ID(), geom.Distance(position.X, position.Y, newPos.X, newPos.Y), position, newPos, moveHeading)
 		}
 
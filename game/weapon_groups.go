@@ -92,31 +92,42 @@ func saveUserWeaponGroups() error {
 
 func setUnitWeaponGroups(unit model.Unit, weaponGroups [][]model.Weapon) {
 	unitKey := _getUnitWeaponsKey(unit)
-
-	unitWeaponGroups := make([][]model.WeaponGroup, len(unit.Armament()))
-	for i, w := range unit.Armament() {
-		unitWeaponGroups[i] = model.GetGroupsForWeapon(w, weaponGroups)
-	}
 	userWeaponGroups[unitKey] = _unitWeaponGroups{
-		WeaponGroups: unitWeaponGroups,
+		WeaponGroups: weaponGroupsByIndex(unit, weaponGroups),
 	}
 }
 
 func getUnitWeaponGroups(unit model.Unit) [][]model.Weapon {
 	unitKey := _getUnitWeaponsKey(unit)
 
+	var unitWeaponGroups [][]model.WeaponGroup
+	if wg, ok := userWeaponGroups[unitKey]; ok {
+		// restore saved weapon groups
+		unitWeaponGroups = wg.WeaponGroups
+	}
+	return weaponGroupsFromIndex(unit, unitWeaponGroups)
+}
+
+// weaponGroupsByIndex converts weapon groups to lists of group indices by weapon index
+func weaponGroupsByIndex(unit model.Unit, weaponGroups [][]model.Weapon) [][]model.WeaponGroup {
+	unitWeaponGroups := make([][]model.WeaponGroup, len(unit.Armament()))
+	for i, w := range unit.Armament() {
+		unitWeaponGroups[i] = model.GetGroupsForWeapon(w, weaponGroups)
+	}
+	return unitWeaponGroups
+}
+
+// weaponGroupsFromIndex converts lists of group indices by weapon index back to weapon groups
+func weaponGroupsFromIndex(unit model.Unit, unitWeaponGroups [][]model.WeaponGroup) [][]model.Weapon {
 	weaponGroups := make([][]model.Weapon, NumWeaponGroups)
 	for i := 0; i < cap(weaponGroups); i++ {
 		weaponGroups[i] = make([]model.Weapon, 0, len(unit.Armament()))
 	}
 
-	if wg, ok := userWeaponGroups[unitKey]; ok {
-		// restore saved weapon groups
-		for weaponIndex, groups := range wg.WeaponGroups {
-			weapon := unit.Armament()[weaponIndex]
-			for _, groupIndex := range groups {
-				weaponGroups[groupIndex] = append(weaponGroups[groupIndex], weapon)
-			}
+	for weaponIndex, groups := range unitWeaponGroups {
+		weapon := unit.Armament()[weaponIndex]
+		for _, groupIndex := range groups {
+			weaponGroups[groupIndex] = append(weaponGroups[groupIndex], weapon)
 		}
 	}
 	return weaponGroups
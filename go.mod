@@ -22,6 +22,7 @@ require (
 	github.com/spf13/viper v1.21.0
 	github.com/tinne26/etxt v0.0.9
 	github.com/wk8/go-ordered-map/v2 v2.1.8
+	go.starlark.net v0.0.0-20260908191801-89a6a09411d5
 	golang.org/x/image v0.43.0
 	gopkg.in/yaml.v3 v3.0.1
 )